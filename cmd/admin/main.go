@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/internal/db"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+const usage = `usage: admin <command> <email>
+
+commands:
+  grant       give the user admin rights
+  revoke      take the user's admin rights away
+
+The change applies to tokens issued from the next login on.`
+
+func main() {
+	if len(os.Args) != 3 {
+		log.Fatalln(usage)
+	}
+
+	var admin bool
+	switch os.Args[1] {
+	case "grant":
+		admin = true
+	case "revoke":
+		admin = false
+	default:
+		log.Fatalln(usage)
+	}
+
+	conn, err := db.Open(config.DbCfg())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	users := repository.NewGormRepositories(conn).Users
+
+	user, err := users.FindByEmail(os.Args[2])
+	if err != nil {
+		log.Fatalf("no user with email %s: %v\n", os.Args[2], err)
+	}
+	user.Admin = admin
+	if err := users.Update(user); err != nil {
+		log.Fatalln(err)
+	}
+
+	if admin {
+		fmt.Printf("%s is now an admin\n", user.Email)
+	} else {
+		fmt.Printf("%s is no longer an admin\n", user.Email)
+	}
+}
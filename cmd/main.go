@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/internal/db"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/internal/jobs"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/routes"
 
 	"github.com/gofiber/fiber/v2"
@@ -31,17 +34,27 @@ func main() {
 	cfg := config.DbCfg()
 
 	// Initialize the database
-	db.ConnectDB(cfg)
-
-	// Check if the database connection was successful
-	if db.GetDB() != nil {
-		log.Println("Successfully connected to the database")
-	} else {
-		log.Fatalln("Failed to connect to the database")
+	if err := db.ConnectDB(cfg); err != nil {
+		log.Fatalln(err)
 	}
+	log.Println("Successfully connected to the database")
+
+	repos := repository.NewGormRepositories(db.GetDB())
+
+	// Purge the trash in the background
+	trashCfg := config.TrashCfg()
+	go jobs.NewTrashPurger(repos, trashCfg.Retention, trashCfg.PurgeInterval).Run(context.Background())
+
+	// Release expired stock reservations in the background
+	reservationCfg := config.ReservationCfg()
+	go jobs.NewReservationSweeper(repos, reservationCfg.SweepInterval).Run(context.Background())
+
+	// Apply scheduled prices in the background
+	pricingCfg := config.PricingCfg()
+	go jobs.NewPriceScheduler(repos, pricingCfg.ScheduleInterval).Run(context.Background())
 
 	// Setup routes
-	routes.AppRoutes(app)
+	routes.AppRoutes(app, repos)
 
 	// Setup swagger middleware
 	app.Get("/swagger/*", swagger.HandlerDefault)
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/internal/db"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up          apply all pending migrations
+  down [n]    roll back the last n applied migrations (default 1)
+  status      list migrations and whether they have been applied`
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalln(usage)
+	}
+
+	// Load your configuration; migrations are driven explicitly here
+	cfg := config.DbCfg()
+	cfg.DBAutoMigrate = false
+
+	conn, err := db.Open(cfg)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	migrator, err := db.NewMigrator(conn, db.DriverName(cfg))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	switch os.Args[1] {
+	case "up":
+		ran, err := migrator.Up()
+		for _, m := range ran {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(ran) == 0 {
+			fmt.Println("no pending migrations")
+		}
+
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				log.Fatalln("down expects a positive number of steps")
+			}
+		}
+
+		rolledBack, err := migrator.Down(steps)
+		for _, m := range rolledBack {
+			fmt.Printf("rolled back %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("no applied migrations")
+		}
+
+	case "status":
+		states, err := migrator.Status()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		for _, s := range states {
+			status := "pending"
+			if s.Applied {
+				status = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, status)
+		}
+
+	default:
+		log.Fatalln(usage)
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -10,17 +12,69 @@ import (
 // Config stores all configuration of the application.
 // The values are read by godotenv from a .env file.
 type Config struct {
+    DBDriver   string
     DBUsername string
     DBPassword string
     DBHost     string
     DBName     string
     DBPort     string
+
+    // DBAutoMigrate applies pending migrations when the app starts
+    DBAutoMigrate bool
 }
 
 type JwtConfig struct {
     SecretKey string
 }
 
+// TrashConfig controls how long soft-deleted records are kept.
+type TrashConfig struct {
+    Retention     time.Duration
+    PurgeInterval time.Duration
+}
+
+// CurrencyConfig names the currency exchange rates are quoted against.
+type CurrencyConfig struct {
+    Base string
+}
+
+// ReservationConfig controls how long stock reservations are held.
+type ReservationConfig struct {
+    DefaultTTL    time.Duration
+    MaxTTL        time.Duration
+    SweepInterval time.Duration
+}
+
+// PricingConfig controls how often scheduled prices are applied.
+type PricingConfig struct {
+    ScheduleInterval time.Duration
+}
+
+// PaymentConfig configures the payment provider and its webhook.
+type PaymentConfig struct {
+    WebhookSecret string
+    WebhookURL    string
+}
+
+// TaxConfig controls how prices are taxed.
+type TaxConfig struct {
+    // Region is where customers are taken to be when they do not say
+    Region string
+
+    // PricesIncludeTax says whether product prices already contain tax
+    // or have it added on top
+    PricesIncludeTax bool
+}
+
+// CompanyConfig holds the company details printed on invoices and
+// packing slips.
+type CompanyConfig struct {
+    Name    string
+    Address string
+    Email   string
+    TaxID   string
+}
+
 // LoadConfig reads configuration from .env file and environment variables.
 func DbCfg() Config {
     err := godotenv.Load()
@@ -29,11 +83,14 @@ func DbCfg() Config {
     }
 
     return Config{
+        DBDriver:   os.Getenv("DB_DRIVER"),
         DBUsername: os.Getenv("DB_USERNAME"),
         DBPassword: os.Getenv("DB_PASSWORD"),
         DBHost:     os.Getenv("DB_HOST"),
         DBName:     os.Getenv("DB_NAME"),
         DBPort:     os.Getenv("DB_PORT"),
+
+        DBAutoMigrate: os.Getenv("DB_AUTO_MIGRATE") == "true",
     }
 }
 
@@ -47,3 +104,129 @@ func JwtCfg() JwtConfig {
         SecretKey: os.Getenv("JWT_SECRET_KEY"),
     }
 }
+
+// TrashCfg reads the trash retention settings. TRASH_RETENTION and
+// TRASH_PURGE_INTERVAL accept Go durations such as "720h".
+func TrashCfg() TrashConfig {
+    err := godotenv.Load()
+    if err != nil {
+        log.Fatal("Error loading .env file")
+    }
+
+    return TrashConfig{
+        Retention:     durationEnv("TRASH_RETENTION", 30*24*time.Hour),
+        PurgeInterval: durationEnv("TRASH_PURGE_INTERVAL", time.Hour),
+    }
+}
+
+// ReservationCfg reads the stock reservation settings. RESERVATION_TTL is
+// used when a request does not ask for a TTL, RESERVATION_MAX_TTL caps it
+// and RESERVATION_SWEEP_INTERVAL is how often expired holds are released.
+func ReservationCfg() ReservationConfig {
+    err := godotenv.Load()
+    if err != nil {
+        log.Fatal("Error loading .env file")
+    }
+
+    return ReservationConfig{
+        DefaultTTL:    durationEnv("RESERVATION_TTL", 15*time.Minute),
+        MaxTTL:        durationEnv("RESERVATION_MAX_TTL", 24*time.Hour),
+        SweepInterval: durationEnv("RESERVATION_SWEEP_INTERVAL", time.Minute),
+    }
+}
+
+// PricingCfg reads the pricing settings. PRICE_SCHEDULE_INTERVAL is how
+// often due scheduled prices are applied.
+func PricingCfg() PricingConfig {
+    err := godotenv.Load()
+    if err != nil {
+        log.Fatal("Error loading .env file")
+    }
+
+    return PricingConfig{
+        ScheduleInterval: durationEnv("PRICE_SCHEDULE_INTERVAL", time.Minute),
+    }
+}
+
+// CurrencyCfg reads the currency settings. BASE_CURRENCY is the ISO 4217
+// code every exchange rate is relative to.
+func CurrencyCfg() CurrencyConfig {
+    err := godotenv.Load()
+    if err != nil {
+        log.Fatal("Error loading .env file")
+    }
+
+    base := strings.ToUpper(os.Getenv("BASE_CURRENCY"))
+    if base == "" {
+        base = "USD"
+    }
+    return CurrencyConfig{Base: base}
+}
+
+// PaymentCfg reads the payment settings. PAYMENT_WEBHOOK_SECRET signs and
+// verifies webhook events and PAYMENT_WEBHOOK_URL is where the mock
+// provider posts them.
+func PaymentCfg() PaymentConfig {
+    err := godotenv.Load()
+    if err != nil {
+        log.Fatal("Error loading .env file")
+    }
+
+    webhookURL := os.Getenv("PAYMENT_WEBHOOK_URL")
+    if webhookURL == "" {
+        webhookURL = "http://localhost:3000/api/payments/webhook"
+    }
+    return PaymentConfig{
+        WebhookSecret: os.Getenv("PAYMENT_WEBHOOK_SECRET"),
+        WebhookURL:    webhookURL,
+    }
+}
+
+// CompanyCfg reads the company details. COMPANY_NAME, COMPANY_ADDRESS,
+// COMPANY_EMAIL and COMPANY_TAX_ID are printed on every invoice.
+func CompanyCfg() CompanyConfig {
+    err := godotenv.Load()
+    if err != nil {
+        log.Fatal("Error loading .env file")
+    }
+
+    return CompanyConfig{
+        Name:    os.Getenv("COMPANY_NAME"),
+        Address: os.Getenv("COMPANY_ADDRESS"),
+        Email:   os.Getenv("COMPANY_EMAIL"),
+        TaxID:   os.Getenv("COMPANY_TAX_ID"),
+    }
+}
+
+// TaxCfg reads the tax settings. TAX_REGION is the default region prices
+// are taxed for and TAX_PRICES_INCLUDE_TAX, "true" or "false", whether
+// product prices are entered with tax included.
+func TaxCfg() TaxConfig {
+    err := godotenv.Load()
+    if err != nil {
+        log.Fatal("Error loading .env file")
+    }
+
+    region := strings.ToUpper(strings.TrimSpace(os.Getenv("TAX_REGION")))
+    if region == "" {
+        region = "US"
+    }
+    return TaxConfig{
+        Region:           region,
+        PricesIncludeTax: os.Getenv("TAX_PRICES_INCLUDE_TAX") == "true",
+    }
+}
+
+// durationEnv parses a duration variable, falling back when it is unset.
+func durationEnv(key string, fallback time.Duration) time.Duration {
+    value := os.Getenv(key)
+    if value == "" {
+        return fallback
+    }
+
+    d, err := time.ParseDuration(value)
+    if err != nil {
+        log.Fatalf("Invalid %s: %v", key, err)
+    }
+    return d
+}
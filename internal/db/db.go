@@ -2,59 +2,103 @@ package db
 
 import (
 	"fmt"
-	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"     // replace with your actual module path
-	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models" // replace with your actual module path
-	"log"
+	"strings"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config" // replace with your actual module path
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+// Supported values for the DB_DRIVER setting
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 var DB *gorm.DB
 
-func ConnectDB(cfg config.Config) {
-    var err error
-
-    dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-        cfg.DBHost, cfg.DBUsername, cfg.DBPassword, cfg.DBName, cfg.DBPort)
-
-    DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
-    if err != nil {
-        log.Fatalf("Failed to connect to database: %v", err)
-    }
-
-    // AutoMigrate
-    err = DB.AutoMigrate(&models.User{}, &models.Product{}, &models.Category{})
-    if err != nil {
-        log.Fatalf("Failed to auto-migrate: %v", err)
-    }
-
-     // Drop the unused column if it exists
-    //  Db migrations users
-    if DB.Migrator().HasColumn(&models.User{}, "OldColumn") {
-        err = DB.Migrator().DropColumn(&models.User{}, "OldColumn")
-        if err != nil {
-            log.Fatalf("Failed to drop column: %v", err)
-        }
-    }
-
-    // Db migrations products
-    if DB.Migrator().HasColumn(&models.Product{}, "OldColumn") {
-        err = DB.Migrator().DropColumn(&models.Product{}, "OldColumn")
-        if err != nil {
-            log.Fatalf("Failed to drop column: %v", err)
-        }
-    }
-
-    // Db migrations categories
-    if DB.Migrator().HasColumn(&models.Category{}, "OldColumn") {
-        err = DB.Migrator().DropColumn(&models.Category{}, "OldColumn")
-        if err != nil {
-            log.Fatalf("Failed to drop column: %v", err)
-        }
-    }
+// ConnectDB opens the database described by cfg and stores the
+// connection in DB. Pending migrations are applied when
+// cfg.DBAutoMigrate is set.
+func ConnectDB(cfg config.Config) error {
+	conn, err := Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	DB = conn
+	return nil
+}
+
+// Open connects to the database described by cfg, without touching
+// the package-level connection. Pending migrations are applied when
+// cfg.DBAutoMigrate is set.
+func Open(cfg config.Config) (*gorm.DB, error) {
+	dialector, err := dialectorFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if DriverName(cfg) == DriverSQLite {
+		// SQLite only allows a single writer, and an in-memory database
+		// lives and dies with its connection, so keep exactly one open.
+		sqlDB, err := conn.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure database: %w", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	if cfg.DBAutoMigrate {
+		migrator, err := NewMigrator(conn, DriverName(cfg))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := migrator.Up(); err != nil {
+			return nil, err
+		}
+	}
+
+	return conn, nil
 }
 
 func GetDB() *gorm.DB {
-    return DB
+	return DB
+}
+
+// DriverName returns the configured driver, defaulting to Postgres
+func DriverName(cfg config.Config) string {
+	if cfg.DBDriver == "" {
+		return DriverPostgres
+	}
+	return strings.ToLower(cfg.DBDriver)
+}
+
+func dialectorFor(cfg config.Config) (gorm.Dialector, error) {
+	switch DriverName(cfg) {
+	case DriverPostgres:
+		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+			cfg.DBHost, cfg.DBUsername, cfg.DBPassword, cfg.DBName, cfg.DBPort)
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(sqliteDSN(cfg.DBName)), nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", cfg.DBDriver)
+	}
+}
+
+// sqliteDSN builds the SQLite DSN for the database file in name.
+// An empty name or ":memory:" selects a private in-memory database.
+func sqliteDSN(name string) string {
+	if name == "" {
+		name = ":memory:"
+	}
+	return name + "?_foreign_keys=on&_busy_timeout=5000"
 }
@@ -0,0 +1,269 @@
+package db
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migration is one versioned schema change with its rollback.
+// Migrations live in migrations/<driver>/<version>_<name>.{up,down}.sql.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState describes whether a migration has been applied
+type MigrationState struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// appliedMigration is a row of the schema_migrations table
+type appliedMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (appliedMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies and rolls back the migrations for one database
+type Migrator struct {
+	conn       *gorm.DB
+	driver     string
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations for the given driver
+func NewMigrator(conn *gorm.DB, driver string) (*Migrator, error) {
+	migrations, err := LoadMigrations(driver)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{conn: conn, driver: driver, migrations: migrations}, nil
+}
+
+// LoadMigrations returns the embedded migrations for the driver, ordered by version
+func LoadMigrations(driver string) ([]Migration, error) {
+	dir := path.Join("migrations", driver)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for driver %q: %w", driver, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		version, name, direction, err := parseMigrationFilename(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, name)
+		}
+
+		if direction == "up" {
+			m.Up = string(contents)
+		} else {
+			m.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseMigrationFilename splits "0001_create_tables.up.sql" into its parts
+func parseMigrationFilename(filename string) (int, string, string, error) {
+	base, ok := strings.CutSuffix(filename, ".sql")
+	if !ok {
+		return 0, "", "", fmt.Errorf("migration %q is not a .sql file", filename)
+	}
+
+	dot := strings.LastIndex(base, ".")
+	if dot < 0 {
+		return 0, "", "", fmt.Errorf("migration %q has no up/down suffix", filename)
+	}
+	direction := base[dot+1:]
+	if direction != "up" && direction != "down" {
+		return 0, "", "", fmt.Errorf("migration %q has no up/down suffix", filename)
+	}
+
+	versionPart, name, ok := strings.Cut(base[:dot], "_")
+	if !ok {
+		return 0, "", "", fmt.Errorf("migration %q has no name", filename)
+	}
+	version, err := strconv.Atoi(versionPart)
+	if err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("migration %q has an invalid version", filename)
+	}
+
+	return version, name, direction, nil
+}
+
+// Up applies every pending migration in order and returns the ones it ran
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&appliedMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return ran, fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		ran = append(ran, migration)
+	}
+	return ran, nil
+}
+
+// Down rolls back the most recently applied migrations, newest first
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var rolledBack []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return rolledBack, fmt.Errorf("migration %d_%s cannot be rolled back", migration.Version, migration.Name)
+		}
+
+		err := m.transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&appliedMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		rolledBack = append(rolledBack, migration)
+	}
+	return rolledBack, nil
+}
+
+// Status reports every known migration and whether it has been applied
+func (m *Migrator) Status() ([]MigrationState, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		state := MigrationState{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			state.Applied = true
+			state.AppliedAt = &appliedAt
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// transaction runs one migration script atomically. SQLite rebuilds
+// tables to change constraints, so foreign keys are switched off for the
+// duration and verified before commit, as the SQLite documentation
+// recommends; otherwise dropping a referenced table would cascade.
+func (m *Migrator) transaction(fn func(tx *gorm.DB) error) error {
+	if m.driver != DriverSQLite {
+		return m.conn.Transaction(fn)
+	}
+
+	if err := m.conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+		return err
+	}
+	defer m.conn.Exec("PRAGMA foreign_keys = ON")
+
+	return m.conn.Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		var violations []map[string]interface{}
+		if err := tx.Raw("PRAGMA foreign_key_check").Scan(&violations).Error; err != nil {
+			return err
+		}
+		if len(violations) > 0 {
+			return fmt.Errorf("migration leaves %d foreign key violations", len(violations))
+		}
+		return nil
+	})
+}
+
+// applied loads the schema_migrations table, creating it if needed
+func (m *Migrator) applied() (map[int]appliedMigration, error) {
+	err := m.conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var rows []appliedMigration
+	if err := m.conn.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[int]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
@@ -0,0 +1,329 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"gorm.io/gorm"
+)
+
+// openMemory opens a private in-memory SQLite database without applying
+// any migration
+func openMemory(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := Open(config.Config{DBDriver: DriverSQLite, DBName: ":memory:"})
+	if err != nil {
+		t.Fatalf("opening SQLite: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return conn
+}
+
+// schema describes the columns, foreign keys and indexes of every table
+// in the database, by table name. It compares what SQLite enforces rather
+// than the SQL a table was created with, which differs in layout when a
+// down migration rebuilds a table. Column order is left out too: a rebuilt
+// table may list its columns differently, and queries always name them.
+func schema(t *testing.T, conn *gorm.DB) map[string]string {
+	t.Helper()
+	var tables []string
+	err := conn.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'").
+		Scan(&tables).Error
+	if err != nil {
+		t.Fatalf("listing tables: %v", err)
+	}
+
+	described := make(map[string]string, len(tables))
+	for _, table := range tables {
+		var columns []struct {
+			Name       string
+			Type       string
+			NotNull    bool           `gorm:"column:notnull"`
+			Default    sql.NullString `gorm:"column:dflt_value"`
+			PrimaryKey int            `gorm:"column:pk"`
+		}
+		var keys []struct {
+			Table    string
+			From     string
+			To       string
+			OnUpdate string
+			OnDelete string
+		}
+		var indexes []struct {
+			Name   string
+			Unique bool
+		}
+		if err := conn.Raw("SELECT * FROM pragma_table_info(?)", table).Scan(&columns).Error; err != nil {
+			t.Fatalf("reading the columns of %s: %v", table, err)
+		}
+		if err := conn.Raw("SELECT * FROM pragma_foreign_key_list(?)", table).Scan(&keys).Error; err != nil {
+			t.Fatalf("reading the foreign keys of %s: %v", table, err)
+		}
+		if err := conn.Raw("SELECT * FROM pragma_index_list(?)", table).Scan(&indexes).Error; err != nil {
+			t.Fatalf("reading the indexes of %s: %v", table, err)
+		}
+
+		var parts []string
+		for _, column := range columns {
+			parts = append(parts, fmt.Sprintf("column %+v", column))
+		}
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("foreign key %+v", key))
+		}
+		for _, index := range indexes {
+			var indexed []string
+			if err := conn.Raw("SELECT name FROM pragma_index_info(?) ORDER BY seqno", index.Name).Scan(&indexed).Error; err != nil {
+				t.Fatalf("reading index %s: %v", index.Name, err)
+			}
+			// SQLite names the indexes behind UNIQUE columns after the
+			// position of the table's constraint, which a rebuild changes
+			name := index.Name
+			if strings.HasPrefix(name, "sqlite_autoindex_") {
+				name = "autoindex"
+			}
+			parts = append(parts, fmt.Sprintf("index %s unique=%v on %v", name, index.Unique, indexed))
+		}
+		sort.Strings(parts)
+		described[table] = strings.Join(parts, "\n")
+	}
+	return described
+}
+
+func TestLoadMigrations(t *testing.T) {
+	for _, driver := range []string{DriverPostgres, DriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			migrations, err := LoadMigrations(driver)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(migrations) == 0 {
+				t.Fatal("no migrations")
+			}
+			for i, migration := range migrations {
+				if migration.Version != i+1 {
+					t.Fatalf("migration %d_%s is number %d", migration.Version, migration.Name, i+1)
+				}
+				if migration.Up == "" || migration.Down == "" {
+					t.Fatalf("migration %d_%s lacks an up or a down script", migration.Version, migration.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsSameForEveryDriver(t *testing.T) {
+	postgres, err := LoadMigrations(DriverPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite, err := LoadMigrations(DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(postgres) != len(sqlite) {
+		t.Fatalf("got %d Postgres and %d SQLite migrations", len(postgres), len(sqlite))
+	}
+	for i := range postgres {
+		if postgres[i].Name != sqlite[i].Name {
+			t.Fatalf("migration %d is %s on Postgres but %s on SQLite", i+1, postgres[i].Name, sqlite[i].Name)
+		}
+	}
+}
+
+func TestParseMigrationFilename(t *testing.T) {
+	tests := []struct {
+		filename  string
+		version   int
+		name      string
+		direction string
+		ok        bool
+	}{
+		{"0001_create_initial_tables.up.sql", 1, "create_initial_tables", "up", true},
+		{"0012_create_price_history.down.sql", 12, "create_price_history", "down", true},
+		{"0001_create_initial_tables.sql", 0, "", "", false},
+		{"0001_create_initial_tables.sideways.sql", 0, "", "", false},
+		{"first_create_initial_tables.up.sql", 0, "", "", false},
+		{"0001.up.sql", 0, "", "", false},
+		{"README.md", 0, "", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.filename, func(t *testing.T) {
+			version, name, direction, err := parseMigrationFilename(test.filename)
+			if (err == nil) != test.ok {
+				t.Fatalf("got error %v, want ok=%v", err, test.ok)
+			}
+			if !test.ok {
+				return
+			}
+			if version != test.version || name != test.name || direction != test.direction {
+				t.Fatalf("got %d, %q, %q", version, name, direction)
+			}
+		})
+	}
+}
+
+func TestMigrateUpDownUp(t *testing.T) {
+	conn := openMemory(t)
+	migrator, err := NewMigrator(conn, DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := LoadMigrations(DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ran, err := migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != len(all) {
+		t.Fatalf("applied %d of %d migrations", len(ran), len(all))
+	}
+	migrated := schema(t, conn)
+
+	ran, err = migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("applied %d migrations twice", len(ran))
+	}
+
+	rolledBack, err := migrator.Down(len(all))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rolledBack) != len(all) {
+		t.Fatalf("rolled back %d of %d migrations", len(rolledBack), len(all))
+	}
+	for i, migration := range rolledBack {
+		if want := len(all) - i; migration.Version != want {
+			t.Fatalf("rollback %d was %d_%s, want version %d", i+1, migration.Version, migration.Name, want)
+		}
+	}
+	for name := range schema(t, conn) {
+		if name != "schema_migrations" {
+			t.Errorf("%s is left after rolling everything back", name)
+		}
+	}
+
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	again := schema(t, conn)
+	if len(again) != len(migrated) {
+		t.Fatalf("got %d tables and indexes after migrating again, want %d", len(again), len(migrated))
+	}
+	for name, sql := range migrated {
+		if again[name] != sql {
+			t.Errorf("%s differs after migrating again:\n%s\nwant:\n%s", name, again[name], sql)
+		}
+	}
+
+	states, err := migrator.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range states {
+		if !state.Applied || state.AppliedAt == nil {
+			t.Errorf("migration %d_%s is not applied", state.Version, state.Name)
+		}
+	}
+}
+
+// Every migration has to undo exactly what it did, so rolling back any
+// number of steps and migrating again ends where it started
+func TestMigrateDownAnyNumberOfSteps(t *testing.T) {
+	conn := openMemory(t)
+	migrator, err := NewMigrator(conn, DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ran, err := migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated := schema(t, conn)
+
+	for steps := 1; steps <= len(ran); steps++ {
+		rolledBack, err := migrator.Down(steps)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rolledBack) != steps {
+			t.Fatalf("rolled back %d of %d steps", len(rolledBack), steps)
+		}
+		last := rolledBack[len(rolledBack)-1]
+
+		reapplied, err := migrator.Up()
+		if err != nil {
+			t.Fatalf("migrating again from before %d_%s: %v", last.Version, last.Name, err)
+		}
+		if len(reapplied) != steps {
+			t.Fatalf("migrating again from before %d_%s ran %d migrations, want %d", last.Version, last.Name, len(reapplied), steps)
+		}
+		again := schema(t, conn)
+		for name, sql := range migrated {
+			if again[name] != sql {
+				t.Fatalf("%s differs after rolling back to before %d_%s:\n%s\nwant:\n%s", name, last.Version, last.Name, again[name], sql)
+			}
+		}
+	}
+}
+
+func TestMigrateKeepsForeignKeysOn(t *testing.T) {
+	conn := openMemory(t)
+	migrator, err := NewMigrator(conn, DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Down(1); err != nil {
+		t.Fatal(err)
+	}
+
+	var enabled int
+	if err := conn.Raw("PRAGMA foreign_keys").Scan(&enabled).Error; err != nil {
+		t.Fatal(err)
+	}
+	if enabled != 1 {
+		t.Fatal("foreign keys are off after migrating")
+	}
+}
+
+func TestOpenAppliesMigrations(t *testing.T) {
+	conn, err := Open(config.Config{DBDriver: DriverSQLite, DBName: ":memory:", DBAutoMigrate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}()
+
+	migrator, err := NewMigrator(conn, DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	states, err := migrator.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range states {
+		if !state.Applied {
+			t.Errorf("migration %d_%s was not applied", state.Version, state.Name)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+-- Baseline schema. IF NOT EXISTS lets databases that were created by
+-- the old AutoMigrate startup adopt the migration history unchanged.
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    first_name TEXT,
+    last_name TEXT,
+    email TEXT UNIQUE,
+    password TEXT
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    name TEXT UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS products (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    name TEXT UNIQUE,
+    description TEXT,
+    qty BIGINT,
+    price DECIMAL,
+    discount DECIMAL,
+    category_id BIGINT
+);
@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
+
+ALTER TABLE products ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
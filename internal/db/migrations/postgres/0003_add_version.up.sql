@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE products ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
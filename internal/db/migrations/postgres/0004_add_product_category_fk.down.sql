@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_products_category_id;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_products_category;
@@ -0,0 +1,10 @@
+-- Products pointing at categories that no longer exist lose their category
+UPDATE products SET category_id = NULL
+WHERE category_id IS NOT NULL
+  AND category_id NOT IN (SELECT id FROM categories);
+
+ALTER TABLE products
+    ADD CONSTRAINT fk_products_category FOREIGN KEY (category_id)
+    REFERENCES categories (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX idx_products_category_id ON products (category_id);
@@ -0,0 +1,7 @@
+ALTER TABLE categories ADD COLUMN parent_id BIGINT;
+
+ALTER TABLE categories
+    ADD CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id)
+    REFERENCES categories (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
@@ -0,0 +1,17 @@
+CREATE TABLE variants (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    version BIGINT NOT NULL DEFAULT 1,
+    product_id BIGINT NOT NULL,
+    sku TEXT NOT NULL UNIQUE,
+    options JSONB NOT NULL DEFAULT '{}',
+    price DECIMAL,
+    qty BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_products_variants FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_variants_deleted_at ON variants (deleted_at);
+CREATE INDEX idx_variants_product_id ON variants (product_id);
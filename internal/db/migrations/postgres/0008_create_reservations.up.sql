@@ -0,0 +1,17 @@
+CREATE TABLE reservations (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    product_id BIGINT NOT NULL,
+    user_id BIGINT,
+    quantity BIGINT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active',
+    expires_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_reservations_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_reservations_user FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_reservations_product_id ON reservations (product_id);
+CREATE INDEX idx_reservations_status_expires_at ON reservations (status, expires_at);
@@ -0,0 +1,6 @@
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS fk_stock_movements_warehouse;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS warehouse_id;
+
+DROP TABLE IF EXISTS transfers;
+DROP TABLE IF EXISTS warehouse_stocks;
+DROP TABLE IF EXISTS warehouses;
@@ -0,0 +1,52 @@
+CREATE TABLE warehouses (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    version BIGINT NOT NULL DEFAULT 1,
+    name TEXT UNIQUE,
+    address TEXT
+);
+
+CREATE INDEX idx_warehouses_deleted_at ON warehouses (deleted_at);
+
+CREATE TABLE warehouse_stocks (
+    product_id BIGINT NOT NULL,
+    warehouse_id BIGINT NOT NULL,
+    qty BIGINT NOT NULL DEFAULT 0 CHECK (qty >= 0),
+    updated_at TIMESTAMPTZ,
+    PRIMARY KEY (product_id, warehouse_id),
+    CONSTRAINT fk_warehouse_stocks_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_warehouse_stocks_warehouse FOREIGN KEY (warehouse_id)
+        REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_warehouse_stocks_warehouse_id ON warehouse_stocks (warehouse_id);
+
+CREATE TABLE transfers (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    product_id BIGINT NOT NULL,
+    from_warehouse_id BIGINT,
+    to_warehouse_id BIGINT,
+    quantity BIGINT NOT NULL,
+    reason TEXT,
+    actor_id BIGINT,
+    CONSTRAINT fk_transfers_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_transfers_from_warehouse FOREIGN KEY (from_warehouse_id)
+        REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_transfers_to_warehouse FOREIGN KEY (to_warehouse_id)
+        REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_transfers_actor FOREIGN KEY (actor_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_transfers_product_id ON transfers (product_id);
+
+ALTER TABLE stock_movements ADD COLUMN warehouse_id BIGINT;
+
+ALTER TABLE stock_movements
+    ADD CONSTRAINT fk_stock_movements_warehouse FOREIGN KEY (warehouse_id)
+    REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE SET NULL;
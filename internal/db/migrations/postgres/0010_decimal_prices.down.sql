@@ -0,0 +1,5 @@
+ALTER TABLE products
+    ALTER COLUMN price DROP NOT NULL,
+    ALTER COLUMN price DROP DEFAULT,
+    ALTER COLUMN discount DROP NOT NULL,
+    ALTER COLUMN discount DROP DEFAULT;
@@ -0,0 +1,15 @@
+-- Prices used to be written from float64. NUMERIC keeps them exact; the
+-- cast also cleans up columns that were created as double precision.
+UPDATE products SET price = 0 WHERE price IS NULL;
+UPDATE products SET discount = 0 WHERE discount IS NULL;
+
+ALTER TABLE products
+    ALTER COLUMN price TYPE NUMERIC USING price::NUMERIC,
+    ALTER COLUMN price SET DEFAULT 0,
+    ALTER COLUMN price SET NOT NULL,
+    ALTER COLUMN discount TYPE NUMERIC USING discount::NUMERIC,
+    ALTER COLUMN discount SET DEFAULT 0,
+    ALTER COLUMN discount SET NOT NULL;
+
+ALTER TABLE variants
+    ALTER COLUMN price TYPE NUMERIC USING price::NUMERIC;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_prices;
+DROP TABLE IF EXISTS exchange_rates;
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,39 @@
+CREATE TABLE price_history (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    product_id BIGINT NOT NULL,
+    price NUMERIC NOT NULL,
+    currency TEXT NOT NULL,
+    effective_at TIMESTAMPTZ NOT NULL,
+    source TEXT NOT NULL,
+    actor_id BIGINT,
+    CONSTRAINT fk_price_history_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_price_history_actor FOREIGN KEY (actor_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_price_history_product_id_effective_at ON price_history (product_id, effective_at);
+
+CREATE TABLE scheduled_prices (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    product_id BIGINT NOT NULL,
+    price NUMERIC NOT NULL,
+    effective_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    actor_id BIGINT,
+    CONSTRAINT fk_scheduled_prices_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_scheduled_prices_actor FOREIGN KEY (actor_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_scheduled_prices_product_id ON scheduled_prices (product_id);
+CREATE INDEX idx_scheduled_prices_status_effective_at ON scheduled_prices (status, effective_at);
+
+-- Open the history with the prices products already have
+INSERT INTO price_history (created_at, product_id, price, currency, effective_at, source)
+SELECT NOW(), id, price, currency, COALESCE(created_at, NOW()), 'initial'
+FROM products;
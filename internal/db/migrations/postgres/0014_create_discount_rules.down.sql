@@ -0,0 +1,10 @@
+ALTER TABLE products ADD COLUMN discount NUMERIC NOT NULL DEFAULT 0;
+
+UPDATE products SET discount = COALESCE((
+    SELECT r.value FROM discount_rules r
+    WHERE r.product_id = products.id AND r.type = 'fixed' AND r.name = 'Discount' AND r.deleted_at IS NULL
+    ORDER BY r.id
+    LIMIT 1
+), 0);
+
+DROP TABLE IF EXISTS discount_rules;
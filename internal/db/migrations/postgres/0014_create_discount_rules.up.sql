@@ -0,0 +1,34 @@
+CREATE TABLE discount_rules (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    version BIGINT NOT NULL DEFAULT 1,
+    name TEXT,
+    type TEXT NOT NULL,
+    value NUMERIC NOT NULL,
+    currency TEXT,
+    product_id BIGINT,
+    category_id BIGINT,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    priority BIGINT NOT NULL DEFAULT 0,
+    exclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT fk_discount_rules_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_discount_rules_category FOREIGN KEY (category_id)
+        REFERENCES categories (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_discount_rules_deleted_at ON discount_rules (deleted_at);
+CREATE INDEX idx_discount_rules_product_id ON discount_rules (product_id);
+CREATE INDEX idx_discount_rules_category_id ON discount_rules (category_id);
+
+-- products.discount was never interpreted; it is taken to be an amount in
+-- the product's currency and becomes a fixed discount on that product
+INSERT INTO discount_rules (created_at, updated_at, name, type, value, currency, product_id)
+SELECT NOW(), NOW(), 'Discount', 'fixed', discount, currency, id
+FROM products
+WHERE discount <> 0;
+
+ALTER TABLE products DROP COLUMN discount;
@@ -0,0 +1,37 @@
+CREATE TABLE coupons (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    version BIGINT NOT NULL DEFAULT 1,
+    code TEXT NOT NULL UNIQUE,
+    description TEXT,
+    type TEXT NOT NULL,
+    value NUMERIC NOT NULL,
+    currency TEXT NOT NULL,
+    min_order_value NUMERIC NOT NULL DEFAULT 0,
+    usage_limit BIGINT,
+    per_user_limit BIGINT,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    redeemed BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT chk_coupons_redeemed CHECK (usage_limit IS NULL OR redeemed <= usage_limit)
+);
+
+CREATE INDEX idx_coupons_deleted_at ON coupons (deleted_at);
+
+CREATE TABLE coupon_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    coupon_id BIGINT NOT NULL,
+    user_id BIGINT,
+    order_value NUMERIC NOT NULL,
+    discount NUMERIC NOT NULL,
+    currency TEXT NOT NULL,
+    CONSTRAINT fk_coupon_redemptions_coupon FOREIGN KEY (coupon_id)
+        REFERENCES coupons (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_coupon_redemptions_user FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_coupon_redemptions_coupon_id_user_id ON coupon_redemptions (coupon_id, user_id);
@@ -0,0 +1,22 @@
+CREATE TABLE cart_items (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    user_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT,
+    quantity BIGINT NOT NULL,
+    CONSTRAINT chk_cart_items_quantity CHECK (quantity > 0),
+    CONSTRAINT fk_cart_items_user FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_cart_items_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_cart_items_variant FOREIGN KEY (variant_id)
+        REFERENCES variants (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- A user has one line per product and one per variant of it. NULLs never
+-- clash in a unique index, so lines without a variant need their own.
+CREATE UNIQUE INDEX idx_cart_items_user_id_product_id ON cart_items (user_id, product_id)
+    WHERE variant_id IS NULL;
+CREATE UNIQUE INDEX idx_cart_items_user_id_variant_id ON cart_items (user_id, variant_id);
@@ -0,0 +1,40 @@
+CREATE TABLE orders (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    user_id BIGINT,
+    status TEXT NOT NULL DEFAULT 'pending',
+    currency TEXT NOT NULL,
+    subtotal NUMERIC NOT NULL,
+    discount NUMERIC NOT NULL DEFAULT 0,
+    total NUMERIC NOT NULL,
+    coupon_id BIGINT,
+    coupon_code TEXT,
+    CONSTRAINT fk_orders_user FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_orders_coupon FOREIGN KEY (coupon_id)
+        REFERENCES coupons (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_orders_user_id ON orders (user_id);
+
+CREATE TABLE order_items (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    product_id BIGINT,
+    variant_id BIGINT,
+    name TEXT NOT NULL,
+    sku TEXT,
+    unit_price NUMERIC NOT NULL,
+    quantity BIGINT NOT NULL,
+    line_total NUMERIC NOT NULL,
+    CONSTRAINT chk_order_items_quantity CHECK (quantity > 0),
+    CONSTRAINT fk_orders_items FOREIGN KEY (order_id)
+        REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_order_items_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_order_items_variant FOREIGN KEY (variant_id)
+        REFERENCES variants (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_order_items_order_id ON order_items (order_id);
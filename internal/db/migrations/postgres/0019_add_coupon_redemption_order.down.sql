@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_coupon_redemptions_order_id;
+ALTER TABLE coupon_redemptions DROP CONSTRAINT IF EXISTS fk_coupon_redemptions_order;
+ALTER TABLE coupon_redemptions DROP COLUMN IF EXISTS order_id;
+ALTER TABLE coupon_redemptions DROP COLUMN IF EXISTS released_at;
@@ -0,0 +1,8 @@
+ALTER TABLE coupon_redemptions ADD COLUMN order_id BIGINT;
+ALTER TABLE coupon_redemptions ADD COLUMN released_at TIMESTAMPTZ;
+
+ALTER TABLE coupon_redemptions
+    ADD CONSTRAINT fk_coupon_redemptions_order FOREIGN KEY (order_id)
+    REFERENCES orders (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX idx_coupon_redemptions_order_id ON coupon_redemptions (order_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sequences;
+DROP TABLE IF EXISTS invoices;
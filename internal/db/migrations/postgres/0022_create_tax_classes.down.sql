@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS order_taxes;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_rate;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS prices_include_tax;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_region;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax;
+ALTER TABLE orders DROP COLUMN IF EXISTS net;
+
+DROP INDEX IF EXISTS idx_products_tax_class_id;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_products_tax_class;
+ALTER TABLE products DROP COLUMN IF EXISTS tax_class_id;
+
+DROP INDEX IF EXISTS idx_categories_tax_class_id;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS fk_categories_tax_class;
+ALTER TABLE categories DROP COLUMN IF EXISTS tax_class_id;
+
+DROP TABLE IF EXISTS tax_rates;
+DROP TABLE IF EXISTS tax_classes;
@@ -0,0 +1,61 @@
+CREATE TABLE tax_classes (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE tax_rates (
+    tax_class_id BIGINT NOT NULL,
+    region TEXT NOT NULL,
+    rate NUMERIC NOT NULL CHECK (rate >= 0),
+    updated_at TIMESTAMPTZ,
+    PRIMARY KEY (tax_class_id, region),
+    CONSTRAINT fk_tax_classes_rates FOREIGN KEY (tax_class_id)
+        REFERENCES tax_classes (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_tax_rates_region ON tax_rates (region);
+
+ALTER TABLE categories ADD COLUMN tax_class_id BIGINT;
+
+ALTER TABLE categories
+    ADD CONSTRAINT fk_categories_tax_class FOREIGN KEY (tax_class_id)
+    REFERENCES tax_classes (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX idx_categories_tax_class_id ON categories (tax_class_id);
+
+ALTER TABLE products ADD COLUMN tax_class_id BIGINT;
+
+ALTER TABLE products
+    ADD CONSTRAINT fk_products_tax_class FOREIGN KEY (tax_class_id)
+    REFERENCES tax_classes (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX idx_products_tax_class_id ON products (tax_class_id);
+
+ALTER TABLE orders ADD COLUMN net NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN tax NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN tax_region TEXT;
+ALTER TABLE orders ADD COLUMN prices_include_tax BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE order_items ADD COLUMN tax_rate NUMERIC NOT NULL DEFAULT 0;
+
+CREATE TABLE order_taxes (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    rate NUMERIC NOT NULL,
+    net NUMERIC NOT NULL,
+    tax NUMERIC NOT NULL,
+    gross NUMERIC NOT NULL,
+    CONSTRAINT fk_orders_taxes FOREIGN KEY (order_id)
+        REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_order_taxes_order_id ON order_taxes (order_id);
+
+-- Orders placed before taxes existed were not taxed: all of their total
+-- is net, at a rate of zero
+UPDATE orders SET net = total, tax = total - total;
+
+INSERT INTO order_taxes (order_id, rate, net, tax, gross)
+SELECT id, 0, net, tax, total FROM orders ORDER BY id;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS shipping_methods;
+DROP TABLE IF EXISTS shipping_zones;
+
+ALTER TABLE products DROP COLUMN IF EXISTS height;
+ALTER TABLE products DROP COLUMN IF EXISTS width;
+ALTER TABLE products DROP COLUMN IF EXISTS length;
+ALTER TABLE products DROP COLUMN IF EXISTS weight;
@@ -0,0 +1,29 @@
+ALTER TABLE products ADD COLUMN weight NUMERIC NOT NULL DEFAULT 0 CHECK (weight >= 0);
+ALTER TABLE products ADD COLUMN length NUMERIC NOT NULL DEFAULT 0 CHECK (length >= 0);
+ALTER TABLE products ADD COLUMN width NUMERIC NOT NULL DEFAULT 0 CHECK (width >= 0);
+ALTER TABLE products ADD COLUMN height NUMERIC NOT NULL DEFAULT 0 CHECK (height >= 0);
+
+CREATE TABLE shipping_zones (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    name TEXT NOT NULL UNIQUE,
+    regions JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE TABLE shipping_methods (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    zone_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    price NUMERIC NOT NULL DEFAULT 0 CHECK (price >= 0),
+    threshold NUMERIC,
+    tiers JSONB NOT NULL DEFAULT '[]',
+    CONSTRAINT fk_shipping_zones_methods FOREIGN KEY (zone_id)
+        REFERENCES shipping_zones (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_shipping_methods_zone_id ON shipping_methods (zone_id);
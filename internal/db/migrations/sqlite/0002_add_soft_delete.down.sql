@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_products_deleted_at;
+ALTER TABLE products DROP COLUMN deleted_at;
+
+DROP INDEX IF EXISTS idx_categories_deleted_at;
+ALTER TABLE categories DROP COLUMN deleted_at;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,21 @@
+CREATE TABLE products_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    name TEXT UNIQUE,
+    description TEXT,
+    qty INTEGER,
+    price REAL,
+    discount REAL,
+    category_id INTEGER
+);
+
+INSERT INTO products_old (id, created_at, updated_at, deleted_at, version, name, description, qty, price, discount, category_id)
+SELECT id, created_at, updated_at, deleted_at, version, name, description, qty, price, discount, category_id FROM products;
+
+DROP TABLE products;
+ALTER TABLE products_old RENAME TO products;
+
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
@@ -0,0 +1,29 @@
+-- SQLite cannot add a constraint to an existing table, so rebuild it
+UPDATE products SET category_id = NULL
+WHERE category_id IS NOT NULL
+  AND category_id NOT IN (SELECT id FROM categories);
+
+CREATE TABLE products_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    name TEXT UNIQUE,
+    description TEXT,
+    qty INTEGER,
+    price REAL,
+    discount REAL,
+    category_id INTEGER,
+    CONSTRAINT fk_products_category FOREIGN KEY (category_id)
+        REFERENCES categories (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+INSERT INTO products_new (id, created_at, updated_at, deleted_at, version, name, description, qty, price, discount, category_id)
+SELECT id, created_at, updated_at, deleted_at, version, name, description, qty, price, discount, category_id FROM products;
+
+DROP TABLE products;
+ALTER TABLE products_new RENAME TO products;
+
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+CREATE INDEX idx_products_category_id ON products (category_id);
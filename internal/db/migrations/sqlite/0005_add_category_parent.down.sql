@@ -0,0 +1,17 @@
+-- SQLite cannot drop a column that is part of a foreign key, so rebuild the table
+CREATE TABLE categories_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    name TEXT UNIQUE
+);
+
+INSERT INTO categories_old (id, created_at, updated_at, deleted_at, version, name)
+SELECT id, created_at, updated_at, deleted_at, version, name FROM categories;
+
+DROP TABLE categories;
+ALTER TABLE categories_old RENAME TO categories;
+
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
@@ -0,0 +1,4 @@
+ALTER TABLE categories ADD COLUMN parent_id INTEGER
+    CONSTRAINT fk_categories_parent REFERENCES categories (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
@@ -0,0 +1,17 @@
+CREATE TABLE variants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    product_id INTEGER NOT NULL,
+    sku TEXT NOT NULL UNIQUE,
+    options TEXT NOT NULL DEFAULT '{}',
+    price REAL,
+    qty INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_products_variants FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_variants_deleted_at ON variants (deleted_at);
+CREATE INDEX idx_variants_product_id ON variants (product_id);
@@ -0,0 +1,32 @@
+CREATE TABLE stock_movements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    product_id INTEGER NOT NULL,
+    variant_id INTEGER,
+    type TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    balance INTEGER NOT NULL,
+    reason TEXT,
+    actor_id INTEGER,
+    CONSTRAINT fk_stock_movements_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_stock_movements_variant FOREIGN KEY (variant_id)
+        REFERENCES variants (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_stock_movements_actor FOREIGN KEY (actor_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_stock_movements_product_id ON stock_movements (product_id);
+
+-- Open the ledger with the stock products and variants already have
+INSERT INTO stock_movements (created_at, product_id, type, quantity, balance, reason)
+SELECT CURRENT_TIMESTAMP, id, 'adjustment', qty, qty, 'Opening balance'
+FROM products
+WHERE qty IS NOT NULL AND qty <> 0;
+
+INSERT INTO stock_movements (created_at, product_id, variant_id, type, quantity, balance, reason)
+SELECT CURRENT_TIMESTAMP, product_id, id, 'adjustment', qty, qty, 'Opening balance'
+FROM variants
+WHERE qty <> 0;
+
+UPDATE products SET qty = 0 WHERE qty IS NULL;
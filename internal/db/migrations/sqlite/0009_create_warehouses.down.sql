@@ -0,0 +1,30 @@
+-- SQLite cannot drop a column that is part of a foreign key, so rebuild the table
+CREATE TABLE stock_movements_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    product_id INTEGER NOT NULL,
+    variant_id INTEGER,
+    type TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    balance INTEGER NOT NULL,
+    reason TEXT,
+    actor_id INTEGER,
+    CONSTRAINT fk_stock_movements_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_stock_movements_variant FOREIGN KEY (variant_id)
+        REFERENCES variants (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_stock_movements_actor FOREIGN KEY (actor_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+INSERT INTO stock_movements_old (id, created_at, product_id, variant_id, type, quantity, balance, reason, actor_id)
+SELECT id, created_at, product_id, variant_id, type, quantity, balance, reason, actor_id FROM stock_movements;
+
+DROP TABLE stock_movements;
+ALTER TABLE stock_movements_old RENAME TO stock_movements;
+
+CREATE INDEX idx_stock_movements_product_id ON stock_movements (product_id);
+
+DROP TABLE IF EXISTS transfers;
+DROP TABLE IF EXISTS warehouse_stocks;
+DROP TABLE IF EXISTS warehouses;
@@ -0,0 +1,49 @@
+CREATE TABLE warehouses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    name TEXT UNIQUE,
+    address TEXT
+);
+
+CREATE INDEX idx_warehouses_deleted_at ON warehouses (deleted_at);
+
+CREATE TABLE warehouse_stocks (
+    product_id INTEGER NOT NULL,
+    warehouse_id INTEGER NOT NULL,
+    qty INTEGER NOT NULL DEFAULT 0 CHECK (qty >= 0),
+    updated_at DATETIME,
+    PRIMARY KEY (product_id, warehouse_id),
+    CONSTRAINT fk_warehouse_stocks_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_warehouse_stocks_warehouse FOREIGN KEY (warehouse_id)
+        REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_warehouse_stocks_warehouse_id ON warehouse_stocks (warehouse_id);
+
+CREATE TABLE transfers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    product_id INTEGER NOT NULL,
+    from_warehouse_id INTEGER,
+    to_warehouse_id INTEGER,
+    quantity INTEGER NOT NULL,
+    reason TEXT,
+    actor_id INTEGER,
+    CONSTRAINT fk_transfers_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_transfers_from_warehouse FOREIGN KEY (from_warehouse_id)
+        REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_transfers_to_warehouse FOREIGN KEY (to_warehouse_id)
+        REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_transfers_actor FOREIGN KEY (actor_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_transfers_product_id ON transfers (product_id);
+
+ALTER TABLE stock_movements ADD COLUMN warehouse_id INTEGER
+    CONSTRAINT fk_stock_movements_warehouse REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE SET NULL;
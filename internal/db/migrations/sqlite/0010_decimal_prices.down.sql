@@ -0,0 +1,57 @@
+CREATE TABLE products_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    name TEXT UNIQUE,
+    description TEXT,
+    qty INTEGER,
+    price REAL,
+    discount REAL,
+    category_id INTEGER,
+    CONSTRAINT fk_products_category FOREIGN KEY (category_id)
+        REFERENCES categories (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+INSERT INTO products_old (id, created_at, updated_at, deleted_at, version, name, description, qty, price, discount, category_id)
+SELECT id, created_at, updated_at, deleted_at, version, name, description, qty,
+       CAST(price AS REAL), CAST(discount AS REAL), category_id
+FROM products;
+
+DELETE FROM sqlite_sequence WHERE name = 'products_old';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'products_old', seq FROM sqlite_sequence WHERE name = 'products';
+
+DROP TABLE products;
+ALTER TABLE products_old RENAME TO products;
+
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+CREATE INDEX idx_products_category_id ON products (category_id);
+
+CREATE TABLE variants_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    product_id INTEGER NOT NULL,
+    sku TEXT NOT NULL UNIQUE,
+    options TEXT NOT NULL DEFAULT '{}',
+    price REAL,
+    qty INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_products_variants FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+INSERT INTO variants_old (id, created_at, updated_at, deleted_at, version, product_id, sku, options, price, qty)
+SELECT id, created_at, updated_at, deleted_at, version, product_id, sku, options, CAST(price AS REAL), qty
+FROM variants;
+
+DELETE FROM sqlite_sequence WHERE name = 'variants_old';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'variants_old', seq FROM sqlite_sequence WHERE name = 'variants';
+
+DROP TABLE variants;
+ALTER TABLE variants_old RENAME TO variants;
+
+CREATE INDEX idx_variants_deleted_at ON variants (deleted_at);
+CREATE INDEX idx_variants_product_id ON variants (product_id);
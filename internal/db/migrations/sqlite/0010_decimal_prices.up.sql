@@ -0,0 +1,62 @@
+-- SQLite has no exact numeric type: a NUMERIC or REAL column would turn
+-- "19.90" back into a float. Prices are kept as decimal text instead.
+-- CAST(REAL AS TEXT) prints 15 significant digits, which drops the
+-- float noise of the old values without losing any real digit.
+CREATE TABLE products_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    name TEXT UNIQUE,
+    description TEXT,
+    qty INTEGER,
+    price TEXT NOT NULL DEFAULT '0',
+    discount TEXT NOT NULL DEFAULT '0',
+    category_id INTEGER,
+    CONSTRAINT fk_products_category FOREIGN KEY (category_id)
+        REFERENCES categories (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+INSERT INTO products_new (id, created_at, updated_at, deleted_at, version, name, description, qty, price, discount, category_id)
+SELECT id, created_at, updated_at, deleted_at, version, name, description, qty,
+       COALESCE(CAST(price AS TEXT), '0'), COALESCE(CAST(discount AS TEXT), '0'), category_id
+FROM products;
+
+-- Carry the ID sequence over so purged IDs are not handed out again
+DELETE FROM sqlite_sequence WHERE name = 'products_new';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'products_new', seq FROM sqlite_sequence WHERE name = 'products';
+
+DROP TABLE products;
+ALTER TABLE products_new RENAME TO products;
+
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+CREATE INDEX idx_products_category_id ON products (category_id);
+
+CREATE TABLE variants_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    product_id INTEGER NOT NULL,
+    sku TEXT NOT NULL UNIQUE,
+    options TEXT NOT NULL DEFAULT '{}',
+    price TEXT,
+    qty INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_products_variants FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+INSERT INTO variants_new (id, created_at, updated_at, deleted_at, version, product_id, sku, options, price, qty)
+SELECT id, created_at, updated_at, deleted_at, version, product_id, sku, options, CAST(price AS TEXT), qty
+FROM variants;
+
+DELETE FROM sqlite_sequence WHERE name = 'variants_new';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'variants_new', seq FROM sqlite_sequence WHERE name = 'variants';
+
+DROP TABLE variants;
+ALTER TABLE variants_new RENAME TO variants;
+
+CREATE INDEX idx_variants_deleted_at ON variants (deleted_at);
+CREATE INDEX idx_variants_product_id ON variants (product_id);
@@ -0,0 +1,19 @@
+-- Existing prices had no currency; they are taken to be in USD
+ALTER TABLE products ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+
+CREATE TABLE exchange_rates (
+    currency TEXT PRIMARY KEY,
+    rate TEXT NOT NULL,
+    decimals INTEGER NOT NULL DEFAULT 2,
+    updated_at DATETIME
+);
+
+CREATE TABLE product_prices (
+    product_id INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    price TEXT NOT NULL,
+    updated_at DATETIME,
+    PRIMARY KEY (product_id, currency),
+    CONSTRAINT fk_product_prices_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN admin NUMERIC NOT NULL DEFAULT 0;
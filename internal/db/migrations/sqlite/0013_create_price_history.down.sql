@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scheduled_prices;
+DROP TABLE IF EXISTS price_history;
@@ -0,0 +1,39 @@
+CREATE TABLE price_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    product_id INTEGER NOT NULL,
+    price TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    effective_at DATETIME NOT NULL,
+    source TEXT NOT NULL,
+    actor_id INTEGER,
+    CONSTRAINT fk_price_history_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_price_history_actor FOREIGN KEY (actor_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_price_history_product_id_effective_at ON price_history (product_id, effective_at);
+
+CREATE TABLE scheduled_prices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    product_id INTEGER NOT NULL,
+    price TEXT NOT NULL,
+    effective_at DATETIME NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    actor_id INTEGER,
+    CONSTRAINT fk_scheduled_prices_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_scheduled_prices_actor FOREIGN KEY (actor_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_scheduled_prices_product_id ON scheduled_prices (product_id);
+CREATE INDEX idx_scheduled_prices_status_effective_at ON scheduled_prices (status, effective_at);
+
+-- Open the history with the prices products already have
+INSERT INTO price_history (created_at, product_id, price, currency, effective_at, source)
+SELECT CURRENT_TIMESTAMP, id, price, currency, COALESCE(created_at, CURRENT_TIMESTAMP), 'initial'
+FROM products;
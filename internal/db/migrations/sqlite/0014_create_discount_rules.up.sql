@@ -0,0 +1,34 @@
+CREATE TABLE discount_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    name TEXT,
+    type TEXT NOT NULL,
+    value TEXT NOT NULL,
+    currency TEXT,
+    product_id INTEGER,
+    category_id INTEGER,
+    starts_at DATETIME,
+    ends_at DATETIME,
+    priority INTEGER NOT NULL DEFAULT 0,
+    exclusive NUMERIC NOT NULL DEFAULT 0,
+    CONSTRAINT fk_discount_rules_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_discount_rules_category FOREIGN KEY (category_id)
+        REFERENCES categories (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_discount_rules_deleted_at ON discount_rules (deleted_at);
+CREATE INDEX idx_discount_rules_product_id ON discount_rules (product_id);
+CREATE INDEX idx_discount_rules_category_id ON discount_rules (category_id);
+
+-- products.discount was never interpreted; it is taken to be an amount in
+-- the product's currency and becomes a fixed discount on that product
+INSERT INTO discount_rules (created_at, updated_at, name, type, value, currency, product_id)
+SELECT CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'Discount', 'fixed', discount, currency, id
+FROM products
+WHERE CAST(discount AS REAL) <> 0;
+
+ALTER TABLE products DROP COLUMN discount;
@@ -0,0 +1,37 @@
+CREATE TABLE coupons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    code TEXT NOT NULL UNIQUE,
+    description TEXT,
+    type TEXT NOT NULL,
+    value TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    min_order_value TEXT NOT NULL DEFAULT '0',
+    usage_limit INTEGER,
+    per_user_limit INTEGER,
+    starts_at DATETIME,
+    ends_at DATETIME,
+    redeemed INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT chk_coupons_redeemed CHECK (usage_limit IS NULL OR redeemed <= usage_limit)
+);
+
+CREATE INDEX idx_coupons_deleted_at ON coupons (deleted_at);
+
+CREATE TABLE coupon_redemptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    coupon_id INTEGER NOT NULL,
+    user_id INTEGER,
+    order_value TEXT NOT NULL,
+    discount TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    CONSTRAINT fk_coupon_redemptions_coupon FOREIGN KEY (coupon_id)
+        REFERENCES coupons (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_coupon_redemptions_user FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_coupon_redemptions_coupon_id_user_id ON coupon_redemptions (coupon_id, user_id);
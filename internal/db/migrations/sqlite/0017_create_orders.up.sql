@@ -0,0 +1,40 @@
+CREATE TABLE orders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    user_id INTEGER,
+    status TEXT NOT NULL DEFAULT 'pending',
+    currency TEXT NOT NULL,
+    subtotal TEXT NOT NULL,
+    discount TEXT NOT NULL DEFAULT '0',
+    total TEXT NOT NULL,
+    coupon_id INTEGER,
+    coupon_code TEXT,
+    CONSTRAINT fk_orders_user FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_orders_coupon FOREIGN KEY (coupon_id)
+        REFERENCES coupons (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_orders_user_id ON orders (user_id);
+
+CREATE TABLE order_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL,
+    product_id INTEGER,
+    variant_id INTEGER,
+    name TEXT NOT NULL,
+    sku TEXT,
+    unit_price TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    line_total TEXT NOT NULL,
+    CONSTRAINT chk_order_items_quantity CHECK (quantity > 0),
+    CONSTRAINT fk_orders_items FOREIGN KEY (order_id)
+        REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_order_items_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_order_items_variant FOREIGN KEY (variant_id)
+        REFERENCES variants (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_order_items_order_id ON order_items (order_id);
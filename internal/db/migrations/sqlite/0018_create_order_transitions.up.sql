@@ -0,0 +1,19 @@
+CREATE TABLE order_transitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    order_id INTEGER NOT NULL,
+    from_status TEXT NOT NULL DEFAULT '',
+    to_status TEXT NOT NULL,
+    note TEXT,
+    actor_id INTEGER,
+    CONSTRAINT fk_order_transitions_order FOREIGN KEY (order_id)
+        REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_order_transitions_actor FOREIGN KEY (actor_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX idx_order_transitions_order_id ON order_transitions (order_id);
+
+-- Every order placed so far starts its history in its current state
+INSERT INTO order_transitions (created_at, order_id, from_status, to_status, actor_id)
+SELECT created_at, id, '', status, user_id FROM orders;
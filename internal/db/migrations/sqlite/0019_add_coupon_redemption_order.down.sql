@@ -0,0 +1,29 @@
+DROP INDEX IF EXISTS idx_coupon_redemptions_order_id;
+
+-- SQLite cannot drop a column with a foreign key, so coupon_redemptions
+-- is rebuilt without order_id and released_at
+CREATE TABLE coupon_redemptions_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    coupon_id INTEGER NOT NULL,
+    user_id INTEGER,
+    order_value TEXT NOT NULL,
+    discount TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    CONSTRAINT fk_coupon_redemptions_coupon FOREIGN KEY (coupon_id)
+        REFERENCES coupons (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_coupon_redemptions_user FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+INSERT INTO coupon_redemptions_old (id, created_at, coupon_id, user_id, order_value, discount, currency)
+SELECT id, created_at, coupon_id, user_id, order_value, discount, currency
+FROM coupon_redemptions;
+
+DELETE FROM sqlite_sequence WHERE name = 'coupon_redemptions_old';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'coupon_redemptions_old', seq FROM sqlite_sequence WHERE name = 'coupon_redemptions';
+
+DROP TABLE coupon_redemptions;
+ALTER TABLE coupon_redemptions_old RENAME TO coupon_redemptions;
+
+CREATE INDEX idx_coupon_redemptions_coupon_id_user_id ON coupon_redemptions (coupon_id, user_id);
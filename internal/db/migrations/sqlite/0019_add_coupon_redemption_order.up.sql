@@ -0,0 +1,5 @@
+ALTER TABLE coupon_redemptions ADD COLUMN order_id INTEGER
+    CONSTRAINT fk_coupon_redemptions_order REFERENCES orders (id) ON UPDATE CASCADE ON DELETE SET NULL;
+ALTER TABLE coupon_redemptions ADD COLUMN released_at DATETIME;
+
+CREATE UNIQUE INDEX idx_coupon_redemptions_order_id ON coupon_redemptions (order_id);
@@ -0,0 +1,30 @@
+CREATE TABLE payments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    order_id INTEGER NOT NULL,
+    provider TEXT NOT NULL,
+    intent_id TEXT NOT NULL,
+    method TEXT,
+    amount TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    CONSTRAINT fk_payments_order FOREIGN KEY (order_id)
+        REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_payments_order_id ON payments (order_id);
+CREATE UNIQUE INDEX idx_payments_provider_intent_id ON payments (provider, intent_id);
+
+CREATE TABLE payment_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    provider TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    payment_id INTEGER,
+    CONSTRAINT fk_payment_events_payment FOREIGN KEY (payment_id)
+        REFERENCES payments (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX idx_payment_events_provider_event_id ON payment_events (provider, event_id);
@@ -0,0 +1,27 @@
+CREATE TABLE invoices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    order_id INTEGER NOT NULL,
+    number INTEGER NOT NULL,
+    CONSTRAINT fk_invoices_order FOREIGN KEY (order_id)
+        REFERENCES orders (id) ON UPDATE CASCADE ON DELETE RESTRICT
+);
+
+CREATE UNIQUE INDEX idx_invoices_order_id ON invoices (order_id);
+CREATE UNIQUE INDEX idx_invoices_number ON invoices (number);
+
+-- The last number handed out per kind of document. Numbers are drawn from
+-- here in the transaction that uses them, so they have no gaps and are
+-- never handed out twice, whatever happens to the documents afterwards.
+CREATE TABLE sequences (
+    name TEXT PRIMARY KEY,
+    value INTEGER NOT NULL
+);
+
+-- Orders paid for before invoices existed are invoiced in the order they
+-- were placed
+INSERT INTO invoices (created_at, order_id, number)
+SELECT CURRENT_TIMESTAMP, id, ROW_NUMBER() OVER (ORDER BY id) FROM orders
+WHERE status IN ('paid', 'fulfilled', 'shipped', 'delivered', 'refunded');
+
+INSERT INTO sequences (name, value) SELECT 'invoices', COUNT(*) FROM invoices;
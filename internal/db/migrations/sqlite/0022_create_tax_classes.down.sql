@@ -0,0 +1,66 @@
+DROP TABLE IF EXISTS order_taxes;
+
+ALTER TABLE order_items DROP COLUMN tax_rate;
+
+ALTER TABLE orders DROP COLUMN prices_include_tax;
+ALTER TABLE orders DROP COLUMN tax_region;
+ALTER TABLE orders DROP COLUMN tax;
+ALTER TABLE orders DROP COLUMN net;
+
+-- SQLite cannot drop a column with a foreign key, so products and
+-- categories are rebuilt without tax_class_id
+CREATE TABLE products_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    name TEXT UNIQUE,
+    description TEXT,
+    qty INTEGER,
+    price TEXT NOT NULL DEFAULT '0',
+    category_id INTEGER,
+    currency TEXT NOT NULL DEFAULT 'USD',
+    CONSTRAINT fk_products_category FOREIGN KEY (category_id)
+        REFERENCES categories (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+INSERT INTO products_old (id, created_at, updated_at, deleted_at, version, name, description, qty, price, category_id, currency)
+SELECT id, created_at, updated_at, deleted_at, version, name, description, qty, price, category_id, currency
+FROM products;
+
+DELETE FROM sqlite_sequence WHERE name = 'products_old';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'products_old', seq FROM sqlite_sequence WHERE name = 'products';
+
+DROP TABLE products;
+ALTER TABLE products_old RENAME TO products;
+
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+CREATE INDEX idx_products_category_id ON products (category_id);
+
+CREATE TABLE categories_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    name TEXT UNIQUE,
+    deleted_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    parent_id INTEGER
+        CONSTRAINT fk_categories_parent REFERENCES categories (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+INSERT INTO categories_old (id, created_at, updated_at, name, deleted_at, version, parent_id)
+SELECT id, created_at, updated_at, name, deleted_at, version, parent_id
+FROM categories;
+
+DELETE FROM sqlite_sequence WHERE name = 'categories_old';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'categories_old', seq FROM sqlite_sequence WHERE name = 'categories';
+
+DROP TABLE categories;
+ALTER TABLE categories_old RENAME TO categories;
+
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+
+DROP TABLE IF EXISTS tax_rates;
+DROP TABLE IF EXISTS tax_classes;
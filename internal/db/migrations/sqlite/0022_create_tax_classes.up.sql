@@ -0,0 +1,59 @@
+CREATE TABLE tax_classes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE tax_rates (
+    tax_class_id INTEGER NOT NULL,
+    region TEXT NOT NULL,
+    rate TEXT NOT NULL,
+    updated_at DATETIME,
+    PRIMARY KEY (tax_class_id, region),
+    CONSTRAINT fk_tax_classes_rates FOREIGN KEY (tax_class_id)
+        REFERENCES tax_classes (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_tax_rates_region ON tax_rates (region);
+
+ALTER TABLE categories ADD COLUMN tax_class_id INTEGER
+    CONSTRAINT fk_categories_tax_class REFERENCES tax_classes (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX idx_categories_tax_class_id ON categories (tax_class_id);
+
+ALTER TABLE products ADD COLUMN tax_class_id INTEGER
+    CONSTRAINT fk_products_tax_class REFERENCES tax_classes (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX idx_products_tax_class_id ON products (tax_class_id);
+
+ALTER TABLE orders ADD COLUMN net TEXT NOT NULL DEFAULT '0';
+ALTER TABLE orders ADD COLUMN tax TEXT NOT NULL DEFAULT '0';
+ALTER TABLE orders ADD COLUMN tax_region TEXT;
+ALTER TABLE orders ADD COLUMN prices_include_tax NUMERIC NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items ADD COLUMN tax_rate TEXT NOT NULL DEFAULT '0';
+
+CREATE TABLE order_taxes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL,
+    rate TEXT NOT NULL,
+    net TEXT NOT NULL,
+    tax TEXT NOT NULL,
+    gross TEXT NOT NULL,
+    CONSTRAINT fk_orders_taxes FOREIGN KEY (order_id)
+        REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_order_taxes_order_id ON order_taxes (order_id);
+
+-- Orders placed before taxes existed were not taxed: all of their total
+-- is net, at a rate of zero. Their tax of zero keeps the decimals of the
+-- total, as totals are stored as text.
+UPDATE orders SET net = total, tax = CASE
+    WHEN instr(total, '.') > 0 THEN '0.' || substr('00000000', 1, length(total) - instr(total, '.'))
+    ELSE '0'
+END;
+
+INSERT INTO order_taxes (order_id, rate, net, tax, gross)
+SELECT id, '0', net, tax, total FROM orders ORDER BY id;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS shipping_methods;
+DROP TABLE IF EXISTS shipping_zones;
+
+ALTER TABLE products DROP COLUMN height;
+ALTER TABLE products DROP COLUMN width;
+ALTER TABLE products DROP COLUMN length;
+ALTER TABLE products DROP COLUMN weight;
@@ -0,0 +1,29 @@
+ALTER TABLE products ADD COLUMN weight TEXT NOT NULL DEFAULT '0';
+ALTER TABLE products ADD COLUMN length TEXT NOT NULL DEFAULT '0';
+ALTER TABLE products ADD COLUMN width TEXT NOT NULL DEFAULT '0';
+ALTER TABLE products ADD COLUMN height TEXT NOT NULL DEFAULT '0';
+
+CREATE TABLE shipping_zones (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    name TEXT NOT NULL UNIQUE,
+    regions TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE TABLE shipping_methods (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    zone_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    price TEXT NOT NULL DEFAULT '0',
+    threshold TEXT,
+    tiers TEXT NOT NULL DEFAULT '[]',
+    CONSTRAINT fk_shipping_zones_methods FOREIGN KEY (zone_id)
+        REFERENCES shipping_zones (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_shipping_methods_zone_id ON shipping_methods (zone_id);
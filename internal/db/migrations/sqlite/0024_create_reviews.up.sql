@@ -0,0 +1,21 @@
+ALTER TABLE products ADD COLUMN rating_average TEXT NOT NULL DEFAULT '0';
+ALTER TABLE products ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    product_id INTEGER NOT NULL,
+    user_id INTEGER,
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    CONSTRAINT fk_products_reviews FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_users_reviews FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX idx_reviews_product_user ON reviews (product_id, user_id);
+CREATE INDEX idx_reviews_status ON reviews (status);
@@ -0,0 +1,26 @@
+CREATE TABLE wishlists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    share_token TEXT,
+    CONSTRAINT fk_users_wishlists FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_wishlists_user_id_name ON wishlists (user_id, name);
+CREATE UNIQUE INDEX idx_wishlists_share_token ON wishlists (share_token);
+
+CREATE TABLE wishlist_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    wishlist_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    CONSTRAINT fk_wishlists_items FOREIGN KEY (wishlist_id)
+        REFERENCES wishlists (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_wishlist_items_product FOREIGN KEY (product_id)
+        REFERENCES products (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_wishlist_items_wishlist_id_product_id ON wishlist_items (wishlist_id, product_id);
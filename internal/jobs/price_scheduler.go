@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+// PriceScheduler switches products to their scheduled prices once those
+// fall due. Each product is updated in a transaction of its own, so one
+// failing product does not hold back the others.
+type PriceScheduler struct {
+	repos    repository.Repositories
+	interval time.Duration
+}
+
+// NewPriceScheduler creates a scheduler that runs every interval
+func NewPriceScheduler(repos repository.Repositories, interval time.Duration) *PriceScheduler {
+	return &PriceScheduler{repos: repos, interval: interval}
+}
+
+// Run applies due prices once immediately and then on every tick until
+// ctx is done
+func (s *PriceScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ApplyOnce(time.Now()); err != nil {
+			log.Printf("Failed to apply scheduled prices: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ApplyOnce applies every pending scheduled price that is due at now
+func (s *PriceScheduler) ApplyOnce(now time.Time) error {
+	due, err := s.repos.Schedules.FindDue(now)
+	if err != nil {
+		return err
+	}
+
+	var applied int
+	done := make(map[uint]bool)
+	for _, schedule := range due {
+		if done[schedule.ProductID] {
+			continue
+		}
+		done[schedule.ProductID] = true
+
+		n, err := pricing.ApplyDue(s.repos, schedule.ProductID, now)
+		if err != nil {
+			log.Printf("Failed to apply scheduled prices of product %d: %v", schedule.ProductID, err)
+			continue
+		}
+		applied += n
+	}
+	if applied > 0 {
+		log.Printf("Applied %d scheduled prices", applied)
+	}
+	return nil
+}
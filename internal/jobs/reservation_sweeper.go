@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+// ReservationSweeper marks stock reservations whose TTL ran out as expired.
+// Expired holds already stop counting against available stock the moment
+// they run out; sweeping keeps their status truthful.
+type ReservationSweeper struct {
+	repos    repository.Repositories
+	interval time.Duration
+}
+
+// NewReservationSweeper creates a sweeper that runs every interval
+func NewReservationSweeper(repos repository.Repositories, interval time.Duration) *ReservationSweeper {
+	return &ReservationSweeper{repos: repos, interval: interval}
+}
+
+// Run sweeps once immediately and then on every tick until ctx is done
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SweepOnce(time.Now()); err != nil {
+			log.Printf("Failed to sweep reservations: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SweepOnce expires every active reservation that ran out before now
+func (s *ReservationSweeper) SweepOnce(now time.Time) error {
+	expired, err := s.repos.Reservations.ExpireBefore(now)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		log.Printf("Expired %d stock reservations", expired)
+	}
+	return nil
+}
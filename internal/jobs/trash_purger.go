@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+// TrashPurger permanently removes records that have been in the trash
+// for longer than the retention period.
+type TrashPurger struct {
+	repos     repository.Repositories
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewTrashPurger creates a purger that runs every interval
+func NewTrashPurger(repos repository.Repositories, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{repos: repos, retention: retention, interval: interval}
+}
+
+// Run purges once immediately and then on every tick until ctx is done
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.PurgeOnce(time.Now()); err != nil {
+			log.Printf("Failed to purge trash: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce removes everything trashed before now minus the retention period
+func (p *TrashPurger) PurgeOnce(now time.Time) error {
+	cutoff := now.Add(-p.retention)
+
+	purgers := []struct {
+		table string
+		purge func(time.Time) (int64, error)
+	}{
+		{"variants", p.repos.Variants.PurgeDeleted},
+		{"products", p.repos.Products.PurgeDeleted},
+		{"categories", p.repos.Categories.PurgeDeleted},
+		{"warehouses", p.repos.Warehouses.PurgeDeleted},
+		{"discount rules", p.repos.Discounts.PurgeDeleted},
+		{"coupons", p.repos.Coupons.PurgeDeleted},
+		{"users", p.repos.Users.PurgeDeleted},
+	}
+
+	for _, purger := range purgers {
+		purged, err := purger.purge(cutoff)
+		if err != nil {
+			return err
+		}
+		if purged > 0 {
+			log.Printf("Purged %d %s from the trash", purged, purger.table)
+		}
+	}
+	return nil
+}
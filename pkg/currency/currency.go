@@ -0,0 +1,109 @@
+// Package currency converts prices between currencies using a table of
+// exchange rates quoted against one base currency.
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// ErrUnknown is returned when a currency has no exchange rate
+var ErrUnknown = errors.New("unknown currency")
+
+// minorUnits lists the ISO 4217 currencies that do not use two decimals
+var minorUnits = map[string]int32{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+}
+
+// Normalize returns code in upper case without surrounding space
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Valid reports whether code has the shape of an ISO 4217 code
+func Valid(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimals returns the number of digits after the point ISO 4217 uses
+// for the currency
+func Decimals(code string) int32 {
+	if decimals, ok := minorUnits[code]; ok {
+		return decimals
+	}
+	return 2
+}
+
+// Rate is what one unit of the base currency is worth in another
+// currency, and how many decimals amounts in that currency are rounded to
+type Rate struct {
+	Rate     decimal.Decimal
+	Decimals int32
+}
+
+// Converter converts amounts between the base currency and the
+// currencies it has rates for
+type Converter struct {
+	Base  string
+	Rates map[string]Rate
+}
+
+// Supports reports whether amounts can be converted to or from code
+func (c Converter) Supports(code string) bool {
+	_, ok := c.rate(code)
+	return ok
+}
+
+// Convert turns an amount in currency from into currency to, rounded to
+// the decimals of to. The conversion goes through the base currency and
+// is rounded only once, at the end.
+func (c Converter) Convert(amount decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	source, ok := c.rate(from)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: %s", ErrUnknown, from)
+	}
+	target, ok := c.rate(to)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: %s", ErrUnknown, to)
+	}
+	if from == to {
+		return amount.Round(target.Decimals), nil
+	}
+	return amount.Mul(target.Rate).Div(source.Rate, target.Decimals), nil
+}
+
+// Round rounds an amount to the decimals of currency code
+func (c Converter) Round(amount decimal.Decimal, code string) decimal.Decimal {
+	return amount.Round(c.Decimals(code))
+}
+
+// Decimals returns how many digits after the point amounts in currency
+// code are rounded to
+func (c Converter) Decimals(code string) int32 {
+	if rate, ok := c.rate(code); ok {
+		return rate.Decimals
+	}
+	return Decimals(code)
+}
+
+func (c Converter) rate(code string) (Rate, bool) {
+	if code == c.Base {
+		return Rate{Rate: decimal.NewFromInt(1), Decimals: Decimals(code)}, true
+	}
+	rate, ok := c.Rates[code]
+	return rate, ok
+}
@@ -0,0 +1,343 @@
+// Package decimal provides an exact decimal number type for prices and
+// other amounts of money. Values are stored as NUMERIC in Postgres and as
+// TEXT in SQLite, and are written to JSON as strings so no client ever
+// sees them go through a binary float.
+package decimal
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number: coef × 10^-scale. The zero value is
+// 0. Decimals are immutable; every operation returns a new value.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+// Zero is the decimal 0
+var Zero = Decimal{}
+
+var errSyntax = errors.New("decimal: invalid syntax")
+
+// maxScale bounds the digits after the point and the exponent Parse
+// accepts, so a hostile input cannot make it allocate without limit
+const maxScale = 1000
+
+var (
+	bigOne = big.NewInt(1)
+	bigTen = big.NewInt(10)
+)
+
+// New returns coef × 10^-scale, e.g. New(1999, 2) is 19.99
+func New(coef int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{coef: new(big.Int).Mul(big.NewInt(coef), pow10(-scale))}
+	}
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// NewFromInt returns the decimal for a whole number
+func NewFromInt(value int64) Decimal {
+	return New(value, 0)
+}
+
+// NewFromFloat returns the shortest decimal that reads back as value.
+// It is only meant for values that already went through a float, such
+// as legacy database rows.
+func NewFromFloat(value float64) Decimal {
+	d, err := Parse(strconv.FormatFloat(value, 'f', -1, 64))
+	if err != nil {
+		return Zero
+	}
+	return d
+}
+
+// Parse reads a decimal such as "12", "-0.50" or "1.5e3"
+func Parse(s string) (Decimal, error) {
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		exponent, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || exponent > maxScale || exponent < -maxScale {
+			return Zero, fmt.Errorf("%w: %q", errSyntax, s)
+		}
+		mantissa = s[:i]
+	}
+
+	digits := mantissa
+	var scale int64
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		digits = mantissa[:i] + mantissa[i+1:]
+		scale = int64(len(mantissa) - i - 1)
+	}
+	unsigned := strings.TrimLeft(digits, "+-")
+	if unsigned == "" || len(digits)-len(unsigned) > 1 || strings.ContainsAny(unsigned, "+-") {
+		return Zero, fmt.Errorf("%w: %q", errSyntax, s)
+	}
+
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Zero, fmt.Errorf("%w: %q", errSyntax, s)
+	}
+
+	scale -= exponent
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+	if scale > maxScale {
+		return Zero, fmt.Errorf("%w: %q", errSyntax, s)
+	}
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustParse is like Parse but panics if s is not a decimal. It is meant
+// for constants.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1
+func (d Decimal) Sign() int {
+	if d.coef == nil {
+		return 0
+	}
+	return d.coef.Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// IsNegative reports whether d is below 0
+func (d Decimal) IsNegative() bool {
+	return d.Sign() < 0
+}
+
+// Cmp returns -1, 0 or +1 depending on whether d is less than, equal to
+// or greater than e. Trailing zeros do not matter: 1.50 equals 1.5.
+func (d Decimal) Cmp(e Decimal) int {
+	a, b := align(d, e)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and e are the same number
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Cmp(e) == 0
+}
+
+// LessThan reports whether d < e
+func (d Decimal) LessThan(e Decimal) bool {
+	return d.Cmp(e) < 0
+}
+
+// GreaterThan reports whether d > e
+func (d Decimal) GreaterThan(e Decimal) bool {
+	return d.Cmp(e) > 0
+}
+
+// Add returns d + e
+func (d Decimal) Add(e Decimal) Decimal {
+	a, b := align(d, e)
+	return Decimal{coef: a.Add(a, b), scale: max(d.scale, e.scale)}
+}
+
+// Sub returns d - e
+func (d Decimal) Sub(e Decimal) Decimal {
+	a, b := align(d, e)
+	return Decimal{coef: a.Sub(a, b), scale: max(d.scale, e.scale)}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Mul returns d × e. The result is exact.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), e.int()), scale: d.scale + e.scale}
+}
+
+// Div returns d ÷ e rounded half away from zero to scale digits after
+// the point. It panics if e is 0.
+func (d Decimal) Div(e Decimal, scale int32) Decimal {
+	if e.IsZero() {
+		panic("decimal: division by zero")
+	}
+	// d/e = (dc × 10^-ds) / (ec × 10^-es); scale the numerator so the
+	// integer quotient has one digit more than asked for, then round.
+	shift := scale + 1 + e.scale - d.scale
+	num := new(big.Int).Set(d.int())
+	den := new(big.Int).Set(e.int())
+	if shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	// Whatever is left past the extra digit cannot change which side of
+	// the half the digit is on, so the truncated quotient rounds correctly.
+	return roundDigit(num.Quo(num, den), scale)
+}
+
+// Round returns d rounded half away from zero to places digits after the
+// point. The result always has exactly places digits after the point, so
+// Round(2) turns 10.5 into 10.50.
+func (d Decimal) Round(places int32) Decimal {
+	if places >= d.scale {
+		return Decimal{coef: new(big.Int).Mul(d.int(), pow10(places-d.scale)), scale: places}
+	}
+	// Keep one extra digit and round it away
+	quo := new(big.Int).Quo(d.int(), pow10(d.scale-places-1))
+	return roundDigit(quo, places)
+}
+
+// Percent returns pct percent of d, exact
+func (d Decimal) Percent(pct Decimal) Decimal {
+	return d.Mul(pct).Mul(New(1, 2))
+}
+
+// String formats d with all of its digits after the point, e.g. "19.90"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// MarshalJSON writes d as a JSON string
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads a decimal written either as a string or as a
+// number. Numbers are read from their text, so they never go through a
+// float either.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner
+func (d *Decimal) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Zero
+		return nil
+	case int64:
+		*d = NewFromInt(v)
+		return nil
+	case float64:
+		*d = NewFromFloat(v)
+		return nil
+	case []byte:
+		return d.scanText(string(v))
+	case string:
+		return d.scanText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", value)
+	}
+}
+
+func (d *Decimal) scanText(text string) error {
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// int returns the coefficient, treating the zero value as 0. The result
+// must not be modified.
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// align returns the coefficients of d and e scaled to the same number of
+// digits after the point. Both results are fresh and may be modified.
+func align(d, e Decimal) (*big.Int, *big.Int) {
+	a := new(big.Int).Set(d.int())
+	b := new(big.Int).Set(e.int())
+	switch {
+	case d.scale < e.scale:
+		a.Mul(a, pow10(e.scale-d.scale))
+	case e.scale < d.scale:
+		b.Mul(b, pow10(d.scale-e.scale))
+	}
+	return a, b
+}
+
+// roundDigit drops the last digit of quo, rounding half away from zero,
+// and returns the rest as a decimal with the given scale
+func roundDigit(quo *big.Int, scale int32) Decimal {
+	last := new(big.Int)
+	quo.QuoRem(quo, bigTen, last)
+	if last.CmpAbs(big.NewInt(5)) >= 0 {
+		if last.Sign() < 0 {
+			quo.Sub(quo, bigOne)
+		} else {
+			quo.Add(quo, bigOne)
+		}
+	}
+	if scale < 0 {
+		return Decimal{coef: quo.Mul(quo, pow10(-scale))}
+	}
+	return Decimal{coef: quo, scale: scale}
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
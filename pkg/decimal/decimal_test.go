@@ -0,0 +1,407 @@
+package decimal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"12", "12"},
+		{"19.99", "19.99"},
+		{"19.90", "19.90"},
+		{"-0.50", "-0.50"},
+		{"+7.25", "7.25"},
+		{"-0", "0"},
+		{"-0.00", "0.00"},
+		{".5", "0.5"},
+		{"-.5", "-0.5"},
+		{"5.", "5"},
+		{"007.10", "7.10"},
+		{"1.5e3", "1500"},
+		{"1.5E3", "1500"},
+		{"1.5e+3", "1500"},
+		{"15e-1", "1.5"},
+		{"1.5e-3", "0.0015"},
+		{"-2e2", "-200"},
+		{"0e10", "0"},
+		{"123456789012345678901234567890.123456789", "123456789012345678901234567890.123456789"},
+		{"1e-1000", "0." + strings.Repeat("0", 999) + "1"},
+		{"0." + strings.Repeat("0", 999) + "1", "0." + strings.Repeat("0", 999) + "1"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{"only a sign", "-"},
+		{"only a point", "."},
+		{"signed point", "+."},
+		{"two signs", "--1"},
+		{"mixed signs", "+-1"},
+		{"trailing sign", "1-"},
+		{"sign after the point", "1.-5"},
+		{"two points", "1.2.3"},
+		{"letters", "abc"},
+		{"hexadecimal", "0x10"},
+		{"underscores", "1_000"},
+		{"comma", "1,5"},
+		{"leading space", " 1"},
+		{"trailing space", "1 "},
+		{"empty exponent", "1e"},
+		{"exponent without mantissa", "e5"},
+		{"fractional exponent", "1e1.5"},
+		{"two exponents", "1e2e3"},
+		{"exponent too large", "1e1001"},
+		{"exponent too small", "1e-1001"},
+		{"exponent overflows", "1e99999999999"},
+		{"too many digits after the point", "0." + strings.Repeat("1", 1001)},
+		{"too many digits after the exponent", "1.5e-1000"},
+		{"infinity", "Inf"},
+		{"not a number", "NaN"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, err := Parse(test.in); err == nil {
+				t.Fatalf("Parse(%q) = %s, want an error", test.in, got)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		coef  int64
+		scale int32
+		want  string
+	}{
+		{1999, 2, "19.99"},
+		{-5, 3, "-0.005"},
+		{0, 2, "0.00"},
+		{12, -2, "1200"},
+	}
+	for _, test := range tests {
+		if got := New(test.coef, test.scale).String(); got != test.want {
+			t.Errorf("New(%d, %d) = %s, want %s", test.coef, test.scale, got, test.want)
+		}
+	}
+	if got := NewFromFloat(0.1).String(); got != "0.1" {
+		t.Errorf("NewFromFloat(0.1) = %s, want 0.1", got)
+	}
+	if got := Zero.String(); got != "0" {
+		t.Errorf("Zero = %s, want 0", got)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int32
+		want   string
+	}{
+		// Ties go away from zero
+		{"2.345", 2, "2.35"},
+		{"-2.345", 2, "-2.35"},
+		{"2.5", 0, "3"},
+		{"-2.5", 0, "-3"},
+		{"0.5", 0, "1"},
+		{"-0.5", 0, "-1"},
+		{"1.005", 2, "1.01"},
+		{"0.125", 2, "0.13"},
+		{"-0.125", 2, "-0.13"},
+		{"9.995", 2, "10.00"},
+		{"-9.995", 2, "-10.00"},
+		{"15", -1, "20"},
+		{"-25", -1, "-30"},
+
+		// Anything short of a tie goes to the nearer value
+		{"2.344", 2, "2.34"},
+		{"2.3449999", 2, "2.34"},
+		{"2.3450001", 2, "2.35"},
+		{"-2.344", 2, "-2.34"},
+		{"0.4", 0, "0"},
+		{"-0.4", 0, "0"},
+		{"0.49999", 0, "0"},
+
+		// Rounding to more places pads with zeros
+		{"10.5", 2, "10.50"},
+		{"7", 2, "7.00"},
+		{"-1.2", 3, "-1.200"},
+		{"0", 2, "0.00"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got := MustParse(test.in).Round(test.places)
+			if got.String() != test.want {
+				t.Fatalf("Round(%d) = %s, want %s", test.places, got, test.want)
+			}
+			if test.places >= 0 && got.Scale() != test.places {
+				t.Fatalf("got scale %d, want %d", got.Scale(), test.places)
+			}
+		})
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		d, e  string
+		scale int32
+		want  string
+	}{
+		{"1", "3", 2, "0.33"},
+		{"2", "3", 2, "0.67"},
+		{"-2", "3", 2, "-0.67"},
+		{"2", "-3", 2, "-0.67"},
+		{"-2", "-3", 2, "0.67"},
+		{"1", "8", 2, "0.13"},
+		{"-1", "8", 2, "-0.13"},
+		{"1", "8", 3, "0.125"},
+		{"10", "4", 0, "3"},
+		{"-10", "4", 0, "-3"},
+		{"9", "4", 0, "2"},
+		{"1.00", "0.5", 2, "2.00"},
+		{"0.005", "1", 2, "0.01"},
+		{"0.0001", "1", 2, "0.00"},
+		{"100", "0.03", 4, "3333.3333"},
+		{"85.68", "1.19", 2, "72.00"},
+		{"0", "7", 2, "0.00"},
+		{"1", "3", 20, "0.33333333333333333333"},
+		{"12345678901234567890", "1", 2, "12345678901234567890.00"},
+	}
+	for _, test := range tests {
+		t.Run(test.d+"/"+test.e, func(t *testing.T) {
+			got := MustParse(test.d).Div(MustParse(test.e), test.scale)
+			if got.String() != test.want {
+				t.Fatalf("Div = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDivByZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("dividing by zero did not panic")
+		}
+	}()
+	NewFromInt(1).Div(MustParse("0.00"), 2)
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := MustParse("19.99"), MustParse("0.011")
+	tests := []struct {
+		name string
+		got  Decimal
+		want string
+	}{
+		{"add", a.Add(b), "20.001"},
+		{"sub", a.Sub(b), "19.979"},
+		{"sub below zero", b.Sub(a), "-19.979"},
+		{"mul", a.Mul(b), "0.21989"},
+		{"neg", a.Neg(), "-19.99"},
+		{"abs", a.Neg().Abs(), "19.99"},
+		{"percent", MustParse("80.00").Percent(MustParse("19")), "15.2000"},
+		{"zero value", Zero.Add(a), "19.99"},
+	}
+	for _, test := range tests {
+		if test.got.String() != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	tests := []struct {
+		d, e string
+		want int
+	}{
+		{"1.50", "1.5", 0},
+		{"0", "-0.00", 0},
+		{"1.49", "1.5", -1},
+		{"-1.5", "-1.49", -1},
+		{"10", "9.999", 1},
+	}
+	for _, test := range tests {
+		if got := MustParse(test.d).Cmp(MustParse(test.e)); got != test.want {
+			t.Errorf("%s.Cmp(%s) = %d, want %d", test.d, test.e, got, test.want)
+		}
+	}
+	if Zero.Cmp(MustParse("0.00")) != 0 || !Zero.IsZero() {
+		t.Error("the zero value is not 0")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`"19.90"`, "19.90"},
+		{`19.90`, "19.90"},
+		{`-1e2`, "-100"},
+		{`"0.1"`, "0.1"},
+	}
+	for _, test := range tests {
+		var d Decimal
+		if err := json.Unmarshal([]byte(test.in), &d); err != nil {
+			t.Fatalf("unmarshaling %s: %v", test.in, err)
+		}
+		if d.String() != test.want {
+			t.Errorf("unmarshaling %s gave %s, want %s", test.in, d, test.want)
+		}
+		out, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != `"`+test.want+`"` {
+			t.Errorf("marshaling %s gave %s", d, out)
+		}
+	}
+
+	d := MustParse("5")
+	if err := json.Unmarshal([]byte("null"), &d); err != nil || d.String() != "5" {
+		t.Errorf("null changed the decimal to %s (%v)", d, err)
+	}
+	for _, in := range []string{`"abc"`, `true`, `"1e"`} {
+		if err := json.Unmarshal([]byte(in), &d); err == nil {
+			t.Errorf("unmarshaling %s did not fail", in)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{"nil", nil, "0"},
+		{"int64", int64(42), "42"},
+		{"float64", 19.9, "19.9"},
+		{"string", "19.90", "19.90"},
+		{"bytes", []byte("-0.005"), "-0.005"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := MustParse("1")
+			if err := d.Scan(test.in); err != nil {
+				t.Fatal(err)
+			}
+			if d.String() != test.want {
+				t.Fatalf("got %s, want %s", d, test.want)
+			}
+		})
+	}
+
+	var d Decimal
+	for _, in := range []interface{}{true, "abc", []byte("1.2.3")} {
+		if err := d.Scan(in); err == nil {
+			t.Errorf("scanning %#v did not fail", in)
+		}
+	}
+}
+
+// Postgres hands NUMERIC columns to the driver as text, the same form
+// Value writes, so every decimal must survive the trip exactly
+func TestValueScanText(t *testing.T) {
+	for _, in := range []string{"0", "19.90", "-0.005", "123456789012345678901234567890.123456789"} {
+		value, err := MustParse(in).Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		text, ok := value.(string)
+		if !ok {
+			t.Fatalf("Value returned %T, want a string", value)
+		}
+
+		for _, scanned := range []interface{}{text, []byte(text)} {
+			var d Decimal
+			if err := d.Scan(scanned); err != nil {
+				t.Fatal(err)
+			}
+			if d.String() != in {
+				t.Errorf("%s came back as %s from %T", in, d, scanned)
+			}
+		}
+	}
+}
+
+// SQLite keeps TEXT columns as written, which is why the SQLite
+// migrations store decimals as TEXT. NUMERIC columns turn them into
+// integers or floats, which Scan still reads back as the same number as
+// long as a float holds it exactly, but without the trailing zeros.
+func TestValueScanSQLite(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	sqlDB.SetMaxOpenConns(1)
+	if err := conn.Exec("CREATE TABLE amounts (id INTEGER PRIMARY KEY, as_text TEXT, as_numeric NUMERIC)").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		in         string
+		numeric    string
+		exactFloat bool
+	}{
+		{"0", "0", true},
+		{"19.90", "19.9", true},
+		{"-0.005", "-0.005", true},
+		{"1500", "1500", true},
+		{"100.00", "100", true},
+		{"123456789012345678901234567890.123456789", "", false},
+	}
+	for i, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			d := MustParse(test.in)
+			if err := conn.Exec("INSERT INTO amounts (id, as_text, as_numeric) VALUES (?, ?, ?)", i+1, d, d).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			var text, numeric Decimal
+			if err := conn.Raw("SELECT as_text, as_numeric FROM amounts WHERE id = ?", i+1).Row().Scan(&text, &numeric); err != nil {
+				t.Fatal(err)
+			}
+			if text.String() != test.in {
+				t.Errorf("TEXT gave back %s", text)
+			}
+			if !test.exactFloat {
+				if numeric.Equal(d) {
+					t.Errorf("NUMERIC kept %s exactly; SQLite could store decimals as NUMERIC after all", d)
+				}
+				return
+			}
+			if numeric.String() != test.numeric || !numeric.Equal(d) {
+				t.Errorf("NUMERIC gave back %s, want %s", numeric, test.numeric)
+			}
+		})
+	}
+}
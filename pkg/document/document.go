@@ -0,0 +1,310 @@
+// Package document renders the paperwork of an order, its invoice and its
+// packing slip, as HTML and as PDF. Both are produced in pure Go.
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+// Company is the seller printed at the top of every document
+type Company struct {
+	Name    string
+	Address string
+	Email   string
+	TaxID   string
+}
+
+// Customer is who an order was placed by
+type Customer struct {
+	Name  string
+	Email string
+}
+
+// TaxLine is one tax amount listed on an invoice
+type TaxLine struct {
+	Label  string
+	Amount decimal.Decimal
+}
+
+// Invoice is everything printed on the invoice of an order
+type Invoice struct {
+	Number   string
+	IssuedAt time.Time
+	Company  Company
+	Customer Customer
+	Order    models.Order
+	TaxLines []TaxLine
+}
+
+// PackingSlip is everything printed on the packing slip of an order. It
+// lists what to pack without any prices.
+type PackingSlip struct {
+	Company  Company
+	Customer Customer
+	Order    models.Order
+}
+
+const dateLayout = "2006-01-02"
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format(dateLayout) },
+}).Parse(`
+{{define "head"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 14px; margin: 40px; color: #222; }
+h1 { font-size: 24px; margin: 0 0 8px; }
+table { width: 100%; border-collapse: collapse; margin-top: 24px; }
+th, td { padding: 6px 4px; border-bottom: 1px solid #ccc; text-align: left; }
+.number { text-align: right; }
+.totals td { border: none; }
+.total td { font-weight: bold; }
+.muted { color: #666; }
+</style>
+</head>
+<body>{{end}}
+
+{{define "company"}}<div>
+<strong>{{.Name}}</strong><br>
+{{with .Address}}{{.}}<br>{{end}}
+{{with .Email}}{{.}}<br>{{end}}
+{{with .TaxID}}Tax ID: {{.}}{{end}}
+</div>{{end}}
+
+{{define "invoice"}}{{template "head" (print "Invoice " .Number)}}
+{{template "company" .Company}}
+<h1>Invoice {{.Number}}</h1>
+<p>Date: {{date .IssuedAt}}<br>Order: {{.Order.ID}}</p>
+<p><span class="muted">Bill to</span><br>{{.Customer.Name}}<br>{{.Customer.Email}}</p>
+<table>
+<tr><th>Item</th><th class="number">Qty</th><th class="number">Unit price</th><th class="number">Amount</th></tr>
+{{range .Order.Items}}<tr><td>{{.Name}}{{with .SKU}} ({{.}}){{end}}</td><td class="number">{{.Quantity}}</td><td class="number">{{.UnitPrice}}</td><td class="number">{{.LineTotal}}</td></tr>
+{{end}}</table>
+<table class="totals">
+<tr><td></td><td class="number">Subtotal</td><td class="number">{{.Order.Subtotal}}</td></tr>
+{{if .Order.CouponCode}}<tr><td></td><td class="number">Discount ({{.Order.CouponCode}})</td><td class="number">-{{.Order.Discount}}</td></tr>
+{{end}}{{range .TaxLines}}<tr><td></td><td class="number">{{.Label}}</td><td class="number">{{.Amount}}</td></tr>
+{{end}}<tr class="total"><td></td><td class="number">Total ({{.Order.Currency}})</td><td class="number">{{.Order.Total}}</td></tr>
+</table>
+</body>
+</html>
+{{end}}
+
+{{define "packing-slip"}}{{template "head" (print "Packing slip for order " .Order.ID)}}
+{{template "company" .Company}}
+<h1>Packing slip</h1>
+<p>Order: {{.Order.ID}}<br>Placed: {{date .Order.CreatedAt}}</p>
+<p><span class="muted">Ship to</span><br>{{.Customer.Name}}<br>{{.Customer.Email}}</p>
+<table>
+<tr><th>Item</th><th class="number">Product</th><th class="number">Qty</th></tr>
+{{range .Order.Items}}<tr><td>{{.Name}}{{with .SKU}} ({{.}}){{end}}</td><td class="number">{{with .ProductID}}{{.}}{{end}}</td><td class="number">{{.Quantity}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+{{end}}
+`))
+
+// HTML renders the invoice as a standalone HTML page
+func (inv Invoice) HTML() ([]byte, error) {
+	var out bytes.Buffer
+	if err := templates.ExecuteTemplate(&out, "invoice", inv); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// PDF renders the invoice as a PDF file
+func (inv Invoice) PDF() []byte {
+	s := newSheet()
+	s.header(inv.Company, "INVOICE", []string{
+		"Invoice " + inv.Number,
+		"Date " + inv.IssuedAt.Format(dateLayout),
+		fmt.Sprintf("Order %d", inv.Order.ID),
+	})
+	s.party("Bill to", inv.Customer)
+
+	s.table([]column{
+		{title: "Item", x: margin},
+		{title: "Qty", x: 360, right: true},
+		{title: "Unit price", x: 450, right: true},
+		{title: "Amount", x: pageWidth - margin, right: true},
+	})
+	for _, item := range inv.Order.Items {
+		s.row(itemName(item), fmt.Sprint(item.Quantity), item.UnitPrice.String(), item.LineTotal.String())
+	}
+
+	s.y += 10
+	s.total("Subtotal", inv.Order.Subtotal.String(), false)
+	if inv.Order.CouponCode != "" {
+		s.total("Discount ("+inv.Order.CouponCode+")", "-"+inv.Order.Discount.String(), false)
+	}
+	for _, line := range inv.TaxLines {
+		s.total(line.Label, line.Amount.String(), false)
+	}
+	s.total("Total ("+inv.Order.Currency+")", inv.Order.Total.String(), true)
+	return s.pdf.bytes()
+}
+
+// HTML renders the packing slip as a standalone HTML page
+func (slip PackingSlip) HTML() ([]byte, error) {
+	var out bytes.Buffer
+	if err := templates.ExecuteTemplate(&out, "packing-slip", slip); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// PDF renders the packing slip as a PDF file
+func (slip PackingSlip) PDF() []byte {
+	s := newSheet()
+	s.header(slip.Company, "PACKING SLIP", []string{
+		fmt.Sprintf("Order %d", slip.Order.ID),
+		"Placed " + slip.Order.CreatedAt.Format(dateLayout),
+	})
+	s.party("Ship to", slip.Customer)
+
+	s.table([]column{
+		{title: "Item", x: margin},
+		{title: "Product", x: 450, right: true},
+		{title: "Qty", x: pageWidth - margin, right: true},
+	})
+	for _, item := range slip.Order.Items {
+		product := ""
+		if item.ProductID != nil {
+			product = fmt.Sprint(*item.ProductID)
+		}
+		s.row(itemName(item), product, fmt.Sprint(item.Quantity))
+	}
+	return s.pdf.bytes()
+}
+
+// itemName returns the name an order item is listed under, followed by
+// the SKU of its variant, if any
+func itemName(item models.OrderItem) string {
+	if item.SKU == "" {
+		return item.Name
+	}
+	return item.Name + " (" + item.SKU + ")"
+}
+
+// column is one column of a table on a sheet. Right-aligned columns end
+// at x; the others start there.
+type column struct {
+	title string
+	x     float64
+	right bool
+}
+
+// sheet keeps track of where the next line of a document goes and starts
+// a new page when one fills up
+type sheet struct {
+	pdf     *pdfWriter
+	y       float64
+	columns []column
+}
+
+func newSheet() *sheet {
+	return &sheet{pdf: newPDF(), y: margin}
+}
+
+// header prints the company at the top left and the title of the
+// document with its details at the top right
+func (s *sheet) header(company Company, title string, details []string) {
+	s.pdf.textRight(pageWidth-margin, s.y+14, 18, true, title)
+	for i, detail := range details {
+		s.pdf.textRight(pageWidth-margin, s.y+34+float64(i)*14, 10, false, detail)
+	}
+
+	s.pdf.text(margin, s.y+14, 14, true, company.Name)
+	y := s.y + 34
+	for _, line := range []string{company.Address, company.Email, taxID(company.TaxID)} {
+		if line != "" {
+			s.pdf.text(margin, y, 10, false, line)
+			y += 14
+		}
+	}
+	s.y = max(y, s.y+34+float64(len(details))*14) + 20
+}
+
+// party prints who the document is addressed to under a caption
+func (s *sheet) party(caption string, customer Customer) {
+	s.pdf.text(margin, s.y, 9, true, caption)
+	s.y += 14
+	for _, line := range []string{customer.Name, customer.Email} {
+		if line != "" {
+			s.pdf.text(margin, s.y, 10, false, line)
+			s.y += 14
+		}
+	}
+	s.y += 16
+}
+
+// table starts a table with a head row of the column titles
+func (s *sheet) table(columns []column) {
+	s.columns = columns
+	s.cells(true, titles(columns))
+}
+
+// row prints one line of the current table. A row that does not fit goes
+// on a new page under a repeated head row.
+func (s *sheet) row(cells ...string) {
+	if s.y > pageHeight-margin-20 {
+		s.pdf.addPage()
+		s.y = margin
+		s.cells(true, titles(s.columns))
+	}
+	s.cells(false, cells)
+}
+
+func (s *sheet) cells(bold bool, cells []string) {
+	for i, cell := range cells {
+		col := s.columns[i]
+		if col.right {
+			s.pdf.textRight(col.x, s.y, 10, bold, cell)
+			continue
+		}
+		// Left-aligned text stops short of the next column's widest value
+		limit := pageWidth - margin - col.x
+		if i+1 < len(s.columns) {
+			limit = s.columns[i+1].x - col.x - 90
+		}
+		s.pdf.text(col.x, s.y, 10, bold, truncate(cell, limit, 10, bold))
+	}
+	s.pdf.line(margin, s.y+5, pageWidth-margin, s.y+5)
+	s.y += 18
+}
+
+// total prints a caption and an amount at the right of the sheet
+func (s *sheet) total(caption, amount string, bold bool) {
+	if s.y > pageHeight-margin {
+		s.pdf.addPage()
+		s.y = margin
+	}
+	s.pdf.textRight(450, s.y, 10, bold, caption)
+	s.pdf.textRight(pageWidth-margin, s.y, 10, bold, amount)
+	s.y += 16
+}
+
+func titles(columns []column) []string {
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = col.title
+	}
+	return titles
+}
+
+func taxID(id string) string {
+	if id == "" {
+		return ""
+	}
+	return "Tax ID: " + id
+}
@@ -0,0 +1,151 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page size in points
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 50.0
+)
+
+// helveticaWidths are the advances of the printable ASCII characters in
+// Helvetica, in thousandths of the font size, starting at the space
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// pdfWriter lays out text and rules on A4 pages and writes them as a PDF
+// 1.4 file. It only uses the standard Helvetica fonts, which every viewer
+// has, so nothing needs to be embedded. Positions are in points from the
+// top left corner of the page.
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+}
+
+func newPDF() *pdfWriter {
+	p := &pdfWriter{}
+	p.addPage()
+	return p
+}
+
+func (p *pdfWriter) addPage() {
+	p.page = &bytes.Buffer{}
+	p.pages = append(p.pages, p.page)
+}
+
+// text writes s with its left edge at x and its baseline at y
+func (p *pdfWriter) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, pageHeight-y, escape(s))
+}
+
+// textRight writes s with its right edge at x
+func (p *pdfWriter) textRight(x, y, size float64, bold bool, s string) {
+	p.text(x-textWidth(s, size, bold), y, size, bold, s)
+}
+
+// line draws a thin grey rule
+func (p *pdfWriter) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(p.page, "0.6 G 0.5 w %.2f %.2f m %.2f %.2f l S 0 G\n", x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+// bytes returns the finished document
+func (p *pdfWriter) bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Objects 1 to 4 are the catalog, the page tree and the two fonts;
+	// every page then takes two objects, itself and its content stream
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range p.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// escape turns s into the body of a PDF string in WinAnsiEncoding.
+// Characters the encoding lacks are replaced by a question mark.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteString(`\200`)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, `\%03o`, r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// textWidth estimates how wide s is set in Helvetica. Bold letters are
+// taken to be a tenth wider, which is close enough to align columns.
+func textWidth(s string, size float64, bold bool) float64 {
+	var units float64
+	for _, r := range s {
+		width := 556.0
+		if r >= ' ' && r <= '~' {
+			width = float64(helveticaWidths[r-' '])
+		}
+		if bold && (r < '0' || r > '9') {
+			width *= 1.1
+		}
+		units += width
+	}
+	return units * size / 1000
+}
+
+// truncate shortens s with an ellipsis until it fits in width
+func truncate(s string, width, size float64, bold bool) string {
+	if textWidth(s, size, bold) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
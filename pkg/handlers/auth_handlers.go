@@ -6,8 +6,6 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
-	"github.com/santoadji21/santoadji21-go-fiber-product-api/internal/db"
-	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
 )
 
@@ -24,7 +22,7 @@ import (
 // @Failure 401 {object} utils.ApiResponse "Unauthorized"
 // @Failure 500 {object} utils.ApiResponse "Internal server error"
 // @Router /api/login [post]
-func Login(c *fiber.Ctx) error {
+func (h *UserHandler) Login(c *fiber.Ctx) error {
 	type LoginRequest struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
@@ -38,9 +36,8 @@ func Login(c *fiber.Ctx) error {
 		})
 	}
 
-	var user models.User
-	result := db.GetDB().Where("email = ?", request.Email).First(&user)
-	if result.Error != nil {
+	user, err := h.repos.Users.FindByEmail(request.Email)
+	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "User not found",
@@ -56,16 +53,18 @@ func Login(c *fiber.Ctx) error {
 		})
 	}
 
+	jwtCfg := config.JwtCfg()
+
 	// Create token
 	token := jwt.New(jwt.SigningMethodHS256)
 
 	// Set claims
 	claims := token.Claims.(jwt.MapClaims)
 	claims["user_id"] = user.ID
+	claims["admin"] = user.Admin
 	claims["exp"] = time.Now().Add(time.Hour * 72).Unix()
 
 	// Generate encoded token
-	jwtCfg := config.JwtCfg()
 	t, err := token.SignedString([]byte(jwtCfg.SecretKey))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Error generating token")
@@ -77,3 +76,22 @@ func Login(c *fiber.Ctx) error {
 		Data:    fiber.Map{"token": t},
 	})
 }
+
+// currentUserID returns the user_id claim of the request's JWT, or nil
+// when the route is not protected or the claim is missing
+func currentUserID(c *fiber.Ctx) *uint {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return nil
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok || userID <= 0 {
+		return nil
+	}
+	id := uint(userID)
+	return &id
+}
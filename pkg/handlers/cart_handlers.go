@@ -0,0 +1,508 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+var (
+	errCartItemNotFound = errors.New("cart item not found")
+	errVariantNotFound  = errors.New("variant not found")
+)
+
+// CartHandler serves the shopping cart endpoints. Every user has one
+// cart, found through the user_id claim of their token.
+type CartHandler struct {
+	repos    repository.Repositories
+	currency config.CurrencyConfig
+	tax      config.TaxConfig
+}
+
+// NewCartHandler creates a CartHandler backed by the given repositories
+func NewCartHandler(repos repository.Repositories, currencyCfg config.CurrencyConfig, taxCfg config.TaxConfig) *CartHandler {
+	return &CartHandler{repos: repos, currency: currencyCfg, tax: taxCfg}
+}
+
+// CartItemRequest is the body of a cart item. VariantID picks one of the
+// product's variants; 0 means the product itself. ProductID and VariantID
+// are only read when adding an item.
+type CartItemRequest struct {
+	ProductID uint `json:"product_id"`
+	VariantID uint `json:"variant_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+// GetCart - Handler for getting the current user's cart
+// @Summary Get the cart
+// @Description Retrieves the current user's cart priced at the current prices, after the
+// @Description discount rules in force, in currency (the base currency by default), and taxed
+// @Description for region (the configured one by default). Items of products in the trash
+// @Description are left out.
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param currency query string false "ISO 4217 currency of the prices"
+// @Param region query string false "Tax region the prices are taxed for"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} utils.ApiResponse "Unknown currency or tax region"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Router /api/cart [get]
+func (h *CartHandler) GetCart(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	target, ok := h.cartCurrency(c)
+	if !ok {
+		return invalidCurrency(c)
+	}
+	region, ok := taxRegion(c, h.repos, h.tax)
+	if !ok {
+		return invalidRegion(c)
+	}
+
+	return h.respondCart(c, fiber.StatusOK, "Cart retrieved successfully", *userID, target, region)
+}
+
+// AddCartItem - Handler for adding a product to the cart
+// @Summary Add a cart item
+// @Description Adds quantity units of a product, or of one of its variants with variant_id, to
+// @Description the current user's cart, on top of any already in it. The cart cannot hold
+// @Description more units than are available; a variant only has its own stock.
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param currency query string false "ISO 4217 currency of the prices"
+// @Param region query string false "Tax region the prices are taxed for"
+// @Param item body CartItemRequest true "Cart item"
+// @Success 201 {object} models.Cart
+// @Failure 400 {object} utils.ApiResponse "Invalid cart item"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Product or variant not found"
+// @Failure 409 {object} utils.ApiResponse "Insufficient stock"
+// @Router /api/cart/items [post]
+func (h *CartHandler) AddCartItem(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	target, ok := h.cartCurrency(c)
+	if !ok {
+		return invalidCurrency(c)
+	}
+	region, ok := taxRegion(c, h.repos, h.tax)
+	if !ok {
+		return invalidRegion(c)
+	}
+
+	var request CartItemRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if request.Quantity <= 0 {
+		return invalidCartItem(c, "quantity must be positive")
+	}
+
+	err := h.repos.Transaction(func(tx repository.Repositories) error {
+		quantity := request.Quantity
+		item, err := tx.Carts.FindItem(*userID, request.ProductID, request.VariantID)
+		if err == nil {
+			quantity += item.Quantity
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		return setCartItem(tx, *userID, request.ProductID, request.VariantID, quantity)
+	})
+	if err != nil {
+		return h.cartItemError(c, err, "Failed to add cart item")
+	}
+
+	return h.respondCart(c, fiber.StatusCreated, "Cart item added successfully", *userID, target, region)
+}
+
+// UpdateCartItem - Handler for changing the quantity of a cart item
+// @Summary Update a cart item
+// @Description Sets the quantity of a product, or of the variant named by variant_id, in the
+// @Description current user's cart. The cart cannot hold more units than are available.
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param productId path int true "Product ID"
+// @Param variant_id query int false "Variant ID, for the item of a variant"
+// @Param currency query string false "ISO 4217 currency of the prices"
+// @Param region query string false "Tax region the prices are taxed for"
+// @Param item body CartItemRequest true "Cart item"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} utils.ApiResponse "Invalid cart item"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Cart item not found"
+// @Failure 409 {object} utils.ApiResponse "Insufficient stock"
+// @Router /api/cart/items/{productId} [patch]
+func (h *CartHandler) UpdateCartItem(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	productID, err := paramUint(c, "productId")
+	if err != nil {
+		return invalidID(c)
+	}
+	variantID, ok := queryVariantID(c)
+	if !ok {
+		return invalidID(c)
+	}
+	target, ok := h.cartCurrency(c)
+	if !ok {
+		return invalidCurrency(c)
+	}
+	region, ok := taxRegion(c, h.repos, h.tax)
+	if !ok {
+		return invalidRegion(c)
+	}
+
+	var request CartItemRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if request.Quantity <= 0 {
+		return invalidCartItem(c, "quantity must be positive")
+	}
+
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if _, err := tx.Carts.FindItem(*userID, productID, variantID); err != nil {
+			return errCartItemNotFound
+		}
+		return setCartItem(tx, *userID, productID, variantID, request.Quantity)
+	})
+	if err != nil {
+		return h.cartItemError(c, err, "Failed to update cart item")
+	}
+
+	return h.respondCart(c, fiber.StatusOK, "Cart item updated successfully", *userID, target, region)
+}
+
+// DeleteCartItem - Handler for removing a product from the cart
+// @Summary Remove a cart item
+// @Description Removes a product, or the variant named by variant_id, from the current
+// @Description user's cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param productId path int true "Product ID"
+// @Param variant_id query int false "Variant ID, for the item of a variant"
+// @Param currency query string false "ISO 4217 currency of the prices"
+// @Param region query string false "Tax region the prices are taxed for"
+// @Success 200 {object} models.Cart
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Cart item not found"
+// @Router /api/cart/items/{productId} [delete]
+func (h *CartHandler) DeleteCartItem(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	productID, err := paramUint(c, "productId")
+	if err != nil {
+		return invalidID(c)
+	}
+	variantID, ok := queryVariantID(c)
+	if !ok {
+		return invalidID(c)
+	}
+	target, ok := h.cartCurrency(c)
+	if !ok {
+		return invalidCurrency(c)
+	}
+	region, ok := taxRegion(c, h.repos, h.tax)
+	if !ok {
+		return invalidRegion(c)
+	}
+
+	if err := h.repos.Carts.Remove(*userID, productID, variantID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return cartItemNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to remove cart item",
+			Data:    err.Error(),
+		})
+	}
+
+	return h.respondCart(c, fiber.StatusOK, "Cart item removed successfully", *userID, target, region)
+}
+
+// ClearCart - Handler for emptying the cart
+// @Summary Clear the cart
+// @Description Removes every item from the current user's cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Cart
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Router /api/cart [delete]
+func (h *CartHandler) ClearCart(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+
+	if err := h.repos.Carts.Clear(*userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to clear cart",
+			Data:    err.Error(),
+		})
+	}
+
+	return h.respondCart(c, fiber.StatusOK, "Cart cleared successfully", *userID, h.currency.Base, h.tax.Region)
+}
+
+// setCartItem sets the quantity of a product, or of one of its variants
+// when variantID is not 0, in a user's cart, as long as that many units
+// are available. The product stays locked until tx ends, so the check
+// cannot be raced.
+func setCartItem(tx repository.Repositories, userID, productID, variantID uint, quantity int) error {
+	if err := tx.Products.Lock(productID); err != nil {
+		return err
+	}
+	item := models.CartItem{UserID: userID, ProductID: productID, VariantID: variantRef(variantID), Quantity: quantity}
+	available, err := lineAvailable(tx, item)
+	if err != nil {
+		return err
+	}
+	if quantity > available {
+		return repository.ErrInsufficientStock
+	}
+	return tx.Carts.Set(&item)
+}
+
+// lineAvailable returns how many units of the product or variant of item
+// can still be bought. Variants only have their own stock, which is never
+// reserved.
+func lineAvailable(repos repository.Repositories, item models.CartItem) (int, error) {
+	if item.VariantID == nil {
+		return availableQty(repos, item.ProductID)
+	}
+	if _, err := repos.Products.FindByID(item.ProductID); err != nil {
+		return 0, err
+	}
+	variant, err := repos.Variants.FindByID(*item.VariantID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, errVariantNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if variant.ProductID != item.ProductID {
+		return 0, errVariantNotFound
+	}
+	return variant.Qty, nil
+}
+
+// variantRef returns a reference to variantID, or nil for 0, which names
+// the product itself
+func variantRef(variantID uint) *uint {
+	if variantID == 0 {
+		return nil
+	}
+	return &variantID
+}
+
+// queryVariantID reads the optional "variant_id" query parameter naming
+// the variant a cart item is for. It reports false when it is not a
+// positive integer.
+func queryVariantID(c *fiber.Ctx) (uint, bool) {
+	if c.Query("variant_id") == "" {
+		return 0, true
+	}
+	variantID := c.QueryInt("variant_id")
+	return uint(variantID), variantID > 0
+}
+
+// cartCurrency reads the currency query parameter, defaulting to the base
+// currency. It reports false when prices cannot be given in it.
+func (h *CartHandler) cartCurrency(c *fiber.Ctx) (string, bool) {
+	target := currency.Normalize(strings.Clone(c.Query("currency")))
+	if target == "" || target == h.currency.Base {
+		return h.currency.Base, true
+	}
+	_, err := h.repos.Currencies.FindRate(target)
+	return target, err == nil
+}
+
+// respondCart writes the user's priced cart as the response
+func (h *CartHandler) respondCart(c *fiber.Ctx, status int, message string, userID uint, target, region string) error {
+	items, err := h.repos.Carts.FindByUser(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve cart",
+			Data:    err.Error(),
+		})
+	}
+	cart, err := priceCart(h.repos, h.currency, h.tax, userID, items, target, region)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to price cart",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(status).JSON(utils.ApiResponse{
+		Success: true,
+		Message: message,
+		Data:    cart,
+	})
+}
+
+// cartItemError writes the response for an error from changing a cart item
+func (h *CartHandler) cartItemError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, errCartItemNotFound):
+		return cartItemNotFound(c)
+	case errors.Is(err, errVariantNotFound):
+		return variantNotFound(c)
+	case errors.Is(err, repository.ErrNotFound), errors.Is(err, repository.ErrInvalidReference):
+		return productNotFound(c)
+	case errors.Is(err, repository.ErrInsufficientStock):
+		return insufficientStock(c)
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+		Success: false,
+		Message: message,
+		Data:    err.Error(),
+	})
+}
+
+// priceCart prices cart items at the current prices, after the discount
+// rules in force, in the target currency and taxes them for region. Items
+// of a variant are priced at its price override, if it has one. Items
+// whose product or variant is gone are left out.
+func priceCart(repos repository.Repositories, cfg config.CurrencyConfig, taxCfg config.TaxConfig, userID uint, items []models.CartItem, target, region string) (*models.Cart, error) {
+	// Every item is priced as a product of its own, carrying its variant
+	// if it has one, so two variants of a product can differ in price
+	lines := make([]models.CartItem, 0, len(items))
+	products := make([]models.Product, 0, len(items))
+	for _, item := range items {
+		product, err := repos.Products.FindByID(item.ProductID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if item.VariantID != nil {
+			variant, err := repos.Variants.FindByID(*item.VariantID)
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			product.Variants = []models.Variant{*variant}
+		}
+		lines = append(lines, item)
+		products = append(products, *product)
+	}
+
+	if err := fillAvailable(repos, products); err != nil {
+		return nil, err
+	}
+	converter, err := loadConverter(repos, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := convertPrices(repos, converter, products, target); err != nil {
+		return nil, err
+	}
+	for i := range products {
+		if len(products[i].Variants) == 1 && products[i].Variants[0].Price != nil {
+			products[i].Price = *products[i].Variants[0].Price
+		}
+	}
+	if err := fillDiscounts(repos, cfg, products); err != nil {
+		return nil, err
+	}
+	taxer, err := pricing.NewTaxer(repos, converter, region, taxCfg.PricesIncludeTax)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := &models.Cart{
+		UserID:           userID,
+		Currency:         target,
+		TaxRegion:        region,
+		PricesIncludeTax: taxCfg.PricesIncludeTax,
+		Items:            []models.CartLine{},
+		Subtotal:         converter.Round(decimal.Zero, target),
+	}
+	amounts := make([]pricing.TaxedAmount, 0, len(products))
+	for i, product := range products {
+		quantity := lines[i].Quantity
+		line := models.CartLine{
+			ProductID: product.ID,
+			VariantID: lines[i].VariantID,
+			Name:      product.Name,
+			Quantity:  quantity,
+			UnitPrice: *product.EffectivePrice,
+			LineTotal: product.EffectivePrice.Mul(decimal.NewFromInt(int64(quantity))),
+			TaxRate:   taxer.Rate(product),
+			Available: *product.Available,
+		}
+		if line.VariantID != nil {
+			line.SKU = product.Variants[0].SKU
+			line.Available = product.Variants[0].Qty
+		}
+		cart.Items = append(cart.Items, line)
+		cart.Subtotal = cart.Subtotal.Add(line.LineTotal)
+		amounts = append(amounts, pricing.TaxedAmount{Amount: line.LineTotal, Rate: line.TaxRate})
+	}
+
+	cart.Taxes = taxer.Summarize(amounts, decimal.Zero, target)
+	total := taxer.Total(cart.Taxes, target)
+	cart.Net, cart.Tax, cart.Total = total.Net, total.Tax, total.Gross
+	return cart, nil
+}
+
+func unauthorized(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Unauthorized",
+		Data:    nil,
+	})
+}
+
+func cartItemNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Cart item not found",
+		Data:    nil,
+	})
+}
+
+func invalidCartItem(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid cart item",
+		Data:    reason,
+	})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// cartLine is a line of a cart as the cart endpoints return it
+type cartLine struct {
+	ProductID uint   `json:"product_id"`
+	VariantID *uint  `json:"variant_id"`
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice string `json:"unit_price"`
+	Available int    `json:"available"`
+}
+
+// cart returns the lines of the cart of user
+func (a *testApp) cart(user string) []cartLine {
+	a.t.Helper()
+	response := a.do("GET", "/api/cart", user, "")
+	a.expect(response, fiber.StatusOK)
+	var cart struct {
+		Items []cartLine `json:"items"`
+	}
+	a.decode(response, &cart)
+	return cart.Items
+}
+
+func TestCartVariantItems(t *testing.T) {
+	app := newTestApp(t)
+	shirt := app.createProduct("Shirt")
+	large := app.createVariant(shirt, "SHIRT-L", 3)
+	app.expect(app.do("PATCH", fmt.Sprintf("/api/product/%d/variants/%d", shirt, large), "1", `{"sku":"SHIRT-L","qty":3,"price":"25.00"}`), fiber.StatusOK)
+	small := app.createVariant(shirt, "SHIRT-S", 1)
+
+	app.expect(app.do("POST", "/api/cart/items", "1", fmt.Sprintf(`{"product_id":%d,"quantity":1}`, shirt)), fiber.StatusCreated)
+	app.expect(app.do("POST", "/api/cart/items", "1", fmt.Sprintf(`{"product_id":%d,"variant_id":%d,"quantity":2}`, shirt, large)), fiber.StatusCreated)
+	app.expect(app.do("POST", "/api/cart/items", "1", fmt.Sprintf(`{"product_id":%d,"variant_id":%d,"quantity":1}`, shirt, small)), fiber.StatusCreated)
+
+	want := []struct {
+		variantID uint
+		sku       string
+		quantity  int
+		unitPrice string
+		available int
+	}{
+		{0, "", 1, "19.99", 5},
+		{large, "SHIRT-L", 2, "25.00", 3},
+		{small, "SHIRT-S", 1, "19.99", 1},
+	}
+	lines := app.cart("1")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d cart lines, want %d", len(lines), len(want))
+	}
+	for i, w := range want {
+		line := lines[i]
+		variantID := uint(0)
+		if line.VariantID != nil {
+			variantID = *line.VariantID
+		}
+		if variantID != w.variantID || line.SKU != w.sku || line.Quantity != w.quantity || line.UnitPrice != w.unitPrice || line.Available != w.available {
+			t.Fatalf("got line %d %+v, want %+v", i, line, w)
+		}
+	}
+
+	// A variant only has its own stock, however much the product has
+	app.expect(app.do("POST", "/api/cart/items", "1", fmt.Sprintf(`{"product_id":%d,"variant_id":%d,"quantity":1}`, shirt, small)), fiber.StatusConflict)
+	app.expect(app.do("PATCH", fmt.Sprintf("/api/cart/items/%d?variant_id=%d", shirt, large), "1", `{"quantity":3}`), fiber.StatusOK)
+	app.expect(app.do("PATCH", fmt.Sprintf("/api/cart/items/%d?variant_id=%d", shirt, large), "1", `{"quantity":4}`), fiber.StatusConflict)
+	app.expect(app.do("DELETE", fmt.Sprintf("/api/cart/items/%d?variant_id=%d", shirt, small), "1", ""), fiber.StatusOK)
+	app.expect(app.do("DELETE", fmt.Sprintf("/api/cart/items/%d?variant_id=%d", shirt, small), "1", ""), fiber.StatusNotFound)
+	app.expect(app.do("DELETE", fmt.Sprintf("/api/cart/items/%d?variant_id=0", shirt), "1", ""), fiber.StatusBadRequest)
+
+	lines = app.cart("1")
+	if len(lines) != 2 || lines[0].VariantID != nil || lines[1].Quantity != 3 {
+		t.Fatalf("got cart %+v, want the product and 3 of the large shirt", lines)
+	}
+}
+
+func TestCartVariantOfAnotherProduct(t *testing.T) {
+	app := newTestApp(t)
+	shirt := app.createProduct("Shirt")
+	mug := app.createProduct("Mug")
+	large := app.createVariant(shirt, "SHIRT-L", 3)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"variant of another product", fmt.Sprintf(`{"product_id":%d,"variant_id":%d,"quantity":1}`, mug, large)},
+		{"unknown variant", fmt.Sprintf(`{"product_id":%d,"variant_id":99,"quantity":1}`, shirt)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app.t = t
+			response := app.do("POST", "/api/cart/items", "1", test.body)
+			app.expect(response, fiber.StatusNotFound)
+			if response.Message != "Variant not found" {
+				t.Fatalf("got %q, want the variant reported missing", response.Message)
+			}
+		})
+	}
+}
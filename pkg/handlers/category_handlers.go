@@ -1,14 +1,39 @@
 package handlers
 
 import (
-	"strconv"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/santoadji21/santoadji21-go-fiber-product-api/internal/db"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
 )
 
+// Policies for the products of a category that is being deleted
+const (
+	deletePolicyRefuse   = "refuse"
+	deletePolicyReassign = "reassign"
+	deletePolicyCascade  = "cascade"
+)
+
+var (
+	errCategoryInUse         = errors.New("category still has products")
+	errCategoryHasChildren   = errors.New("category still has subcategories")
+	errInvalidReassignTarget = errors.New("reassign target does not exist")
+	errInvalidParent         = errors.New("parent category does not exist")
+	errCategoryCycle         = errors.New("category cannot be its own ancestor")
+)
+
+// CategoryHandler serves the category endpoints
+type CategoryHandler struct {
+	repos repository.Repositories
+}
+
+// NewCategoryHandler creates a CategoryHandler backed by the given repositories
+func NewCategoryHandler(repos repository.Repositories) *CategoryHandler {
+	return &CategoryHandler{repos: repos}
+}
+
 // CreateCategory - Handler for creating a new category
 // CreateCategory creates a new category
 // @Summary Create a new category
@@ -18,8 +43,9 @@ import (
 // @Produce json
 // @Param category body models.Category true "Category Info"
 // @Success 201 {object} models.Category
+// @Failure 400 {object} utils.ApiResponse "Parent category or tax class does not exist"
 // @Router /api/category [post]
-func CreateCategory(c *fiber.Ctx) error {
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
 	var category models.Category
 	if err := c.BodyParser(&category); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
@@ -28,26 +54,40 @@ func CreateCategory(c *fiber.Ctx) error {
 			Data:    err.Error(),
 		})
 	}
+	category.Model = models.Model{}
 
 	// Check if a category with the same name already exists
-	var existingCategory models.Category
-	result := db.GetDB().Where("name = ?", category.Name).First(&existingCategory)
-	if result.Error == nil {
+	if _, err := h.repos.Categories.FindByName(category.Name); err == nil {
 		// A category with the same name was found
-		return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
-			Success: false,
-			Message: "Category name already exists",
-			Data:    nil,
-		})
+		return categoryNameConflict(c)
+	}
+	if !taxClassExists(h.repos, category.TaxClassID) {
+		return invalidTaxClass(c)
 	}
 
 	// No existing category found, proceed to create a new one
-	result = db.GetDB().Create(&category)
-	if result.Error != nil {
+	err := h.repos.Transaction(func(tx repository.Repositories) error {
+		if category.ParentID != nil {
+			if err := tx.Categories.Lock(*category.ParentID); err != nil {
+				return err
+			}
+			if _, err := tx.Categories.FindByID(*category.ParentID); err != nil {
+				return errInvalidParent
+			}
+		}
+		return tx.Categories.Create(&category)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return categoryNameConflict(c)
+		case errors.Is(err, errInvalidParent), errors.Is(err, repository.ErrInvalidReference):
+			return invalidParent(c)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Failed to create category",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
 		})
 	}
 
@@ -67,14 +107,13 @@ func CreateCategory(c *fiber.Ctx) error {
 // @Produce json
 // @Success 200 {array} models.Category
 // @Router /api/categories [get]
-func GetAllCategories(c *fiber.Ctx) error {
-	var categories []models.Category
-	result := db.GetDB().Find(&categories)
-	if result.Error != nil {
+func (h *CategoryHandler) GetAllCategories(c *fiber.Ctx) error {
+	categories, err := h.repos.Categories.FindAll()
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Failed to retrieve categories",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
 		})
 	}
 
@@ -85,33 +124,73 @@ func GetAllCategories(c *fiber.Ctx) error {
 	})
 }
 
+// GetCategoryTree - Handler for getting the category hierarchy
+// GetCategoryTree retrieves all categories nested under their parents
+// @Summary Get the category tree
+// @Description Retrieves every category with its subcategories nested under it
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.CategoryNode
+// @Router /api/categories/tree [get]
+func (h *CategoryHandler) GetCategoryTree(c *fiber.Ctx) error {
+	categories, err := h.repos.Categories.FindAll()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve categories",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Category tree retrieved successfully",
+		Data:    buildCategoryTree(categories),
+	})
+}
+
 // GetCategory - Handler for getting a category's details
 // GetCategory retrieves a single category by ID
 // @Summary Get a category
-// @Description Retrieves a category by its ID
+// @Description Retrieves a category by its ID, with breadcrumbs from its root category
 // @Tags Category
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
-// @Success 200 {object} models.Category
+// @Success 200 {object} models.CategoryDetail
+// @Header 200 {string} ETag "Version of the category"
 // @Failure 404 {object} utils.ApiResponse "Category not found"
 // @Router /api/category/{id} [get]
-func GetCategory(c *fiber.Ctx) error {
-	id := c.Params("id")
-	var category models.Category
-	result := db.GetDB().First(&category, id)
-	if result.Error != nil {
-		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	path, err := h.repos.Categories.Path(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return categoryNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
-			Message: "Category not found",
-			Data:    nil,
+			Message: "Failed to retrieve category",
+			Data:    err.Error(),
 		})
 	}
 
+	breadcrumbs := make([]models.Breadcrumb, 0, len(path))
+	for _, ancestor := range path {
+		breadcrumbs = append(breadcrumbs, models.Breadcrumb{ID: ancestor.ID, Name: ancestor.Name})
+	}
+	category := path[len(path)-1]
+
+	setETag(c, category.Version)
 	return c.JSON(utils.ApiResponse{
 		Success: true,
 		Message: "Category retrieved successfully",
-		Data:    category,
+		Data:    models.CategoryDetail{Category: category, Breadcrumbs: breadcrumbs},
 	})
 }
 
@@ -123,31 +202,79 @@ func GetCategory(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Param category body models.Category true "Category update data"
 // @Success 200 {object} models.Category
+// @Failure 400 {object} utils.ApiResponse "Parent category or tax class does not exist"
 // @Failure 404 {object} utils.ApiResponse "Category not found"
+// @Failure 409 {object} utils.ApiResponse "Category would become its own ancestor"
+// @Failure 412 {object} utils.ApiResponse "Category was modified since the given ETag"
 // @Router /api/category/{id} [patch]
-func UpdateCategory(c *fiber.Ctx) error {
-	id := c.Params("id")
-	var category models.Category
-	if err := db.GetDB().First(&category, id).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
-			Success: false,
-			Message: "Category not found",
-			Data:    nil,
-		})
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
 	}
 
-	if err := c.BodyParser(&category); err != nil {
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	category, err := h.repos.Categories.FindByID(id)
+	if err != nil {
+		return categoryNotFound(c)
+	}
+	if expected != 0 && category.Version != expected {
+		return preconditionFailed(c)
+	}
+	model := category.Model
+
+	if err := c.BodyParser(category); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Error parsing JSON",
 			Data:    err.Error(),
 		})
 	}
+	category.Model = model
+	if !taxClassExists(h.repos, category.TaxClassID) {
+		return invalidTaxClass(c)
+	}
 
-	db.GetDB().Save(&category)
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if category.ParentID != nil {
+			if err := checkParent(tx, id, *category.ParentID); err != nil {
+				return err
+			}
+		}
+		return tx.Categories.Update(category)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return categoryNameConflict(c)
+		case errors.Is(err, errInvalidParent), errors.Is(err, repository.ErrInvalidReference):
+			return invalidParent(c)
+		case errors.Is(err, errCategoryCycle):
+			return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Category cannot be moved under itself or its subcategories",
+				Data:    nil,
+			})
+		case errors.Is(err, repository.ErrVersionConflict):
+			return versionConflict(c)
+		case errors.Is(err, repository.ErrNotFound):
+			return categoryNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update category",
+			Data:    err.Error(),
+		})
+	}
 
+	setETag(c, category.Version)
 	return c.JSON(utils.ApiResponse{
 		Success: true,
 		Message: "Category updated successfully",
@@ -156,41 +283,116 @@ func UpdateCategory(c *fiber.Ctx) error {
 }
 
 // DeleteCategory - Handler for deleting a category
-// DeleteCategory deletes a category
+// DeleteCategory moves a category to the trash
 // @Summary Delete a category
-// @Description Deletes a category by its ID
+// @Description Moves a category to the trash by its ID. Categories with subcategories cannot be deleted.
+// @Description on_products decides what happens
+// @Description to the products still in the category: "refuse" (default) rejects the delete,
+// @Description "reassign" moves them to the reassign_to category and "cascade" deletes them too.
 // @Tags Category
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param on_products query string false "refuse, reassign or cascade" Enums(refuse, reassign, cascade)
+// @Param reassign_to query int false "Category that receives the products when on_products=reassign"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 200 {object} utils.ApiResponse
+// @Failure 400 {object} utils.ApiResponse "Invalid delete policy"
 // @Failure 404 {object} utils.ApiResponse "Category not found"
+// @Failure 409 {object} utils.ApiResponse "Category still has products or subcategories"
+// @Failure 412 {object} utils.ApiResponse "Category was modified since the given ETag"
 // @Router /api/category/{id} [delete]
-func DeleteCategory(c *fiber.Ctx) error {
-	id, err := strconv.Atoi(c.Params("id"))
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	id, err := paramID(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
-			Success: false,
-			Message: "Invalid ID format",
-			Data:    nil,
-		})
+		return invalidID(c)
 	}
 
-	var category models.Category
-	if err := db.GetDB().First(&category, id).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
-			Success: false,
-			Message: "Category not found",
-			Data:    nil,
-		})
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	policy := c.Query("on_products", deletePolicyRefuse)
+	var reassignTo uint
+	switch policy {
+	case deletePolicyRefuse, deletePolicyCascade:
+	case deletePolicyReassign:
+		target := c.QueryInt("reassign_to")
+		if target <= 0 || uint(target) == id {
+			return invalidDeletePolicy(c, "reassign_to must name another category")
+		}
+		reassignTo = uint(target)
+	default:
+		return invalidDeletePolicy(c, "on_products must be refuse, reassign or cascade")
 	}
 
-	result := db.GetDB().Unscoped().Delete(&models.Category{}, id)
-	if result.Error != nil {
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Categories.Lock(id); err != nil {
+			return err
+		}
+		if _, err := tx.Categories.FindByID(id); err != nil {
+			return err
+		}
+
+		children, err := tx.Categories.CountChildren(id)
+		if err != nil {
+			return err
+		}
+		if children > 0 {
+			return errCategoryHasChildren
+		}
+
+		count, err := tx.Products.CountByCategory(id)
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			switch policy {
+			case deletePolicyRefuse:
+				return errCategoryInUse
+			case deletePolicyReassign:
+				if _, err := tx.Categories.FindByID(reassignTo); err != nil {
+					return errInvalidReassignTarget
+				}
+				if _, err := tx.Products.ReassignCategory(id, reassignTo); err != nil {
+					return err
+				}
+			case deletePolicyCascade:
+				if _, err := tx.Products.DeleteByCategory(id); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Categories.Delete(id, expected)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return categoryNotFound(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return preconditionFailed(c)
+		case errors.Is(err, errCategoryInUse):
+			return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Category still has products",
+				Data:    nil,
+			})
+		case errors.Is(err, errCategoryHasChildren):
+			return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Category still has subcategories",
+				Data:    nil,
+			})
+		case errors.Is(err, errInvalidReassignTarget):
+			return invalidDeletePolicy(c, "reassign_to category does not exist")
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Failed to delete category",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
 		})
 	}
 
@@ -200,3 +402,102 @@ func DeleteCategory(c *fiber.Ctx) error {
 		Data:    nil,
 	})
 }
+
+func categoryNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Category not found",
+		Data:    nil,
+	})
+}
+
+func categoryNameConflict(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Category name already exists",
+		Data:    nil,
+	})
+}
+
+func invalidDeletePolicy(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid delete policy",
+		Data:    reason,
+	})
+}
+
+func invalidParent(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Parent category does not exist",
+		Data:    nil,
+	})
+}
+
+// checkParent makes sure category id can be moved under parentID. The
+// parent's ancestors are locked and then read again, so a concurrent move
+// cannot slip in and close a cycle between the check and the update.
+func checkParent(tx repository.Repositories, id, parentID uint) error {
+	path, err := tx.Categories.Path(parentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errInvalidParent
+		}
+		return err
+	}
+
+	ids := []uint{id}
+	for _, ancestor := range path {
+		ids = append(ids, ancestor.ID)
+	}
+	if err := tx.Categories.Lock(ids...); err != nil {
+		return err
+	}
+
+	path, err = tx.Categories.Path(parentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errInvalidParent
+		}
+		return err
+	}
+	for _, ancestor := range path {
+		if ancestor.ID == id {
+			return errCategoryCycle
+		}
+	}
+	return nil
+}
+
+// buildCategoryTree nests categories under their parents. Categories
+// whose parent is not in the list become roots.
+func buildCategoryTree(categories []models.Category) []models.CategoryNode {
+	known := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+
+	children := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, category := range categories {
+		if category.ParentID != nil && known[*category.ParentID] && *category.ParentID != category.ID {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		} else {
+			roots = append(roots, category)
+		}
+	}
+
+	var build func(categories []models.Category) []models.CategoryNode
+	build = func(categories []models.Category) []models.CategoryNode {
+		nodes := make([]models.CategoryNode, 0, len(categories))
+		for _, category := range categories {
+			nodes = append(nodes, models.CategoryNode{
+				Category: category,
+				Children: build(children[category.ID]),
+			})
+		}
+		return nodes
+	}
+	return build(roots)
+}
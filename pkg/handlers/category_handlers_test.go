@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// createCategory creates a category with the given JSON body and returns
+// its ID
+func (a *testApp) createCategory(body string) uint {
+	a.t.Helper()
+	response := a.do("POST", "/api/category", "1", body)
+	a.expect(response, fiber.StatusCreated)
+	var category struct {
+		ID uint `json:"id"`
+	}
+	a.decode(response, &category)
+	return category.ID
+}
+
+func TestCreateCategoryDuplicateName(t *testing.T) {
+	app := newTestApp(t)
+	app.createCategory(`{"name":"Lighting"}`)
+
+	app.expect(app.do("POST", "/api/category", "1", `{"name":"Lighting"}`), fiber.StatusConflict)
+}
+
+func TestCreateCategoryIgnoresServerFields(t *testing.T) {
+	app := newTestApp(t)
+	id := app.createCategory(`{"id":42,"deleted_at":"2000-01-01T00:00:00Z","name":"Lighting"}`)
+	if id == 42 {
+		t.Fatal("the category was created with the ID the client chose")
+	}
+	app.expect(app.do("GET", fmt.Sprintf("/api/category/%d", id), "", ""), fiber.StatusOK)
+}
+
+func TestUpdateCategoryIfMatch(t *testing.T) {
+	app := newTestApp(t)
+	app.createCategory(`{"name":"Lighting"}`)
+	path := fmt.Sprintf("/api/category/%d", app.createCategory(`{"name":"Furniture"}`))
+
+	app.expect(app.do("PATCH", path, "1", `{"name":"Lighting"}`), fiber.StatusConflict)
+
+	response := app.do("PATCH", path, "1", `{"name":"Tables"}`, fiber.HeaderIfMatch, `"1"`)
+	app.expect(response, fiber.StatusOK)
+	if response.ETag != `"2"` {
+		t.Fatalf("got ETag %s after the update, want \"2\"", response.ETag)
+	}
+	app.expect(app.do("PATCH", path, "1", `{"name":"Chairs"}`, fiber.HeaderIfMatch, `"1"`), fiber.StatusPreconditionFailed)
+	app.expect(app.do("DELETE", path, "1", "", fiber.HeaderIfMatch, `"1"`), fiber.StatusPreconditionFailed)
+}
+
+func TestDeleteCategoryMovesItToTrash(t *testing.T) {
+	app := newTestApp(t)
+	path := fmt.Sprintf("/api/category/%d", app.createCategory(`{"name":"Lighting"}`))
+
+	app.expect(app.do("DELETE", path, "1", ""), fiber.StatusOK)
+	app.expect(app.do("GET", path, "", ""), fiber.StatusNotFound)
+	app.expect(app.do("DELETE", path, "1", ""), fiber.StatusNotFound)
+
+	// The name stays taken while the category is in the trash
+	app.expect(app.do("POST", "/api/category", "1", `{"name":"Lighting"}`), fiber.StatusConflict)
+}
+
+func TestCreateCategoryUnderMissingParent(t *testing.T) {
+	app := newTestApp(t)
+	app.expect(app.do("POST", "/api/category", "1", `{"name":"Lamps","parent_id":42}`), fiber.StatusBadRequest)
+}
+
+func TestUpdateCategoryKeepsServerFields(t *testing.T) {
+	app := newTestApp(t)
+	id := app.createCategory(`{"name":"Lighting"}`)
+	path := fmt.Sprintf("/api/category/%d", id)
+
+	response := app.do("PATCH", path, "1", `{"id":42,"version":9,"deleted_at":"2000-01-01T00:00:00Z","name":"Lamps"}`)
+	app.expect(response, fiber.StatusOK)
+	if response.ETag != `"2"` {
+		t.Fatalf("got ETag %s after the update, want \"2\"", response.ETag)
+	}
+	app.expect(app.do("GET", path, "", ""), fiber.StatusOK)
+	app.expect(app.do("GET", "/api/category/42", "", ""), fiber.StatusNotFound)
+}
@@ -0,0 +1,473 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// CouponHandler serves the coupon endpoints
+type CouponHandler struct {
+	repos    repository.Repositories
+	currency config.CurrencyConfig
+}
+
+// NewCouponHandler creates a CouponHandler backed by the given repositories
+func NewCouponHandler(repos repository.Repositories, currencyCfg config.CurrencyConfig) *CouponHandler {
+	return &CouponHandler{repos: repos, currency: currencyCfg}
+}
+
+// CouponRequest is the body of a coupon check or redemption. An empty
+// Currency means the base currency.
+type CouponRequest struct {
+	Code       string          `json:"code" example:"SPRING10"`
+	OrderValue decimal.Decimal `json:"order_value" swaggertype:"string" example:"80.00"`
+	Currency   string          `json:"currency" example:"USD"`
+}
+
+// CreateCoupon - Handler for creating a new coupon
+// @Summary Create a coupon
+// @Description Creates a percentage or fixed coupon. Codes are case-insensitive and stored in
+// @Description upper case. Fixed amounts and min_order_value are in currency, the base currency
+// @Description by default. A null usage_limit or per_user_limit means no limit. Admins only.
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Param coupon body models.Coupon true "Coupon"
+// @Success 201 {object} models.Coupon
+// @Failure 400 {object} utils.ApiResponse "Invalid coupon"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 409 {object} utils.ApiResponse "Coupon code already exists"
+// @Router /api/coupon [post]
+func (h *CouponHandler) CreateCoupon(c *fiber.Ctx) error {
+	var coupon models.Coupon
+	if err := c.BodyParser(&coupon); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	coupon.Model = models.Model{}
+	coupon.Redeemed = 0
+
+	if reason := h.validate(&coupon); reason != "" {
+		return invalidCoupon(c, reason)
+	}
+
+	if err := h.repos.Coupons.Create(&coupon); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return couponCodeConflict(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create coupon",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Coupon created successfully",
+		Data:    coupon,
+	})
+}
+
+// GetCoupons - Handler for listing the coupons
+// @Summary Get all coupons
+// @Description Retrieves every coupon, including those not in force. Admins only.
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Coupon
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Router /api/coupons [get]
+func (h *CouponHandler) GetCoupons(c *fiber.Ctx) error {
+	coupons, err := h.repos.Coupons.FindAll()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve coupons",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Coupons retrieved successfully",
+		Data:    coupons,
+	})
+}
+
+// GetCoupon - Handler for getting a coupon
+// @Summary Get a coupon
+// @Description Retrieves a coupon by its ID. Admins only.
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Success 200 {object} models.Coupon
+// @Header 200 {string} ETag "Version of the coupon"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Coupon not found"
+// @Router /api/coupon/{id} [get]
+func (h *CouponHandler) GetCoupon(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	coupon, err := h.repos.Coupons.FindByID(id)
+	if err != nil {
+		return couponNotFound(c)
+	}
+
+	setETag(c, coupon.Version)
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Coupon retrieved successfully",
+		Data:    coupon,
+	})
+}
+
+// UpdateCoupon - Handler for updating a coupon
+// @Summary Update a coupon
+// @Description Updates a coupon by its ID. The redeemed count cannot be changed, and the
+// @Description usage limit cannot be set below it. Every redemption bumps the version of the
+// @Description coupon. Admins only.
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Param coupon body models.Coupon true "Coupon update data"
+// @Success 200 {object} models.Coupon
+// @Failure 400 {object} utils.ApiResponse "Invalid coupon"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Coupon not found"
+// @Failure 409 {object} utils.ApiResponse "Coupon code already exists"
+// @Failure 412 {object} utils.ApiResponse "Coupon was modified since the given ETag"
+// @Router /api/coupon/{id} [patch]
+func (h *CouponHandler) UpdateCoupon(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	coupon, err := h.repos.Coupons.FindByID(id)
+	if err != nil {
+		return couponNotFound(c)
+	}
+	if expected != 0 && coupon.Version != expected {
+		return preconditionFailed(c)
+	}
+	model, redeemed := coupon.Model, coupon.Redeemed
+
+	if err := c.BodyParser(coupon); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	coupon.Model = model
+	coupon.Redeemed = redeemed
+
+	if reason := h.validate(coupon); reason != "" {
+		return invalidCoupon(c, reason)
+	}
+	if coupon.UsageLimit != nil && *coupon.UsageLimit < coupon.Redeemed {
+		return invalidCoupon(c, "usage_limit cannot be below the number of redemptions")
+	}
+
+	if err := h.repos.Coupons.Update(coupon); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return couponCodeConflict(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return versionConflict(c)
+		case errors.Is(err, repository.ErrNotFound):
+			return couponNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update coupon",
+			Data:    err.Error(),
+		})
+	}
+
+	setETag(c, coupon.Version)
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Coupon updated successfully",
+		Data:    coupon,
+	})
+}
+
+// DeleteCoupon - Handler for deleting a coupon
+// @Summary Delete a coupon
+// @Description Moves a coupon to the trash by its ID. Its code stays taken until it is purged.
+// @Description Admins only.
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Param If-Match header string false "ETag of the version being deleted"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Coupon not found"
+// @Failure 412 {object} utils.ApiResponse "Coupon was modified since the given ETag"
+// @Router /api/coupon/{id} [delete]
+func (h *CouponHandler) DeleteCoupon(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	if err := h.repos.Coupons.Delete(id, expected); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return couponNotFound(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return preconditionFailed(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete coupon",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Coupon deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GetCouponRedemptions - Handler for listing the redemptions of a coupon
+// @Summary Get coupon redemptions
+// @Description Retrieves the ledger of every redemption of a coupon, oldest first. Redemptions
+// @Description given back by their order carry a released_at time. Admins only.
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Success 200 {array} models.CouponRedemption
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Coupon not found"
+// @Router /api/coupon/{id}/redemptions [get]
+func (h *CouponHandler) GetCouponRedemptions(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if _, err := h.repos.Coupons.FindByID(id); err != nil {
+		return couponNotFound(c)
+	}
+
+	redemptions, err := h.repos.Coupons.FindRedemptions(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve coupon redemptions",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Coupon redemptions retrieved successfully",
+		Data:    redemptions,
+	})
+}
+
+// ValidateCoupon - Handler for checking a coupon against an order
+// @Summary Validate a coupon
+// @Description Checks whether a coupon can be used on an order worth order_value in currency,
+// @Description the base currency by default, and what it would take off. Nothing is redeemed:
+// @Description coupons are only redeemed by placing an order with them.
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Param request body CouponRequest true "Coupon and order"
+// @Success 200 {object} pricing.CouponQuote
+// @Failure 400 {object} utils.ApiResponse "Coupon cannot be used on this order"
+// @Failure 404 {object} utils.ApiResponse "Coupon not found"
+// @Failure 409 {object} utils.ApiResponse "Coupon has reached its usage limit"
+// @Router /api/coupons/validate [post]
+func (h *CouponHandler) ValidateCoupon(c *fiber.Ctx) error {
+	var request CouponRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+
+	converter, err := loadConverter(h.repos, h.currency)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve exchange rates",
+			Data:    err.Error(),
+		})
+	}
+	if reason := h.normalize(&request, converter); reason != "" {
+		return invalidCouponRequest(c, reason)
+	}
+
+	quote, err := pricing.QuoteCoupon(h.repos, converter, request.Code, request.OrderValue, request.Currency, currentUserID(c), time.Now())
+	if err != nil {
+		return couponError(c, err, "Failed to validate coupon")
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Coupon is valid",
+		Data:    quote,
+	})
+}
+
+// normalize fills in the default currency of request and rounds its
+// order value. It returns why the request is invalid, or "".
+func (h *CouponHandler) normalize(request *CouponRequest, converter currency.Converter) string {
+	request.Currency = currency.Normalize(request.Currency)
+	if request.Currency == "" {
+		request.Currency = h.currency.Base
+	}
+	switch {
+	case pricing.NormalizeCode(request.Code) == "":
+		return "code is required"
+	case request.OrderValue.Sign() <= 0:
+		return "order_value must be positive"
+	case !converter.Supports(request.Currency):
+		return "unknown currency"
+	}
+	request.OrderValue = converter.Round(request.OrderValue, request.Currency)
+	return ""
+}
+
+// validate normalizes coupon in place and returns why it is invalid, or ""
+func (h *CouponHandler) validate(coupon *models.Coupon) string {
+	coupon.Code = pricing.NormalizeCode(coupon.Code)
+	coupon.Currency = currency.Normalize(coupon.Currency)
+	if coupon.Currency == "" {
+		coupon.Currency = h.currency.Base
+	}
+	if coupon.Currency != h.currency.Base {
+		if _, err := h.repos.Currencies.FindRate(coupon.Currency); err != nil {
+			return "unknown currency"
+		}
+	}
+
+	switch coupon.Type {
+	case models.DiscountPercentage:
+		if coupon.Value.GreaterThan(decimal.NewFromInt(100)) {
+			return "a percentage cannot be above 100"
+		}
+	case models.DiscountFixed:
+	default:
+		return "type must be percentage or fixed"
+	}
+
+	switch {
+	case coupon.Code == "":
+		return "code is required"
+	case coupon.Value.Sign() <= 0:
+		return "value must be positive"
+	case coupon.MinOrderValue.IsNegative():
+		return "min_order_value cannot be negative"
+	case coupon.UsageLimit != nil && *coupon.UsageLimit < 1:
+		return "usage_limit must be at least 1"
+	case coupon.PerUserLimit != nil && *coupon.PerUserLimit < 1:
+		return "per_user_limit must be at least 1"
+	case coupon.StartsAt != nil && coupon.EndsAt != nil && !coupon.EndsAt.After(*coupon.StartsAt):
+		return "ends_at must be after starts_at"
+	}
+	return ""
+}
+
+// couponError writes the response for an error from quoting or redeeming
+// a coupon
+func couponError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return couponNotFound(c)
+	case errors.Is(err, pricing.ErrCouponNotActive), errors.Is(err, pricing.ErrBelowMinimum):
+		return unusableCoupon(c, err.Error())
+	case errors.Is(err, currency.ErrUnknown):
+		return unusableCoupon(c, "coupon cannot be used in this currency")
+	case errors.Is(err, pricing.ErrCouponUsedUp), errors.Is(err, pricing.ErrUserLimit):
+		return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Coupon has reached its usage limit",
+			Data:    err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+		Success: false,
+		Message: message,
+		Data:    err.Error(),
+	})
+}
+
+func couponNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Coupon not found",
+		Data:    nil,
+	})
+}
+
+func couponCodeConflict(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Coupon code already exists",
+		Data:    nil,
+	})
+}
+
+func invalidCoupon(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid coupon",
+		Data:    reason,
+	})
+}
+
+func invalidCouponRequest(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid coupon request",
+		Data:    reason,
+	})
+}
+
+func unusableCoupon(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Coupon cannot be used on this order",
+		Data:    reason,
+	})
+}
@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+var errCurrencyInUse = errors.New("currency is still used by products")
+
+// maxDecimals bounds the rounding precision an exchange rate may ask for
+const maxDecimals = 8
+
+// CurrencyHandler serves the exchange rate and price override endpoints
+type CurrencyHandler struct {
+	repos repository.Repositories
+	cfg   config.CurrencyConfig
+}
+
+// NewCurrencyHandler creates a CurrencyHandler backed by the given repositories
+func NewCurrencyHandler(repos repository.Repositories, cfg config.CurrencyConfig) *CurrencyHandler {
+	return &CurrencyHandler{repos: repos, cfg: cfg}
+}
+
+// ExchangeRateRequest is the body of an exchange rate update. Rate is what
+// one unit of the base currency is worth in the currency. Decimals
+// defaults to the ISO 4217 minor units of the currency.
+type ExchangeRateRequest struct {
+	Rate     decimal.Decimal `json:"rate" swaggertype:"string" example:"0.92"`
+	Decimals *int32          `json:"decimals"`
+}
+
+// PriceOverrideRequest is the body of a per-currency price
+type PriceOverrideRequest struct {
+	Price decimal.Decimal `json:"price" swaggertype:"string" example:"17.99"`
+}
+
+// GetExchangeRates - Handler for listing the exchange rates
+// @Summary Get exchange rates
+// @Description Retrieves every exchange rate. Rates are relative to the base currency,
+// @Description which is not listed and always has a rate of 1.
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.ExchangeRate
+// @Router /api/exchange-rates [get]
+func (h *CurrencyHandler) GetExchangeRates(c *fiber.Ctx) error {
+	rates, err := h.repos.Currencies.FindRates()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve exchange rates",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Exchange rates retrieved successfully",
+		Data:    rates,
+	})
+}
+
+// PutExchangeRate - Handler for setting an exchange rate
+// @Summary Set an exchange rate
+// @Description Creates or replaces the exchange rate of a currency. Admins only.
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Param currency path string true "ISO 4217 currency code"
+// @Param rate body ExchangeRateRequest true "Exchange rate"
+// @Success 200 {object} models.ExchangeRate
+// @Failure 400 {object} utils.ApiResponse "Invalid exchange rate"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Router /api/exchange-rates/{currency} [put]
+func (h *CurrencyHandler) PutExchangeRate(c *fiber.Ctx) error {
+	code := paramCurrency(c)
+	if !currency.Valid(code) {
+		return invalidCurrency(c)
+	}
+	if code == h.cfg.Base {
+		return invalidExchangeRate(c, "the base currency always has a rate of 1")
+	}
+
+	var request ExchangeRateRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if request.Rate.Sign() <= 0 {
+		return invalidExchangeRate(c, "rate must be positive")
+	}
+
+	rate := models.ExchangeRate{
+		Currency: code,
+		Rate:     request.Rate,
+		Decimals: currency.Decimals(code),
+	}
+	if request.Decimals != nil {
+		if *request.Decimals < 0 || *request.Decimals > maxDecimals {
+			return invalidExchangeRate(c, "decimals must be between 0 and 8")
+		}
+		rate.Decimals = *request.Decimals
+	}
+
+	if err := h.repos.Currencies.SaveRate(&rate); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to save exchange rate",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Exchange rate saved successfully",
+		Data:    rate,
+	})
+}
+
+// DeleteExchangeRate - Handler for removing an exchange rate
+// @Summary Delete an exchange rate
+// @Description Removes the exchange rate of a currency. A currency that products are still
+// @Description priced in cannot be removed. Admins only.
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Param currency path string true "ISO 4217 currency code"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Exchange rate not found"
+// @Failure 409 {object} utils.ApiResponse "Currency is still used by products"
+// @Router /api/exchange-rates/{currency} [delete]
+func (h *CurrencyHandler) DeleteExchangeRate(c *fiber.Ctx) error {
+	code := paramCurrency(c)
+
+	err := h.repos.Transaction(func(tx repository.Repositories) error {
+		count, err := tx.Products.CountByCurrency(code)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errCurrencyInUse
+		}
+		return tx.Currencies.DeleteRate(code)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Exchange rate not found",
+				Data:    nil,
+			})
+		case errors.Is(err, errCurrencyInUse):
+			return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Currency is still used by products",
+				Data:    nil,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete exchange rate",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Exchange rate deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GetPriceOverrides - Handler for listing a product's per-currency prices
+// @Summary Get price overrides
+// @Description Retrieves the explicit prices of a product in other currencies
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.ProductPrice
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/product/{id}/price-overrides [get]
+func (h *CurrencyHandler) GetPriceOverrides(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if _, err := h.repos.Products.FindByID(id); err != nil {
+		return productNotFound(c)
+	}
+
+	prices, err := h.repos.Currencies.FindPrices([]uint{id})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve price overrides",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Price overrides retrieved successfully",
+		Data:    prices,
+	})
+}
+
+// PutPriceOverride - Handler for setting a product's price in a currency
+// @Summary Set a price override
+// @Description Sets the price of a product in a currency, used instead of converting its own price
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param currency path string true "ISO 4217 currency code"
+// @Param price body PriceOverrideRequest true "Price in the currency"
+// @Success 200 {object} models.ProductPrice
+// @Failure 400 {object} utils.ApiResponse "Unknown currency or negative price"
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/product/{id}/price-overrides/{currency} [put]
+func (h *CurrencyHandler) PutPriceOverride(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request PriceOverrideRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if request.Price.IsNegative() {
+		return invalidPrice(c)
+	}
+
+	product, err := h.repos.Products.FindByID(id)
+	if err != nil {
+		return productNotFound(c)
+	}
+
+	code := paramCurrency(c)
+	converter, err := loadConverter(h.repos, h.cfg)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve exchange rates",
+			Data:    err.Error(),
+		})
+	}
+	if !converter.Supports(code) || code == product.Currency {
+		return invalidCurrency(c)
+	}
+
+	price := models.ProductPrice{
+		ProductID: id,
+		Currency:  code,
+		Price:     converter.Round(request.Price, code),
+	}
+	if err := h.repos.Currencies.SavePrice(&price); err != nil {
+		if errors.Is(err, repository.ErrInvalidReference) {
+			return productNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to save price override",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Price override saved successfully",
+		Data:    price,
+	})
+}
+
+// DeletePriceOverride - Handler for removing a product's price in a currency
+// @Summary Delete a price override
+// @Description Removes the explicit price of a product in a currency, so its own price is converted again
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param currency path string true "ISO 4217 currency code"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse "Price override not found"
+// @Router /api/product/{id}/price-overrides/{currency} [delete]
+func (h *CurrencyHandler) DeletePriceOverride(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	code := paramCurrency(c)
+	if err := h.repos.Currencies.DeletePrice(id, code); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Price override not found",
+				Data:    nil,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete price override",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Price override deleted successfully",
+		Data:    nil,
+	})
+}
+
+// loadConverter reads the exchange rate table into a converter
+func loadConverter(repos repository.Repositories, cfg config.CurrencyConfig) (currency.Converter, error) {
+	rates, err := repos.Currencies.FindRates()
+	if err != nil {
+		return currency.Converter{}, err
+	}
+
+	converter := currency.Converter{Base: cfg.Base, Rates: make(map[string]currency.Rate, len(rates))}
+	for _, rate := range rates {
+		converter.Rates[rate.Currency] = currency.Rate{Rate: rate.Rate, Decimals: rate.Decimals}
+	}
+	return converter, nil
+}
+
+// convertPrices rewrites the prices of every product, and of its variants,
+// into the target currency in place. A product's explicit price in the
+// target currency wins over converting its own price.
+func convertPrices(repos repository.Repositories, converter currency.Converter, products []models.Product, target string) error {
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	prices, err := repos.Currencies.FindPrices(ids)
+	if err != nil {
+		return err
+	}
+	overrides := make(map[uint]decimal.Decimal)
+	for _, price := range prices {
+		if price.Currency == target {
+			overrides[price.ProductID] = price.Price
+		}
+	}
+
+	for i := range products {
+		product := &products[i]
+		from := product.Currency
+
+		if override, ok := overrides[product.ID]; ok {
+			product.Price = converter.Round(override, target)
+		} else if product.Price, err = converter.Convert(product.Price, from, target); err != nil {
+			return err
+		}
+		for j := range product.Variants {
+			variant := &product.Variants[j]
+			if variant.Price == nil {
+				continue
+			}
+			price, err := converter.Convert(*variant.Price, from, target)
+			if err != nil {
+				return err
+			}
+			variant.Price = &price
+		}
+		product.Currency = target
+	}
+	return nil
+}
+
+func invalidCurrency(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Unknown currency",
+		Data:    nil,
+	})
+}
+
+func invalidExchangeRate(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid exchange rate",
+		Data:    reason,
+	})
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// DiscountHandler serves the discount rule endpoints
+type DiscountHandler struct {
+	repos    repository.Repositories
+	currency config.CurrencyConfig
+}
+
+// NewDiscountHandler creates a DiscountHandler backed by the given repositories
+func NewDiscountHandler(repos repository.Repositories, currencyCfg config.CurrencyConfig) *DiscountHandler {
+	return &DiscountHandler{repos: repos, currency: currencyCfg}
+}
+
+// CreateDiscountRule - Handler for creating a new discount rule
+// @Summary Create a discount rule
+// @Description Creates a percentage or fixed discount scoped to a product (product_id), a
+// @Description category and its subcategories (category_id) or, with neither, every product.
+// @Description Fixed amounts are in currency, the base currency by default. Admins only.
+// @Tags Discount
+// @Accept json
+// @Produce json
+// @Param rule body models.DiscountRule true "Discount rule"
+// @Success 201 {object} models.DiscountRule
+// @Failure 400 {object} utils.ApiResponse "Invalid discount rule"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Router /api/discount-rule [post]
+func (h *DiscountHandler) CreateDiscountRule(c *fiber.Ctx) error {
+	var rule models.DiscountRule
+	if err := c.BodyParser(&rule); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	rule.Model = models.Model{}
+
+	if reason := h.validate(&rule); reason != "" {
+		return invalidDiscountRule(c, reason)
+	}
+
+	if err := h.repos.Discounts.Create(&rule); err != nil {
+		if errors.Is(err, repository.ErrInvalidReference) {
+			return invalidDiscountRule(c, "product or category does not exist")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create discount rule",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Discount rule created successfully",
+		Data:    rule,
+	})
+}
+
+// GetDiscountRules - Handler for listing the discount rules
+// @Summary Get all discount rules
+// @Description Retrieves every discount rule, including those not in force
+// @Tags Discount
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.DiscountRule
+// @Router /api/discount-rules [get]
+func (h *DiscountHandler) GetDiscountRules(c *fiber.Ctx) error {
+	rules, err := h.repos.Discounts.FindAll()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve discount rules",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Discount rules retrieved successfully",
+		Data:    rules,
+	})
+}
+
+// GetDiscountRule - Handler for getting a discount rule
+// @Summary Get a discount rule
+// @Description Retrieves a discount rule by its ID
+// @Tags Discount
+// @Accept json
+// @Produce json
+// @Param id path int true "Discount rule ID"
+// @Success 200 {object} models.DiscountRule
+// @Header 200 {string} ETag "Version of the discount rule"
+// @Failure 404 {object} utils.ApiResponse "Discount rule not found"
+// @Router /api/discount-rule/{id} [get]
+func (h *DiscountHandler) GetDiscountRule(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	rule, err := h.repos.Discounts.FindByID(id)
+	if err != nil {
+		return discountRuleNotFound(c)
+	}
+
+	setETag(c, rule.Version)
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Discount rule retrieved successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateDiscountRule - Handler for updating a discount rule
+// @Summary Update a discount rule
+// @Description Updates a discount rule by its ID. Admins only.
+// @Tags Discount
+// @Accept json
+// @Produce json
+// @Param id path int true "Discount rule ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Param rule body models.DiscountRule true "Discount rule update data"
+// @Success 200 {object} models.DiscountRule
+// @Failure 400 {object} utils.ApiResponse "Invalid discount rule"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Discount rule not found"
+// @Failure 412 {object} utils.ApiResponse "Discount rule was modified since the given ETag"
+// @Router /api/discount-rule/{id} [patch]
+func (h *DiscountHandler) UpdateDiscountRule(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	rule, err := h.repos.Discounts.FindByID(id)
+	if err != nil {
+		return discountRuleNotFound(c)
+	}
+	if expected != 0 && rule.Version != expected {
+		return preconditionFailed(c)
+	}
+	model := rule.Model
+
+	if err := c.BodyParser(rule); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	rule.Model = model
+
+	if reason := h.validate(rule); reason != "" {
+		return invalidDiscountRule(c, reason)
+	}
+
+	if err := h.repos.Discounts.Update(rule); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidReference):
+			return invalidDiscountRule(c, "product or category does not exist")
+		case errors.Is(err, repository.ErrVersionConflict):
+			return versionConflict(c)
+		case errors.Is(err, repository.ErrNotFound):
+			return discountRuleNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update discount rule",
+			Data:    err.Error(),
+		})
+	}
+
+	setETag(c, rule.Version)
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Discount rule updated successfully",
+		Data:    rule,
+	})
+}
+
+// DeleteDiscountRule - Handler for deleting a discount rule
+// @Summary Delete a discount rule
+// @Description Moves a discount rule to the trash by its ID. Admins only.
+// @Tags Discount
+// @Accept json
+// @Produce json
+// @Param id path int true "Discount rule ID"
+// @Param If-Match header string false "ETag of the version being deleted"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Discount rule not found"
+// @Failure 412 {object} utils.ApiResponse "Discount rule was modified since the given ETag"
+// @Router /api/discount-rule/{id} [delete]
+func (h *DiscountHandler) DeleteDiscountRule(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	if err := h.repos.Discounts.Delete(id, expected); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return discountRuleNotFound(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return preconditionFailed(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete discount rule",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Discount rule deleted successfully",
+		Data:    nil,
+	})
+}
+
+// validate normalizes rule in place and returns why it is invalid, or ""
+func (h *DiscountHandler) validate(rule *models.DiscountRule) string {
+	switch rule.Type {
+	case models.DiscountPercentage:
+		rule.Currency = ""
+		if rule.Value.GreaterThan(decimal.NewFromInt(100)) {
+			return "a percentage cannot be above 100"
+		}
+	case models.DiscountFixed:
+		rule.Currency = currency.Normalize(rule.Currency)
+		if rule.Currency == "" {
+			rule.Currency = h.currency.Base
+		}
+		if rule.Currency != h.currency.Base {
+			if _, err := h.repos.Currencies.FindRate(rule.Currency); err != nil {
+				return "unknown currency"
+			}
+		}
+	default:
+		return "type must be percentage or fixed"
+	}
+
+	switch {
+	case rule.Value.Sign() <= 0:
+		return "value must be positive"
+	case rule.ProductID != nil && rule.CategoryID != nil:
+		return "a rule is scoped to a product or a category, not both"
+	case rule.StartsAt != nil && rule.EndsAt != nil && !rule.EndsAt.After(*rule.StartsAt):
+		return "ends_at must be after starts_at"
+	}
+	return ""
+}
+
+// fillDiscounts fills in the EffectivePrice and Discounts of every product
+// in place, using the rules in force now. Prices are discounted in the
+// currency the products are shown in.
+func fillDiscounts(repos repository.Repositories, cfg config.CurrencyConfig, products []models.Product) error {
+	converter, err := loadConverter(repos, cfg)
+	if err != nil {
+		return err
+	}
+	discounter, err := pricing.NewDiscounter(repos, converter, time.Now())
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		discounter.Apply(&products[i])
+	}
+	return nil
+}
+
+func discountRuleNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Discount rule not found",
+		Data:    nil,
+	})
+}
+
+func invalidDiscountRule(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid discount rule",
+		Data:    reason,
+	})
+}
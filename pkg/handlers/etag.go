@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// etag formats a record version as a strong entity tag
+func etag(version uint) string {
+	return strconv.Quote(strconv.FormatUint(uint64(version), 10))
+}
+
+// setETag exposes the version of the returned record to the client
+func setETag(c *fiber.Ctx, version uint) {
+	c.Set(fiber.HeaderETag, etag(version))
+}
+
+// ifMatchVersion reads the If-Match header. It returns 0 when the header
+// is absent or "*", meaning any version is acceptable, and ok=false when
+// the header names no version the server could have issued.
+func ifMatchVersion(c *fiber.Ctx) (version uint, ok bool) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	// Only a single strong tag can identify the version to write against
+	if strings.Contains(header, ",") || strings.HasPrefix(header, "W/") {
+		return 0, false
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, false
+	}
+	parsed, err := strconv.ParseUint(unquoted, 10, 0)
+	if err != nil || parsed == 0 {
+		return 0, false
+	}
+	return uint(parsed), true
+}
+
+func preconditionFailed(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusPreconditionFailed).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Resource was modified, reload and retry",
+		Data:    nil,
+	})
+}
+
+// versionConflict answers a write that lost a race against another writer.
+// Conditional requests get 412, unconditional ones a plain conflict.
+func versionConflict(c *fiber.Ctx) error {
+	if c.Get(fiber.HeaderIfMatch) != "" {
+		return preconditionFailed(c)
+	}
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Resource was modified concurrently, reload and retry",
+		Data:    nil,
+	})
+}
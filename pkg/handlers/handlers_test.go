@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/middlewares"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/payment"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+// testWebhookSecret signs the events of the payment provider under test
+const testWebhookSecret = "whsec_test"
+
+// testUserHeader names the user a test request is made as: "<user id>",
+// or "<user id> admin" for an admin
+const testUserHeader = "X-Test-User"
+
+// testApp serves the handlers against fresh in-memory repositories
+type testApp struct {
+	t     *testing.T
+	app   *fiber.App
+	repos repository.Repositories
+}
+
+// testResponse is a decoded utils.ApiResponse together with the status
+// and ETag it was sent with
+type testResponse struct {
+	Status  int
+	ETag    string
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+
+	repos := repository.NewMemoryRepositories()
+	currencyCfg := config.CurrencyConfig{Base: "USD"}
+	taxCfg := config.TaxConfig{Region: "US"}
+	productHandler := NewProductHandler(repos, currencyCfg, taxCfg)
+	categoryHandler := NewCategoryHandler(repos)
+	userHandler := NewUserHandler(repos)
+	variantHandler := NewVariantHandler(repos)
+	reviewHandler := NewReviewHandler(repos)
+	stockHandler := NewStockHandler(repos)
+	reservationHandler := NewReservationHandler(repos, config.ReservationConfig{DefaultTTL: time.Minute, MaxTTL: time.Hour})
+	cartHandler := NewCartHandler(repos, currencyCfg, taxCfg)
+	couponHandler := NewCouponHandler(repos, currencyCfg)
+	provider := payment.NewMockProvider(testWebhookSecret, "")
+	orderHandler := NewOrderHandler(repos, currencyCfg, taxCfg, provider)
+	paymentHandler := NewPaymentHandler(repos, provider)
+
+	app := fiber.New()
+	app.Post("/api/users", userHandler.CreateUser)
+	app.Get("/api/users/:id", userHandler.GetUser)
+	app.Patch("/api/users/:id", authenticate, userHandler.UpdateUser)
+	app.Delete("/api/users/:id", authenticate, userHandler.DeleteUser)
+
+	app.Post("/api/product", authenticate, productHandler.CreateProduct)
+	app.Get("/api/products", productHandler.GetAllProducts)
+	app.Get("/api/products/trash", authenticate, productHandler.GetTrashedProducts)
+	app.Get("/api/product/:id", productHandler.GetProduct)
+	app.Patch("/api/product/:id", authenticate, productHandler.UpdateProduct)
+	app.Delete("/api/product/:id", authenticate, productHandler.DeleteProduct)
+	app.Post("/api/product/:id/restore", authenticate, productHandler.RestoreProduct)
+
+	app.Post("/api/product/:id/variants", authenticate, variantHandler.CreateVariant)
+	app.Get("/api/product/:id/variants/:variantId", variantHandler.GetVariant)
+	app.Patch("/api/product/:id/variants/:variantId", authenticate, variantHandler.UpdateVariant)
+
+	app.Post("/api/product/:id/reviews", authenticate, reviewHandler.CreateReview)
+	app.Patch("/api/review/:id", authenticate, reviewHandler.UpdateReview)
+	app.Put("/api/review/:id/status", authenticate, middlewares.Admin(), reviewHandler.ModerateReview)
+	app.Delete("/api/review/:id", authenticate, reviewHandler.DeleteReview)
+
+	app.Post("/api/product/:id/stock-movements", authenticate, stockHandler.CreateStockMovement)
+	app.Get("/api/product/:id/stock-movements", authenticate, stockHandler.GetStockMovements)
+
+	app.Post("/api/product/:id/reservations", authenticate, reservationHandler.CreateReservation)
+	app.Get("/api/reservations/:id", authenticate, reservationHandler.GetReservation)
+	app.Post("/api/reservations/:id/release", authenticate, reservationHandler.ReleaseReservation)
+
+	app.Get("/api/cart", authenticate, cartHandler.GetCart)
+	app.Post("/api/cart/items", authenticate, cartHandler.AddCartItem)
+	app.Patch("/api/cart/items/:productId", authenticate, cartHandler.UpdateCartItem)
+	app.Delete("/api/cart/items/:productId", authenticate, cartHandler.DeleteCartItem)
+
+	app.Post("/api/coupon", authenticate, middlewares.Admin(), couponHandler.CreateCoupon)
+	app.Get("/api/coupon/:id/redemptions", authenticate, middlewares.Admin(), couponHandler.GetCouponRedemptions)
+
+	app.Post("/api/orders", authenticate, orderHandler.CreateOrder)
+	app.Get("/api/orders/:id", authenticate, orderHandler.GetOrder)
+	app.Post("/api/orders/:id/transitions", authenticate, middlewares.Admin(), orderHandler.TransitionOrder)
+	app.Post("/api/orders/:id/payments", authenticate, paymentHandler.PayOrder)
+	app.Post("/api/payments/webhook", paymentHandler.HandleWebhook)
+
+	app.Post("/api/category", authenticate, categoryHandler.CreateCategory)
+	app.Get("/api/category/:id", categoryHandler.GetCategory)
+	app.Patch("/api/category/:id", authenticate, categoryHandler.UpdateCategory)
+	app.Delete("/api/category/:id", authenticate, categoryHandler.DeleteCategory)
+
+	a := &testApp{t: t, app: app, repos: repos}
+	// Stock movements and price changes point at the user who made them,
+	// so the user most tests act as has to exist
+	if id := a.createUser("staff@example.com"); id != 1 {
+		t.Fatalf("got user %d, want the first user to be 1", id)
+	}
+	return a
+}
+
+// authenticate stands in for middlewares.Protected, which reads the
+// signing key from a .env file: it puts the claims of the user named by
+// testUserHeader where Protected would put those of a verified token
+func authenticate(c *fiber.Ctx) error {
+	id, role, _ := strings.Cut(c.Get(testUserHeader), " ")
+	userID, err := strconv.ParseUint(id, 10, 0)
+	if err != nil || userID == 0 {
+		return unauthorized(c)
+	}
+	c.Locals("user", &jwt.Token{Claims: jwt.MapClaims{
+		"user_id": float64(userID),
+		"admin":   role == "admin",
+	}})
+	return c.Next()
+}
+
+// do sends a request as user, which is "" for an anonymous one. headers
+// are pairs of header names and values.
+func (a *testApp) do(method, path, user, body string, headers ...string) testResponse {
+	a.t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		req.Header.Set(testUserHeader, user)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp, err := a.app.Test(req, -1)
+	if err != nil {
+		a.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		a.t.Fatalf("%s %s: reading body: %v", method, path, err)
+	}
+
+	response := testResponse{Status: resp.StatusCode, ETag: resp.Header.Get(fiber.HeaderETag)}
+	if err := json.Unmarshal(payload, &response); err != nil {
+		a.t.Fatalf("%s %s: decoding %q: %v", method, path, payload, err)
+	}
+	return response
+}
+
+// expect fails the test unless response has the given status
+func (a *testApp) expect(response testResponse, status int) {
+	a.t.Helper()
+	if response.Status != status {
+		a.t.Fatalf("got status %d (%s), want %d", response.Status, response.Message, status)
+	}
+}
+
+// decode unmarshals the data of response into v
+func (a *testApp) decode(response testResponse, v interface{}) {
+	a.t.Helper()
+	if err := json.Unmarshal(response.Data, v); err != nil {
+		a.t.Fatalf("decoding %s: %v", response.Data, err)
+	}
+}
+
+// createUser registers a user and returns its ID
+func (a *testApp) createUser(email string) uint {
+	a.t.Helper()
+	response := a.do("POST", "/api/users", "", `{"firstName":"Ada","lastName":"Lovelace","email":"`+email+`","password":"secret"}`)
+	a.expect(response, fiber.StatusCreated)
+	var user struct {
+		ID uint `json:"id"`
+	}
+	a.decode(response, &user)
+	return user.ID
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/document"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// InvoiceHandler serves the invoices and packing slips of orders. Users
+// only get the documents of their own orders; admins get any.
+type InvoiceHandler struct {
+	repos   repository.Repositories
+	company document.Company
+}
+
+// NewInvoiceHandler creates an InvoiceHandler that prints the given
+// company details on every document
+func NewInvoiceHandler(repos repository.Repositories, companyCfg config.CompanyConfig) *InvoiceHandler {
+	return &InvoiceHandler{repos: repos, company: document.Company(companyCfg)}
+}
+
+// renderable is a document that can be rendered in either format
+type renderable interface {
+	HTML() ([]byte, error)
+	PDF() []byte
+}
+
+// GetInvoicePDF - Handler for getting the invoice of an order as PDF
+// @Summary Get an invoice as PDF
+// @Description Renders the invoice of an order as a PDF file. Orders are invoiced when they are
+// @Description paid; invoice numbers are handed out in sequence and never reused.
+// @Tags Order
+// @Produce application/pdf
+// @Param id path int true "Order ID"
+// @Success 200 {file} file
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Order or invoice not found"
+// @Router /api/orders/{id}/invoice.pdf [get]
+func (h *InvoiceHandler) GetInvoicePDF(c *fiber.Ctx) error {
+	return h.sendInvoice(c, "pdf")
+}
+
+// GetInvoiceHTML - Handler for getting the invoice of an order as HTML
+// @Summary Get an invoice as HTML
+// @Description Renders the invoice of an order as an HTML page
+// @Tags Order
+// @Produce html
+// @Param id path int true "Order ID"
+// @Success 200 {string} string
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Order or invoice not found"
+// @Router /api/orders/{id}/invoice.html [get]
+func (h *InvoiceHandler) GetInvoiceHTML(c *fiber.Ctx) error {
+	return h.sendInvoice(c, "html")
+}
+
+// GetPackingSlipPDF - Handler for getting the packing slip of an order as PDF
+// @Summary Get a packing slip as PDF
+// @Description Renders the packing slip of an order, listing its items without prices, as a
+// @Description PDF file
+// @Tags Order
+// @Produce application/pdf
+// @Param id path int true "Order ID"
+// @Success 200 {file} file
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Order not found"
+// @Router /api/orders/{id}/packing-slip.pdf [get]
+func (h *InvoiceHandler) GetPackingSlipPDF(c *fiber.Ctx) error {
+	return h.sendPackingSlip(c, "pdf")
+}
+
+// GetPackingSlipHTML - Handler for getting the packing slip of an order as HTML
+// @Summary Get a packing slip as HTML
+// @Description Renders the packing slip of an order as an HTML page
+// @Tags Order
+// @Produce html
+// @Param id path int true "Order ID"
+// @Success 200 {string} string
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Order not found"
+// @Router /api/orders/{id}/packing-slip.html [get]
+func (h *InvoiceHandler) GetPackingSlipHTML(c *fiber.Ctx) error {
+	return h.sendPackingSlip(c, "html")
+}
+
+func (h *InvoiceHandler) sendInvoice(c *fiber.Ctx, format string) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	order, err := h.repos.Orders.FindByID(id)
+	if err != nil || !canSeeOrder(c, order, *userID) {
+		return orderNotFound(c)
+	}
+
+	invoice, err := h.repos.Invoices.FindByOrder(order.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Invoice not found",
+			Data:    "orders are invoiced once they are paid",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve invoice",
+			Data:    err.Error(),
+		})
+	}
+
+	doc := document.Invoice{
+		Number:   invoice.Code(),
+		IssuedAt: invoice.CreatedAt,
+		Company:  h.company,
+		Customer: h.customer(order),
+		Order:    *order,
+		TaxLines: taxLines(order),
+	}
+	return sendDocument(c, format, doc.Number, doc)
+}
+
+func (h *InvoiceHandler) sendPackingSlip(c *fiber.Ctx, format string) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	order, err := h.repos.Orders.FindByID(id)
+	if err != nil || !canSeeOrder(c, order, *userID) {
+		return orderNotFound(c)
+	}
+
+	doc := document.PackingSlip{
+		Company:  h.company,
+		Customer: h.customer(order),
+		Order:    *order,
+	}
+	return sendDocument(c, format, fmt.Sprintf("packing-slip-%d", order.ID), doc)
+}
+
+// taxLines lists the tax on order at each rate. Tax that the prices
+// already included is marked as such, as it is not added to the total.
+func taxLines(order *models.Order) []document.TaxLine {
+	label := "Tax"
+	if order.PricesIncludeTax {
+		label = "Incl. tax"
+	}
+	lines := make([]document.TaxLine, 0, len(order.Taxes))
+	for _, tax := range order.Taxes {
+		lines = append(lines, document.TaxLine{
+			Label:  fmt.Sprintf("%s %s%% on %s", label, tax.Rate, tax.Net),
+			Amount: tax.Tax,
+		})
+	}
+	return lines
+}
+
+// customer returns who placed order. Orders of purged users have nobody.
+func (h *InvoiceHandler) customer(order *models.Order) document.Customer {
+	if order.UserID == nil {
+		return document.Customer{}
+	}
+	user, err := h.repos.Users.FindByID(*order.UserID)
+	if err != nil {
+		return document.Customer{}
+	}
+	return document.Customer{Name: user.FirstName + " " + user.LastName, Email: user.Email}
+}
+
+// sendDocument answers with doc rendered in format, pdf or html, offering
+// name as the file name
+func sendDocument(c *fiber.Ctx, format, name string, doc renderable) error {
+	if format == "html" {
+		body, err := doc.HTML()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Failed to render document",
+				Data:    err.Error(),
+			})
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Send(body)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", name+".pdf"))
+	return c.Send(doc.PDF())
+}
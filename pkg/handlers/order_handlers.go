@@ -0,0 +1,680 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/middlewares"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/payment"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+var (
+	errEmptyOrder          = errors.New("order has no items")
+	errUnknownCoupon       = errors.New("coupon does not exist")
+	errUnknownReservation  = errors.New("reservation does not exist")
+	errReservationMismatch = errors.New("reservations must hold units of the products ordered, and no more than ordered")
+	errIllegalTransition   = errors.New("illegal order transition")
+)
+
+// OrderHandler serves the order endpoints. Users only ever see their own
+// orders; admins see every order and move orders between states.
+type OrderHandler struct {
+	repos    repository.Repositories
+	currency config.CurrencyConfig
+	tax      config.TaxConfig
+	provider payment.Provider
+}
+
+// NewOrderHandler creates an OrderHandler backed by the given
+// repositories. Refunded orders are paid back through provider.
+func NewOrderHandler(repos repository.Repositories, currencyCfg config.CurrencyConfig, taxCfg config.TaxConfig, provider payment.Provider) *OrderHandler {
+	return &OrderHandler{repos: repos, currency: currencyCfg, tax: taxCfg, provider: provider}
+}
+
+// OrderRequest is the body of a checkout. Without Items the current
+// user's cart is checked out and emptied. ReservationIDs are reservations
+// of the current user whose units the order takes; they are confirmed
+// with it. An empty Currency means the base currency and an empty Region
+// the configured tax region.
+type OrderRequest struct {
+	Items          []CartItemRequest `json:"items"`
+	ReservationIDs []uint            `json:"reservation_ids"`
+	CouponCode     string            `json:"coupon_code" example:"SPRING10"`
+	Currency       string            `json:"currency" example:"USD"`
+	Region         string            `json:"region" example:"DE"`
+}
+
+// OrderTransitionRequest is the body of a change of an order's state
+type OrderTransitionRequest struct {
+	Status string `json:"status" example:"paid"`
+	Note   string `json:"note" example:"Paid by bank transfer"`
+}
+
+// CreateOrder - Handler for checking out
+// @Summary Place an order
+// @Description Turns the given items, or the current user's cart when there are none, into an
+// @Description order priced at the current prices after the discount rules in force, in
+// @Description currency. An optional coupon_code is redeemed against the subtotal, and what
+// @Description remains is taxed for region at the rate of each product's tax class. Items of a
+// @Description variant are priced at its price override, if it has one. The stock of every
+// @Description product and variant is taken down in the same transaction, so concurrent orders
+// @Description cannot sell more than is available. Units held by the current user's active
+// @Description reservations listed in reservation_ids count as available to the order, and
+// @Description those reservations are confirmed with it.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param order body OrderRequest true "Order"
+// @Success 201 {object} models.Order
+// @Failure 400 {object} utils.ApiResponse "Invalid order"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Product, variant, reservation or coupon not found"
+// @Failure 409 {object} utils.ApiResponse "Insufficient stock, reservation not active or expired, or coupon used up"
+// @Router /api/orders [post]
+func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+
+	var request OrderRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+
+	request.Currency = currency.Normalize(request.Currency)
+	if request.Currency == "" {
+		request.Currency = h.currency.Base
+	}
+	if request.Currency != h.currency.Base {
+		if _, err := h.repos.Currencies.FindRate(request.Currency); err != nil {
+			return invalidCurrency(c)
+		}
+	}
+	region, ok := checkRegion(h.repos, h.tax, request.Region)
+	if !ok {
+		return invalidRegion(c)
+	}
+	request.Region = region
+
+	items, ok := requestedItems(*userID, request.Items)
+	if !ok {
+		return invalidOrder(c, "quantity must be positive")
+	}
+	slices.Sort(request.ReservationIDs)
+	request.ReservationIDs = slices.Compact(request.ReservationIDs)
+
+	var order models.Order
+	err := h.repos.Transaction(func(tx repository.Repositories) error {
+		var err error
+		fromCart := len(items) == 0
+		if fromCart {
+			if items, err = tx.Carts.FindByUser(*userID); err != nil {
+				return err
+			}
+		}
+		if order, err = h.checkout(tx, *userID, items, request); err != nil {
+			return err
+		}
+		if fromCart {
+			return tx.Carts.Clear(*userID)
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errEmptyOrder), errors.Is(err, errReservationMismatch):
+			return invalidOrder(c, err.Error())
+		case errors.Is(err, errUnknownCoupon):
+			return couponNotFound(c)
+		case errors.Is(err, errUnknownReservation):
+			return reservationNotFound(c)
+		case errors.Is(err, errReservationNotActive):
+			return reservationNotActive(c)
+		case errors.Is(err, errReservationExpired):
+			return reservationExpired(c)
+		case errors.Is(err, errVariantNotFound):
+			return variantNotFound(c)
+		case errors.Is(err, repository.ErrNotFound), errors.Is(err, repository.ErrInvalidReference):
+			return productNotFound(c)
+		case errors.Is(err, repository.ErrInsufficientStock):
+			return insufficientStock(c)
+		}
+		return couponError(c, err, "Failed to place order")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Order placed successfully",
+		Data:    order,
+	})
+}
+
+// GetOrders - Handler for listing the current user's orders
+// @Summary Get my orders
+// @Description Retrieves the current user's orders with their items, newest first
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Order
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Router /api/orders [get]
+func (h *OrderHandler) GetOrders(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+
+	orders, err := h.repos.Orders.FindByUser(*userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve orders",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Orders retrieved successfully",
+		Data:    orders,
+	})
+}
+
+// GetOrder - Handler for getting one of the current user's orders
+// @Summary Get an order
+// @Description Retrieves an order of the current user by its ID, with its items. Admins can
+// @Description retrieve any order.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} models.Order
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Order not found"
+// @Router /api/orders/{id} [get]
+func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	order, err := h.repos.Orders.FindByID(id)
+	if err != nil || !canSeeOrder(c, order, *userID) {
+		return orderNotFound(c)
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Order retrieved successfully",
+		Data:    order,
+	})
+}
+
+// GetOrderTransitions - Handler for getting the history of an order's states
+// @Summary Get order history
+// @Description Retrieves every change of state of an order, oldest first, with who made it
+// @Description and when. The first entry records the order being placed. Users can only see
+// @Description the history of their own orders; admins can see any.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {array} models.OrderTransition
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Order not found"
+// @Router /api/orders/{id}/transitions [get]
+func (h *OrderHandler) GetOrderTransitions(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	order, err := h.repos.Orders.FindByID(id)
+	if err != nil || !canSeeOrder(c, order, *userID) {
+		return orderNotFound(c)
+	}
+
+	transitions, err := h.repos.Orders.FindTransitions(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve order history",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Order history retrieved successfully",
+		Data:    transitions,
+	})
+}
+
+// TransitionOrder - Handler for moving an order to another state
+// @Summary Change the state of an order
+// @Description Moves an order to status and records who did it in its history. Orders move
+// @Description from pending to paid or cancelled, from paid to fulfilled or refunded, from
+// @Description fulfilled to shipped or refunded, from shipped to delivered and from delivered
+// @Description to refunded; pending orders whose payment fails move to failed. Cancelled,
+// @Description refunded and failed orders are final and have their items put back in stock.
+// @Description Refunding an order pays back its payments. Any other move is refused with 409.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param transition body OrderTransitionRequest true "New state"
+// @Success 200 {object} models.Order
+// @Failure 400 {object} utils.ApiResponse "Invalid order transition"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Order not found"
+// @Failure 409 {object} utils.ApiResponse "Illegal order transition"
+// @Router /api/orders/{id}/transitions [post]
+func (h *OrderHandler) TransitionOrder(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request OrderTransitionRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if !models.IsOrderStatus(request.Status) {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Invalid order transition",
+			Data:    fmt.Sprintf("unknown order status %q", request.Status),
+		})
+	}
+
+	var order *models.Order
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		var err error
+		if order, err = tx.Orders.FindByID(id); err != nil {
+			return err
+		}
+		if err := transitionOrder(tx, order, request.Status, request.Note, currentUserID(c)); err != nil {
+			return err
+		}
+		if request.Status == models.OrderRefunded {
+			return refundPayments(tx, h.provider, order.ID)
+		}
+		return nil
+	})
+	if errors.Is(err, repository.ErrVersionConflict) {
+		// Somebody else moved the order first; report the state it is in now
+		if current, findErr := h.repos.Orders.FindByID(id); findErr == nil {
+			order = current
+		}
+		err = errIllegalTransition
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return orderNotFound(c)
+		case errors.Is(err, errIllegalTransition):
+			return illegalTransition(c, order.Status, request.Status)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to change order status",
+			Data:    err.Error(),
+		})
+	}
+
+	if order, err = h.repos.Orders.FindByID(id); err != nil {
+		return orderNotFound(c)
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Order status changed successfully",
+		Data:    order,
+	})
+}
+
+// checkout places an order for the items and takes them out of stock,
+// confirming the reservations of the request on the way. The products stay
+// locked until tx ends, so the stock checked here, theirs or that of their
+// variants, is still there when it is taken.
+func (h *OrderHandler) checkout(tx repository.Repositories, userID uint, items []models.CartItem, request OrderRequest) (models.Order, error) {
+	if len(items) == 0 {
+		return models.Order{}, errEmptyOrder
+	}
+
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+	slices.Sort(ids)
+	if err := tx.Products.Lock(ids...); err != nil {
+		return models.Order{}, err
+	}
+	held, err := heldReservations(tx, userID, items, request.ReservationIDs)
+	if err != nil {
+		return models.Order{}, err
+	}
+	for _, item := range items {
+		available, err := lineAvailable(tx, item)
+		if err != nil {
+			return models.Order{}, err
+		}
+		// The buyer's own holds are part of what the order may take
+		if item.VariantID == nil {
+			available += held[item.ProductID]
+		}
+		if item.Quantity > available {
+			return models.Order{}, repository.ErrInsufficientStock
+		}
+	}
+
+	cart, err := priceCart(tx, h.currency, h.tax, userID, items, request.Currency, request.Region)
+	if err != nil {
+		return models.Order{}, err
+	}
+	converter, err := loadConverter(tx, h.currency)
+	if err != nil {
+		return models.Order{}, err
+	}
+	taxer, err := pricing.NewTaxer(tx, converter, request.Region, h.tax.PricesIncludeTax)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	order := models.Order{
+		UserID:           &userID,
+		Status:           models.OrderPending,
+		Currency:         cart.Currency,
+		Subtotal:         cart.Subtotal,
+		Discount:         converter.Round(decimal.Zero, cart.Currency),
+		TaxRegion:        cart.TaxRegion,
+		PricesIncludeTax: cart.PricesIncludeTax,
+		Items:            make([]models.OrderItem, 0, len(cart.Items)),
+	}
+	amounts := make([]pricing.TaxedAmount, 0, len(cart.Items))
+	for _, line := range cart.Items {
+		productID := line.ProductID
+		order.Items = append(order.Items, models.OrderItem{
+			ProductID: &productID,
+			VariantID: line.VariantID,
+			Name:      line.Name,
+			SKU:       line.SKU,
+			UnitPrice: line.UnitPrice,
+			Quantity:  line.Quantity,
+			LineTotal: line.LineTotal,
+			TaxRate:   line.TaxRate,
+		})
+		amounts = append(amounts, pricing.TaxedAmount{Amount: line.LineTotal, Rate: line.TaxRate})
+	}
+
+	var redemptionID uint
+	if request.CouponCode != "" {
+		quote, err := pricing.RedeemCoupon(tx, converter, request.CouponCode, cart.Subtotal, cart.Currency, &userID, time.Now())
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.Order{}, errUnknownCoupon
+		}
+		if err != nil {
+			return models.Order{}, err
+		}
+		order.CouponID = &quote.CouponID
+		order.CouponCode = quote.Code
+		order.Discount = quote.Discount
+		redemptionID = quote.RedemptionID
+	}
+
+	// The discount comes off the goods before they are taxed
+	breakdowns := taxer.Summarize(amounts, order.Discount, cart.Currency)
+	for _, breakdown := range breakdowns {
+		order.Taxes = append(order.Taxes, models.OrderTax{TaxBreakdown: breakdown})
+	}
+	total := taxer.Total(breakdowns, cart.Currency)
+	order.Net, order.Tax, order.Total = total.Net, total.Tax, total.Gross
+
+	if err := tx.Orders.Create(&order); err != nil {
+		return models.Order{}, err
+	}
+	if redemptionID != 0 {
+		if err := tx.Coupons.LinkRedemption(redemptionID, order.ID); err != nil {
+			return models.Order{}, err
+		}
+	}
+	err = tx.Orders.CreateTransition(&models.OrderTransition{OrderID: order.ID, To: order.Status, ActorID: &userID})
+	if err != nil {
+		return models.Order{}, err
+	}
+	for _, id := range request.ReservationIDs {
+		err := tx.Reservations.SetStatus(id, models.ReservationActive, models.ReservationConfirmed)
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return models.Order{}, errReservationNotActive
+		}
+		if err != nil {
+			return models.Order{}, err
+		}
+	}
+
+	reason := fmt.Sprintf("Order %d", order.ID)
+	for _, item := range order.Items {
+		if item.VariantID != nil {
+			balance, err := tx.Variants.AdjustQty(*item.VariantID, -item.Quantity)
+			if err != nil {
+				return models.Order{}, err
+			}
+			if _, err := recordVariantMovement(tx, *item.ProductID, *item.VariantID, models.StockSale, -item.Quantity, balance, reason, &userID); err != nil {
+				return models.Order{}, err
+			}
+			continue
+		}
+		balance, err := tx.Products.AdjustQty(*item.ProductID, -item.Quantity)
+		if err != nil {
+			return models.Order{}, err
+		}
+		if err := drawDownLevels(tx, *item.ProductID, balance); err != nil {
+			return models.Order{}, err
+		}
+		if _, err := recordMovement(tx, *item.ProductID, nil, models.StockSale, -item.Quantity, balance, reason, &userID); err != nil {
+			return models.Order{}, err
+		}
+	}
+	return order, nil
+}
+
+// heldReservations checks that the reservations with the given IDs belong
+// to userID, are active and hold no more units of a product than the items
+// take of it, and returns how many units they hold of each product. The
+// products of the items must already be locked in tx.
+func heldReservations(tx repository.Repositories, userID uint, items []models.CartItem, ids []uint) (map[uint]int, error) {
+	ordered := make(map[uint]int, len(items))
+	for _, item := range items {
+		if item.VariantID == nil {
+			ordered[item.ProductID] += item.Quantity
+		}
+	}
+
+	held := make(map[uint]int, len(ids))
+	for _, id := range ids {
+		reservation, err := tx.Reservations.FindByID(id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errUnknownReservation
+		}
+		if err != nil {
+			return nil, err
+		}
+		if reservation.UserID == nil || *reservation.UserID != userID {
+			return nil, errUnknownReservation
+		}
+		if reservation.Status != models.ReservationActive {
+			return nil, errReservationNotActive
+		}
+		if !reservation.ExpiresAt.After(time.Now()) {
+			return nil, errReservationExpired
+		}
+		held[reservation.ProductID] += reservation.Quantity
+		if held[reservation.ProductID] > ordered[reservation.ProductID] {
+			return nil, errReservationMismatch
+		}
+	}
+	return held, nil
+}
+
+// requestedItems turns the items of a request into cart items of userID,
+// adding up the quantities of a product or variant listed more than once.
+// It reports false when a quantity is not positive.
+func requestedItems(userID uint, requested []CartItemRequest) ([]models.CartItem, bool) {
+	merged := make([]CartItemRequest, 0, len(requested))
+	for _, item := range requested {
+		if item.Quantity <= 0 {
+			return nil, false
+		}
+		index := slices.IndexFunc(merged, func(existing CartItemRequest) bool {
+			return existing.ProductID == item.ProductID && existing.VariantID == item.VariantID
+		})
+		if index >= 0 {
+			merged[index].Quantity += item.Quantity
+			continue
+		}
+		merged = append(merged, item)
+	}
+
+	items := make([]models.CartItem, 0, len(merged))
+	for _, item := range merged {
+		items = append(items, models.CartItem{UserID: userID, ProductID: item.ProductID, VariantID: variantRef(item.VariantID), Quantity: item.Quantity})
+	}
+	return items, true
+}
+
+// transitionOrder moves order to the given state in tx and records the
+// change in its history. Paying for an order issues its invoice.
+// Cancelling or refunding an order, or its payment failing, puts its items
+// back in stock, that of their variant for items of a variant, and gives
+// the use of its coupon back. Products and variants in the trash are
+// restocked all the same, so the units are there when they are restored;
+// only items whose product or variant has been purged have nowhere to go.
+func transitionOrder(tx repository.Repositories, order *models.Order, to, note string, actorID *uint) error {
+	if !models.CanTransition(order.Status, to) {
+		return errIllegalTransition
+	}
+
+	var ids []uint
+	if models.Restocks(to) {
+		for _, item := range order.Items {
+			if item.ProductID != nil {
+				ids = append(ids, *item.ProductID)
+			}
+		}
+		slices.Sort(ids)
+		if err := tx.Products.Lock(ids...); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Orders.SetStatus(order.ID, order.Status, to); err != nil {
+		return err
+	}
+	err := tx.Orders.CreateTransition(&models.OrderTransition{OrderID: order.ID, From: order.Status, To: to, Note: note, ActorID: actorID})
+	if err != nil {
+		return err
+	}
+	if to == models.OrderPaid {
+		if err := tx.Invoices.Issue(&models.Invoice{OrderID: order.ID}); err != nil {
+			return err
+		}
+	}
+	if models.Restocks(to) {
+		if err := tx.Coupons.ReleaseRedemption(order.ID); err != nil {
+			return err
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	reason := fmt.Sprintf("Order %d %s", order.ID, to)
+	for _, item := range order.Items {
+		if item.ProductID == nil {
+			continue
+		}
+		// An item that named a variant keeps its SKU once the variant is
+		// purged; its units must not go to the stock of the product itself
+		if item.VariantID == nil && item.SKU != "" {
+			continue
+		}
+		if item.VariantID != nil {
+			balance, err := tx.Variants.Restock(*item.VariantID, item.Quantity)
+			if err != nil {
+				return err
+			}
+			if _, err := recordVariantMovement(tx, *item.ProductID, *item.VariantID, models.StockReturn, item.Quantity, balance, reason, actorID); err != nil {
+				return err
+			}
+			continue
+		}
+		balance, err := tx.Products.Restock(*item.ProductID, item.Quantity)
+		if err != nil {
+			return err
+		}
+		if _, err := recordMovement(tx, *item.ProductID, nil, models.StockReturn, item.Quantity, balance, reason, actorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// canSeeOrder reports whether the user making the request may see order
+func canSeeOrder(c *fiber.Ctx, order *models.Order, userID uint) bool {
+	if middlewares.IsAdmin(c) {
+		return true
+	}
+	return order.UserID != nil && *order.UserID == userID
+}
+
+func orderNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Order not found",
+		Data:    nil,
+	})
+}
+
+func invalidOrder(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid order",
+		Data:    reason,
+	})
+}
+
+func illegalTransition(c *fiber.Ctx, from, to string) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Illegal order transition",
+		Data:    fmt.Sprintf("order cannot move from %s to %s", from, to),
+	})
+}
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// orderItem is an item of an order as the order endpoints return it
+type orderItem struct {
+	ProductID *uint  `json:"product_id"`
+	VariantID *uint  `json:"variant_id"`
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice string `json:"unit_price"`
+}
+
+// placedOrder is an order as the order endpoints return it
+type placedOrder struct {
+	ID     uint        `json:"id"`
+	Status string      `json:"status"`
+	Total  string      `json:"total"`
+	Items  []orderItem `json:"items"`
+}
+
+// placeOrder checks out body as user and returns the order
+func (a *testApp) placeOrder(user, body string) placedOrder {
+	a.t.Helper()
+	response := a.do("POST", "/api/orders", user, body)
+	a.expect(response, fiber.StatusCreated)
+	var order placedOrder
+	a.decode(response, &order)
+	return order
+}
+
+// qty returns the stock on hand of the resource at path
+func (a *testApp) qty(path string) int {
+	a.t.Helper()
+	response := a.do("GET", path, "", "")
+	a.expect(response, fiber.StatusOK)
+	var stock struct {
+		Qty int `json:"qty"`
+	}
+	a.decode(response, &stock)
+	return stock.Qty
+}
+
+func TestCheckoutVariant(t *testing.T) {
+	app := newTestApp(t)
+	shirt := app.createProduct("Shirt")
+	large := app.createVariant(shirt, "SHIRT-L", 3)
+	productPath := fmt.Sprintf("/api/product/%d", shirt)
+	variantPath := fmt.Sprintf("%s/variants/%d", productPath, large)
+	app.expect(app.do("PATCH", variantPath, "1", `{"sku":"SHIRT-L","qty":3,"price":"25.00"}`), fiber.StatusOK)
+
+	app.expect(app.do("POST", "/api/orders", "1", fmt.Sprintf(`{"items":[{"product_id":%d,"variant_id":%d,"quantity":4}]}`, shirt, large)), fiber.StatusConflict)
+
+	order := app.placeOrder("1", fmt.Sprintf(`{"items":[{"product_id":%d,"variant_id":%d,"quantity":2}]}`, shirt, large))
+	if len(order.Items) != 1 {
+		t.Fatalf("got %d order items, want 1", len(order.Items))
+	}
+	item := order.Items[0]
+	if item.VariantID == nil || *item.VariantID != large || item.SKU != "SHIRT-L" || item.UnitPrice != "25.00" {
+		t.Fatalf("got item %+v, want 2 of SHIRT-L at 25.00", item)
+	}
+	if qty := app.qty(variantPath); qty != 1 {
+		t.Fatalf("got %d of the variant after the sale, want 1", qty)
+	}
+	if qty := app.qty(productPath); qty != 5 {
+		t.Fatalf("got %d of the product after selling a variant, want 5", qty)
+	}
+
+	app.expect(app.do("POST", fmt.Sprintf("/api/orders/%d/transitions", order.ID), "1 admin", `{"status":"cancelled"}`), fiber.StatusOK)
+	if qty := app.qty(variantPath); qty != 3 {
+		t.Fatalf("got %d of the variant after cancelling, want 3", qty)
+	}
+
+	var movements []struct {
+		VariantID *uint  `json:"variant_id"`
+		Type      string `json:"type"`
+		Quantity  int    `json:"quantity"`
+		Balance   int    `json:"balance"`
+	}
+	app.decode(app.do("GET", productPath+"/stock-movements", "1", ""), &movements)
+	var sold, returned bool
+	for _, movement := range movements {
+		if movement.VariantID == nil || *movement.VariantID != large {
+			continue
+		}
+		sold = sold || movement.Type == "sale" && movement.Quantity == -2 && movement.Balance == 1
+		returned = returned || movement.Type == "return" && movement.Quantity == 2 && movement.Balance == 3
+	}
+	if !sold || !returned {
+		t.Fatalf("got variant movements %+v, want the sale and its return", movements)
+	}
+}
+
+func TestCheckoutCartWithVariants(t *testing.T) {
+	app := newTestApp(t)
+	shirt := app.createProduct("Shirt")
+	large := app.createVariant(shirt, "SHIRT-L", 3)
+
+	app.expect(app.do("POST", "/api/cart/items", "1", fmt.Sprintf(`{"product_id":%d,"quantity":1}`, shirt)), fiber.StatusCreated)
+	app.expect(app.do("POST", "/api/cart/items", "1", fmt.Sprintf(`{"product_id":%d,"variant_id":%d,"quantity":2}`, shirt, large)), fiber.StatusCreated)
+
+	order := app.placeOrder("1", `{}`)
+	if len(order.Items) != 2 || order.Items[0].VariantID != nil || order.Items[1].VariantID == nil {
+		t.Fatalf("got items %+v, want a line for the product and one for its variant", order.Items)
+	}
+	if qty := app.qty(fmt.Sprintf("/api/product/%d", shirt)); qty != 4 {
+		t.Fatalf("got %d of the product, want 4", qty)
+	}
+	if qty := app.qty(fmt.Sprintf("/api/product/%d/variants/%d", shirt, large)); qty != 1 {
+		t.Fatalf("got %d of the variant, want 1", qty)
+	}
+	if lines := app.cart("1"); len(lines) != 0 {
+		t.Fatalf("got %d cart lines after checkout, want an empty cart", len(lines))
+	}
+}
+
+// reserve holds quantity units of a product for user and returns the ID
+// of the reservation
+func (a *testApp) reserve(user string, productID uint, quantity int) uint {
+	a.t.Helper()
+	response := a.do("POST", fmt.Sprintf("/api/product/%d/reservations", productID), user, fmt.Sprintf(`{"quantity":%d}`, quantity))
+	a.expect(response, fiber.StatusCreated)
+	var reservation struct {
+		ID uint `json:"id"`
+	}
+	a.decode(response, &reservation)
+	return reservation.ID
+}
+
+func TestCheckoutOwnReservation(t *testing.T) {
+	app := newTestApp(t)
+	buyer := fmt.Sprint(app.createUser("buyer@example.com"))
+	lamp := app.createProduct("Lamp")
+	reservation := app.reserve(buyer, lamp, 5)
+	items := fmt.Sprintf(`"items":[{"product_id":%d,"quantity":5}]`, lamp)
+
+	// Without naming the reservation the buyer competes with it
+	app.expect(app.do("POST", "/api/orders", buyer, "{"+items+"}"), fiber.StatusConflict)
+
+	app.placeOrder(buyer, fmt.Sprintf(`{%s,"reservation_ids":[%d,%d]}`, items, reservation, reservation))
+	var stored struct {
+		Status string `json:"status"`
+	}
+	app.decode(app.do("GET", fmt.Sprintf("/api/reservations/%d", reservation), buyer, ""), &stored)
+	if stored.Status != "confirmed" {
+		t.Fatalf("got reservation %s after checkout, want it confirmed", stored.Status)
+	}
+	if qty := app.qty(fmt.Sprintf("/api/product/%d", lamp)); qty != 0 {
+		t.Fatalf("got %d lamps after checkout, want 0", qty)
+	}
+	app.expect(app.do("POST", "/api/orders", buyer, fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}],"reservation_ids":[%d]}`, lamp, reservation)), fiber.StatusConflict)
+}
+
+func TestCheckoutRejectsReservations(t *testing.T) {
+	app := newTestApp(t)
+	buyer := fmt.Sprint(app.createUser("buyer@example.com"))
+	lamp := app.createProduct("Lamp")
+	desk := app.createProduct("Desk")
+	mine := app.reserve(buyer, lamp, 2)
+	theirs := app.reserve("1", lamp, 1)
+	released := app.reserve(buyer, lamp, 1)
+	app.expect(app.do("POST", fmt.Sprintf("/api/reservations/%d/release", released), buyer, ""), fiber.StatusOK)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"another user's", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}],"reservation_ids":[%d]}`, lamp, theirs), fiber.StatusNotFound},
+		{"unknown", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}],"reservation_ids":[99]}`, lamp), fiber.StatusNotFound},
+		{"released", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}],"reservation_ids":[%d]}`, lamp, released), fiber.StatusConflict},
+		{"more than ordered", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}],"reservation_ids":[%d]}`, lamp, mine), fiber.StatusBadRequest},
+		{"another product", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":2}],"reservation_ids":[%d]}`, desk, mine), fiber.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app.t = t
+			app.expect(app.do("POST", "/api/orders", buyer, test.body), test.status)
+		})
+	}
+	app.t = t
+
+	var stored struct {
+		Status string `json:"status"`
+	}
+	app.decode(app.do("GET", fmt.Sprintf("/api/reservations/%d", mine), buyer, ""), &stored)
+	if stored.Status != "active" {
+		t.Fatalf("got reservation %s after the rejected orders, want it still active", stored.Status)
+	}
+}
+
+func TestConcurrentCheckoutDoesNotOversell(t *testing.T) {
+	app := newTestApp(t)
+	lamp := app.createProduct("Lamp")
+	body := fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}]}`, lamp)
+
+	const buyers = 12
+	statuses := make(chan int, buyers)
+	var wg sync.WaitGroup
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("POST", "/api/orders", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(testUserHeader, "1")
+			resp, err := app.app.Test(req, -1)
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+	if counts[fiber.StatusCreated] != 5 || counts[fiber.StatusConflict] != buyers-5 {
+		t.Fatalf("got statuses %v, want 5 orders placed and the rest refused", counts)
+	}
+	if qty := app.qty(fmt.Sprintf("/api/product/%d", lamp)); qty != 0 {
+		t.Fatalf("got %d lamps left, want 0", qty)
+	}
+}
+
+// transition moves an order to status as an admin
+func (a *testApp) transition(orderID uint, status string) testResponse {
+	a.t.Helper()
+	return a.do("POST", fmt.Sprintf("/api/orders/%d/transitions", orderID), "1 admin", `{"status":"`+status+`"}`)
+}
+
+func TestOrderTransitions(t *testing.T) {
+	app := newTestApp(t)
+	lamp := app.createProduct("Lamp")
+	productPath := fmt.Sprintf("/api/product/%d", lamp)
+
+	tests := []struct {
+		name   string
+		steps  []string
+		status int
+		qty    int
+	}{
+		{"pay", []string{"paid"}, fiber.StatusOK, 3},
+		{"cancel", []string{"cancelled"}, fiber.StatusOK, 5},
+		{"payment failing", []string{"failed"}, fiber.StatusOK, 5},
+		{"refund after delivery", []string{"paid", "fulfilled", "shipped", "delivered", "refunded"}, fiber.StatusOK, 5},
+		{"ship before fulfilling", []string{"paid", "shipped"}, fiber.StatusConflict, 3},
+		{"cancel once paid", []string{"paid", "cancelled"}, fiber.StatusConflict, 3},
+		{"refund once shipped", []string{"paid", "fulfilled", "shipped", "refunded"}, fiber.StatusConflict, 3},
+		{"pay a cancelled order", []string{"cancelled", "paid"}, fiber.StatusConflict, 5},
+		{"cancel twice", []string{"cancelled", "cancelled"}, fiber.StatusConflict, 5},
+		{"back to pending", []string{"pending"}, fiber.StatusConflict, 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app.t = t
+			app.expect(app.do("PATCH", productPath, "1", `{"qty":5}`), fiber.StatusOK)
+			order := app.placeOrder("1", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":2}]}`, lamp))
+
+			last := len(test.steps) - 1
+			for _, step := range test.steps[:last] {
+				app.expect(app.transition(order.ID, step), fiber.StatusOK)
+			}
+			app.expect(app.transition(order.ID, test.steps[last]), test.status)
+
+			if qty := app.qty(productPath); qty != test.qty {
+				t.Fatalf("got %d in stock, want %d", qty, test.qty)
+			}
+		})
+	}
+	app.t = t
+}
+
+func TestCancelRestocksTrashedProduct(t *testing.T) {
+	app := newTestApp(t)
+	lamp := app.createProduct("Lamp")
+	productPath := fmt.Sprintf("/api/product/%d", lamp)
+	order := app.placeOrder("1", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":2}]}`, lamp))
+
+	app.expect(app.do("DELETE", productPath, "1", ""), fiber.StatusOK)
+	app.expect(app.transition(order.ID, "cancelled"), fiber.StatusOK)
+	app.expect(app.do("POST", productPath+"/restore", "1", ""), fiber.StatusOK)
+
+	if qty := app.qty(productPath); qty != 5 {
+		t.Fatalf("got %d in stock after restoring, want the 2 cancelled units back for 5", qty)
+	}
+}
+
+func TestCancelReleasesCoupon(t *testing.T) {
+	app := newTestApp(t)
+	lamp := app.createProduct("Lamp")
+	response := app.do("POST", "/api/coupon", "1 admin", `{"code":"ONCE","type":"fixed","value":"1.00","currency":"USD","usage_limit":1}`)
+	app.expect(response, fiber.StatusCreated)
+	var coupon struct {
+		ID uint `json:"id"`
+	}
+	app.decode(response, &coupon)
+
+	body := fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}],"coupon_code":"ONCE"}`, lamp)
+	first := app.placeOrder("1", body)
+	app.expect(app.do("POST", "/api/orders", "1", body), fiber.StatusConflict)
+
+	app.expect(app.transition(first.ID, "cancelled"), fiber.StatusOK)
+	second := app.placeOrder("1", body)
+
+	var redemptions []struct {
+		OrderID    *uint   `json:"order_id"`
+		ReleasedAt *string `json:"released_at"`
+	}
+	app.decode(app.do("GET", fmt.Sprintf("/api/coupon/%d/redemptions", coupon.ID), "1 admin", ""), &redemptions)
+	if len(redemptions) != 2 {
+		t.Fatalf("got %d redemptions, want the released one and the new one", len(redemptions))
+	}
+	if redemptions[0].OrderID == nil || *redemptions[0].OrderID != first.ID || redemptions[0].ReleasedAt == nil {
+		t.Fatalf("got first redemption %+v, want that of order %d, released", redemptions[0], first.ID)
+	}
+	if redemptions[1].OrderID == nil || *redemptions[1].OrderID != second.ID || redemptions[1].ReleasedAt != nil {
+		t.Fatalf("got second redemption %+v, want that of order %d, in use", redemptions[1], second.ID)
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// paramID parses the ":id" route parameter as a positive integer
+func paramID(c *fiber.Ctx) (uint, error) {
+	return paramUint(c, "id")
+}
+
+// paramUint parses the named route parameter as a positive integer
+func paramUint(c *fiber.Ctx, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Params(name), 10, 0)
+	if err != nil {
+		return 0, err
+	}
+	if id == 0 {
+		return 0, errors.New(name + " must be positive")
+	}
+	return uint(id), nil
+}
+
+// paramCurrency reads the ":currency" route parameter as an upper-case
+// code. Fiber reuses the request buffer behind c.Params, so the value is
+// copied before it can be stored.
+func paramCurrency(c *fiber.Ctx) string {
+	return currency.Normalize(strings.Clone(c.Params("currency")))
+}
+
+// paramRegion reads the ":region" route parameter as an upper-case tax
+// region code, copied for the same reason as in paramCurrency
+func paramRegion(c *fiber.Ctx) string {
+	return pricing.NormalizeRegion(strings.Clone(c.Params("region")))
+}
+
+// Size of a page of a paginated list, unless the request asks otherwise,
+// and the most it can ask for
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// paramPage reads the "page" and "limit" query parameters of a paginated
+// list. Pages are counted from 1. It reports false for a page below 1 or a
+// limit outside 1 to 100.
+func paramPage(c *fiber.Ctx) (int, int, bool) {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", defaultPageSize)
+	return page, limit, page >= 1 && limit >= 1 && limit <= maxPageSize
+}
+
+func invalidPage(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid page",
+		Data:    "page must be positive and limit between 1 and 100",
+	})
+}
+
+func invalidID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid ID format",
+		Data:    nil,
+	})
+}
+
+// includes reports whether the comma-separated "include" query
+// parameter asks for the given relation
+func includes(c *fiber.Ctx, relation string) bool {
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(include) == relation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/payment"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+var (
+	errOrderNotPayable   = errors.New("only pending orders can be paid")
+	errPaymentInProgress = errors.New("order already has a payment in progress")
+)
+
+// PaymentHandler serves the payment endpoints and the webhook the payment
+// provider reports outcomes to
+type PaymentHandler struct {
+	repos    repository.Repositories
+	provider payment.Provider
+}
+
+// NewPaymentHandler creates a PaymentHandler that collects payments
+// through provider
+func NewPaymentHandler(repos repository.Repositories, provider payment.Provider) *PaymentHandler {
+	return &PaymentHandler{repos: repos, provider: provider}
+}
+
+// PaymentRequest is the body of a payment. Method names the means of
+// payment the provider charges; the mock provider declines mock_declined.
+type PaymentRequest struct {
+	Method string `json:"method" example:"mock_card"`
+}
+
+// PayOrder - Handler for paying for an order
+// @Summary Pay for an order
+// @Description Asks the payment provider to collect the total of one of the current user's
+// @Description pending orders. The payment stays pending until the provider reports the
+// @Description outcome to the webhook, which then moves the order to paid or failed. An order
+// @Description with nothing to pay is paid at once without asking the provider.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param payment body PaymentRequest true "Payment"
+// @Success 200 {object} models.Order "Order with nothing to pay"
+// @Success 202 {object} models.Payment
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Order not found"
+// @Failure 409 {object} utils.ApiResponse "Order cannot be paid"
+// @Failure 502 {object} utils.ApiResponse "Payment provider error"
+// @Router /api/orders/{id}/payments [post]
+func (h *PaymentHandler) PayOrder(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request PaymentRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+
+	var paid models.Payment
+	var free *models.Order
+	var providerErr error
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Orders.Lock(id); err != nil {
+			return err
+		}
+		order, err := tx.Orders.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if order.UserID == nil || *order.UserID != *userID {
+			return repository.ErrNotFound
+		}
+		if order.Status != models.OrderPending {
+			return errOrderNotPayable
+		}
+		// The provider only collects positive amounts, and there is
+		// nothing to collect
+		if order.Total.Sign() <= 0 {
+			if err := transitionOrder(tx, order, models.OrderPaid, "Nothing to pay", userID); err != nil {
+				return err
+			}
+			free, err = tx.Orders.FindByID(id)
+			return err
+		}
+
+		payments, err := tx.Payments.FindByOrder(id)
+		if err != nil {
+			return err
+		}
+		for _, existing := range payments {
+			if existing.Status == models.PaymentPending || existing.Status == models.PaymentSucceeded {
+				return errPaymentInProgress
+			}
+		}
+
+		intent, err := h.provider.CreateIntent(payment.IntentRequest{
+			OrderID:  order.ID,
+			Amount:   order.Total,
+			Currency: order.Currency,
+			Method:   request.Method,
+		})
+		if err != nil {
+			providerErr = err
+			return err
+		}
+		paid = models.Payment{
+			OrderID:  order.ID,
+			Provider: h.provider.Name(),
+			IntentID: intent.ID,
+			Method:   request.Method,
+			Amount:   order.Total,
+			Currency: order.Currency,
+			Status:   models.PaymentPending,
+		}
+		return tx.Payments.Create(&paid)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return orderNotFound(c)
+		case errors.Is(err, errOrderNotPayable), errors.Is(err, errPaymentInProgress):
+			return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Order cannot be paid",
+				Data:    err.Error(),
+			})
+		case providerErr != nil:
+			return providerError(c, err)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create payment",
+			Data:    err.Error(),
+		})
+	}
+	if free != nil {
+		return c.JSON(utils.ApiResponse{
+			Success: true,
+			Message: "Order paid successfully",
+			Data:    free,
+		})
+	}
+
+	// The payment is only captured once it is saved, so the webhook
+	// always finds it
+	if _, err := h.provider.Capture(paid.IntentID); err != nil {
+		// Nothing was charged; failing the payment lets the order be paid again
+		_ = h.repos.Payments.SetStatus(paid.ID, models.PaymentPending, models.PaymentFailed)
+		return providerError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Payment submitted successfully",
+		Data:    paid,
+	})
+}
+
+// GetOrderPayments - Handler for listing the payments of an order
+// @Summary Get order payments
+// @Description Retrieves the payments made for an order, oldest first. Users can only see the
+// @Description payments of their own orders; admins can see any.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {array} models.Payment
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Order not found"
+// @Router /api/orders/{id}/payments [get]
+func (h *PaymentHandler) GetOrderPayments(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	order, err := h.repos.Orders.FindByID(id)
+	if err != nil || !canSeeOrder(c, order, *userID) {
+		return orderNotFound(c)
+	}
+
+	payments, err := h.repos.Payments.FindByOrder(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve payments",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Payments retrieved successfully",
+		Data:    payments,
+	})
+}
+
+// HandleWebhook - Handler for the events of the payment provider
+// @Summary Receive a payment event
+// @Description Applies an event posted by the payment provider. The X-Payment-Signature header
+// @Description must carry a valid signature of the body. A succeeded payment moves its order to
+// @Description paid and a failed one to failed, putting its items back in stock. A payment that
+// @Description succeeds after its order was cancelled is refunded. Every event is applied at most
+// @Description once; deliveries of an event that was already applied are acknowledged and
+// @Description ignored.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "t=<unix time>,v1=<hex HMAC-SHA256 of time.body>"
+// @Param event body payment.Event true "Event"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 400 {object} utils.ApiResponse "Invalid payment event"
+// @Failure 401 {object} utils.ApiResponse "Invalid signature"
+// @Failure 404 {object} utils.ApiResponse "Payment not found"
+// @Router /api/payments/webhook [post]
+func (h *PaymentHandler) HandleWebhook(c *fiber.Ctx) error {
+	event, err := h.provider.ParseWebhook(c.Body(), c.Get(payment.SignatureHeader))
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		return c.Status(fiber.StatusUnauthorized).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Invalid signature",
+			Data:    nil,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Invalid payment event",
+			Data:    err.Error(),
+		})
+	}
+
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		paid, err := tx.Payments.FindByIntent(h.provider.Name(), event.IntentID)
+		if err != nil {
+			return err
+		}
+		err = tx.Payments.RecordEvent(&models.PaymentEvent{
+			Provider:  h.provider.Name(),
+			EventID:   event.ID,
+			Type:      event.Type,
+			PaymentID: &paid.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return applyPaymentEvent(tx, h.provider, paid, event.Type)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return c.JSON(utils.ApiResponse{
+				Success: true,
+				Message: "Payment event already processed",
+				Data:    nil,
+			})
+		case errors.Is(err, repository.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Payment not found",
+				Data:    nil,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to process payment event",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Payment event processed successfully",
+		Data:    nil,
+	})
+}
+
+// applyPaymentEvent settles a pending payment as the event reports and
+// moves its order on. Events for a payment that is already settled change
+// nothing. An order that is no longer pending keeps its state; if it was
+// paid for anyway, the money is given back.
+func applyPaymentEvent(tx repository.Repositories, provider payment.Provider, paid *models.Payment, eventType string) error {
+	var status, orderStatus string
+	switch eventType {
+	case payment.EventSucceeded:
+		status, orderStatus = models.PaymentSucceeded, models.OrderPaid
+	case payment.EventFailed:
+		status, orderStatus = models.PaymentFailed, models.OrderFailed
+	default:
+		return nil
+	}
+
+	err := tx.Payments.SetStatus(paid.ID, models.PaymentPending, status)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	paid.Status = status
+
+	if err := tx.Orders.Lock(paid.OrderID); err != nil {
+		return err
+	}
+	order, err := tx.Orders.FindByID(paid.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != models.OrderPending {
+		if status == models.PaymentSucceeded {
+			return refundPayment(tx, provider, *paid)
+		}
+		return nil
+	}
+	return transitionOrder(tx, order, orderStatus, fmt.Sprintf("Payment %s %s", paid.IntentID, status), nil)
+}
+
+// refundPayments gives back every succeeded payment of an order
+func refundPayments(tx repository.Repositories, provider payment.Provider, orderID uint) error {
+	payments, err := tx.Payments.FindByOrder(orderID)
+	if err != nil {
+		return err
+	}
+	for _, paid := range payments {
+		if paid.Status != models.PaymentSucceeded {
+			continue
+		}
+		if err := refundPayment(tx, provider, paid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refundPayment marks a succeeded payment refunded and has the provider
+// pay it back. The refund carries an idempotency key tied to the payment,
+// so when the transaction is rolled back after the provider paid and the
+// change is tried again, the provider does not pay a second time.
+func refundPayment(tx repository.Repositories, provider payment.Provider, paid models.Payment) error {
+	if err := tx.Payments.SetStatus(paid.ID, models.PaymentSucceeded, models.PaymentRefunded); err != nil {
+		return err
+	}
+	_, err := provider.Refund(paid.IntentID, paid.Amount, refundKey(paid))
+	return err
+}
+
+// refundKey returns the idempotency key of the refund of a payment
+func refundKey(paid models.Payment) string {
+	return fmt.Sprintf("refund_%d", paid.ID)
+}
+
+func providerError(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusBadGateway).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Payment provider error",
+		Data:    err.Error(),
+	})
+}
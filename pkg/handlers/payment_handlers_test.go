@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/payment"
+)
+
+// pay submits a payment for an order as user and returns the ID of its
+// intent
+func (a *testApp) pay(user string, orderID uint) string {
+	a.t.Helper()
+	response := a.do("POST", fmt.Sprintf("/api/orders/%d/payments", orderID), user, `{"method":"mock_card"}`)
+	a.expect(response, fiber.StatusAccepted)
+	var paid struct {
+		IntentID string `json:"intent_id"`
+	}
+	a.decode(response, &paid)
+	return paid.IntentID
+}
+
+// deliver posts event to the webhook with the given signature
+func (a *testApp) deliver(event payment.Event, signature func(payload []byte) string) testResponse {
+	a.t.Helper()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return a.do("POST", "/api/payments/webhook", "", string(payload), payment.SignatureHeader, signature(payload))
+}
+
+// signed signs a payload with the webhook secret of the test app
+func signed(payload []byte) string {
+	return payment.Sign(testWebhookSecret, payload, time.Now())
+}
+
+// orderStatus returns the state of an order
+func (a *testApp) orderStatus(orderID uint) string {
+	a.t.Helper()
+	response := a.do("GET", fmt.Sprintf("/api/orders/%d", orderID), "1 admin", "")
+	a.expect(response, fiber.StatusOK)
+	var order placedOrder
+	a.decode(response, &order)
+	return order.Status
+}
+
+func TestWebhookRejectsBadSignatures(t *testing.T) {
+	app := newTestApp(t)
+	lamp := app.createProduct("Lamp")
+	order := app.placeOrder("1", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}]}`, lamp))
+	event := payment.Event{ID: "evt_1", Type: payment.EventSucceeded, IntentID: app.pay("1", order.ID), OrderID: order.ID}
+
+	tests := []struct {
+		name      string
+		signature func(payload []byte) string
+	}{
+		{"missing", func([]byte) string { return "" }},
+		{"malformed", func([]byte) string { return "t=yesterday,v1=cafe" }},
+		{"other secret", func(payload []byte) string { return payment.Sign("whsec_other", payload, time.Now()) }},
+		{"other payload", func([]byte) string { return signed([]byte(`{}`)) }},
+		{"too old", func(payload []byte) string {
+			return payment.Sign(testWebhookSecret, payload, time.Now().Add(-payment.SignatureTolerance-time.Minute))
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app.t = t
+			app.expect(app.deliver(event, test.signature), fiber.StatusUnauthorized)
+			if status := app.orderStatus(order.ID); status != "pending" {
+				t.Fatalf("got order %s, want it still pending", status)
+			}
+		})
+	}
+	app.t = t
+
+	// None of the refused deliveries used up the event
+	app.expect(app.deliver(event, signed), fiber.StatusOK)
+	if status := app.orderStatus(order.ID); status != "paid" {
+		t.Fatalf("got order %s, want it paid", status)
+	}
+}
+
+func TestWebhookIgnoresDuplicateEvent(t *testing.T) {
+	app := newTestApp(t)
+	lamp := app.createProduct("Lamp")
+	order := app.placeOrder("1", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}]}`, lamp))
+	event := payment.Event{ID: "evt_1", Type: payment.EventSucceeded, IntentID: app.pay("1", order.ID), OrderID: order.ID}
+
+	response := app.deliver(event, signed)
+	app.expect(response, fiber.StatusOK)
+	if response.Message != "Payment event processed successfully" {
+		t.Fatalf("got %q delivering the event, want it processed", response.Message)
+	}
+
+	response = app.deliver(event, signed)
+	app.expect(response, fiber.StatusOK)
+	if response.Message != "Payment event already processed" {
+		t.Fatalf("got %q delivering the event again, want it ignored", response.Message)
+	}
+
+	if status := app.orderStatus(order.ID); status != "paid" {
+		t.Fatalf("got order %s, want it paid", status)
+	}
+	transitions, err := app.repos.Orders.FindTransitions(order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transitions) != 2 {
+		t.Fatalf("got %d transitions, want the order placed and paid once", len(transitions))
+	}
+}
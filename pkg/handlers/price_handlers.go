@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// PriceHandler serves the price history and scheduled price endpoints
+type PriceHandler struct {
+	repos repository.Repositories
+}
+
+// NewPriceHandler creates a PriceHandler backed by the given repositories
+func NewPriceHandler(repos repository.Repositories) *PriceHandler {
+	return &PriceHandler{repos: repos}
+}
+
+// ScheduledPriceRequest is the body of a new scheduled price
+type ScheduledPriceRequest struct {
+	Price       decimal.Decimal `json:"price" swaggertype:"string" example:"17.99"`
+	EffectiveAt time.Time       `json:"effective_at"`
+}
+
+// GetPriceHistory - Handler for listing the past prices of a product
+// @Summary Get price history
+// @Description Retrieves every price a product has had, in the order they took effect
+// @Tags Price
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.PriceChange
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/product/{id}/prices [get]
+func (h *PriceHandler) GetPriceHistory(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if _, err := h.repos.Products.FindByID(id); err != nil {
+		return productNotFound(c)
+	}
+
+	history, err := h.repos.PriceHistory.FindByProduct(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve price history",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Price history retrieved successfully",
+		Data:    history,
+	})
+}
+
+// GetEffectivePrice - Handler for getting the price of a product at a point in time
+// @Summary Get the effective price
+// @Description Retrieves the price a product had, has or is scheduled to have at the given
+// @Description time. Without at, the current price is returned.
+// @Tags Price
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param at query string false "RFC 3339 timestamp, e.g. 2025-01-01T00:00:00Z"
+// @Success 200 {object} pricing.Effective
+// @Failure 400 {object} utils.ApiResponse "Invalid time"
+// @Failure 404 {object} utils.ApiResponse "Product not found, or it had no price at that time"
+// @Router /api/product/{id}/price [get]
+func (h *PriceHandler) GetEffectivePrice(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	at := time.Now()
+	if raw := c.Query("at"); raw != "" {
+		at, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Invalid time, expected RFC 3339",
+				Data:    nil,
+			})
+		}
+	}
+
+	product, err := h.repos.Products.FindByID(id)
+	if err != nil {
+		return productNotFound(c)
+	}
+
+	effective, err := pricing.At(h.repos, product, at)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Product had no price at that time",
+				Data:    nil,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve price",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Price retrieved successfully",
+		Data:    effective,
+	})
+}
+
+// CreateScheduledPrice - Handler for scheduling a price change
+// @Summary Schedule a price
+// @Description Schedules a product to switch to price at effective_at. The change is applied
+// @Description automatically and recorded in the price history as of effective_at.
+// @Tags Price
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param schedule body ScheduledPriceRequest true "Scheduled price"
+// @Success 201 {object} models.ScheduledPrice
+// @Failure 400 {object} utils.ApiResponse "Price is negative or effective_at is not in the future"
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/product/{id}/scheduled-prices [post]
+func (h *PriceHandler) CreateScheduledPrice(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request ScheduledPriceRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if request.Price.IsNegative() {
+		return invalidPrice(c)
+	}
+	if !request.EffectiveAt.After(time.Now()) {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "effective_at must be in the future",
+			Data:    nil,
+		})
+	}
+
+	if _, err := h.repos.Products.FindByID(id); err != nil {
+		return productNotFound(c)
+	}
+
+	schedule := models.ScheduledPrice{
+		ProductID:   id,
+		Price:       request.Price,
+		EffectiveAt: request.EffectiveAt,
+		Status:      models.SchedulePending,
+		ActorID:     currentUserID(c),
+	}
+	if err := h.repos.Schedules.Create(&schedule); err != nil {
+		if errors.Is(err, repository.ErrInvalidReference) {
+			return productNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to schedule price",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Price scheduled successfully",
+		Data:    schedule,
+	})
+}
+
+// GetScheduledPrices - Handler for listing the scheduled prices of a product
+// @Summary Get scheduled prices
+// @Description Retrieves the scheduled prices of a product, pending or not, earliest first
+// @Tags Price
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param status query string false "Only return schedules in this state: pending, applied or cancelled"
+// @Success 200 {array} models.ScheduledPrice
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/product/{id}/scheduled-prices [get]
+func (h *PriceHandler) GetScheduledPrices(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if _, err := h.repos.Products.FindByID(id); err != nil {
+		return productNotFound(c)
+	}
+
+	schedules, err := h.repos.Schedules.FindByProduct(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve scheduled prices",
+			Data:    err.Error(),
+		})
+	}
+
+	if status := strings.TrimSpace(c.Query("status")); status != "" {
+		matching := []models.ScheduledPrice{}
+		for _, schedule := range schedules {
+			if schedule.Status == status {
+				matching = append(matching, schedule)
+			}
+		}
+		schedules = matching
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Scheduled prices retrieved successfully",
+		Data:    schedules,
+	})
+}
+
+// CancelScheduledPrice - Handler for cancelling a scheduled price
+// @Summary Cancel a scheduled price
+// @Description Cancels a pending scheduled price so it is never applied
+// @Tags Price
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param scheduleId path int true "Scheduled price ID"
+// @Success 200 {object} models.ScheduledPrice
+// @Failure 404 {object} utils.ApiResponse "Scheduled price not found"
+// @Failure 409 {object} utils.ApiResponse "Scheduled price is no longer pending"
+// @Router /api/product/{id}/scheduled-prices/{scheduleId} [delete]
+func (h *PriceHandler) CancelScheduledPrice(c *fiber.Ctx) error {
+	productID, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+	id, err := paramUint(c, "scheduleId")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	schedule, err := h.repos.Schedules.FindByID(id)
+	if err != nil || schedule.ProductID != productID {
+		return scheduleNotFound(c)
+	}
+
+	if err := h.repos.Schedules.SetStatus(id, models.SchedulePending, models.ScheduleCancelled); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return scheduleNotFound(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Scheduled price is no longer pending",
+				Data:    nil,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to cancel scheduled price",
+			Data:    err.Error(),
+		})
+	}
+
+	schedule, err = h.repos.Schedules.FindByID(id)
+	if err != nil {
+		return scheduleNotFound(c)
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Scheduled price cancelled successfully",
+		Data:    schedule,
+	})
+}
+
+func scheduleNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Scheduled price not found",
+		Data:    nil,
+	})
+}
@@ -1,22 +1,46 @@
 package handlers
 
 import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
-	"github.com/santoadji21/santoadji21-go-fiber-product-api/internal/db"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
 )
 
+// ProductHandler serves the product endpoints
+type ProductHandler struct {
+	repos    repository.Repositories
+	currency config.CurrencyConfig
+	tax      config.TaxConfig
+}
+
+// NewProductHandler creates a ProductHandler backed by the given repositories
+func NewProductHandler(repos repository.Repositories, currencyCfg config.CurrencyConfig, taxCfg config.TaxConfig) *ProductHandler {
+	return &ProductHandler{repos: repos, currency: currencyCfg, tax: taxCfg}
+}
+
 // CreateProduct - Handler for creating a new product
 // @Summary Create a new product
-// @Description Create a new product with the given details
+// @Description Create a new product with the given details. A non-zero qty is recorded
+// @Description in the stock ledger as the initial receipt.
 // @Tags Product
 // @Accept  json
 // @Produce  json
 // @Param   products body     models.Product   true  "Product Info"
 // @Success 201 {object}  models.Product
+// @Failure 400 {object} utils.ApiResponse "Category or tax class does not exist, or qty, price, weight or a dimension is negative"
 // @Router /api/product [post]
-func CreateProduct(c *fiber.Ctx) error {
+func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 	var product models.Product
 	if err := c.BodyParser(&product); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
@@ -26,25 +50,76 @@ func CreateProduct(c *fiber.Ctx) error {
 		})
 	}
 
+	product.Model = models.Model{}
+	product.Category = nil
+	product.Variants = nil
+	product.Available = nil
+	product.Locations = nil
+	product.EffectivePrice = nil
+	product.Discounts = nil
+	product.Tax = nil
+	product.RatingAverage = decimal.Zero
+	product.RatingCount = 0
+
 	// Check if a product with the same name already exists
-	var existingProduct models.Product
-	result := db.GetDB().Where("name = ?", product.Name).First(&existingProduct)
-	if result.Error == nil {
+	if _, err := h.repos.Products.FindByName(product.Name); err == nil {
 		// A product with the same name was found
-		return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
-			Success: false,
-			Message: "Product name already exists",
-			Data:    nil,
-		})
+		return productNameConflict(c)
+	}
+
+	// The category and tax class, when given, must exist
+	if !h.categoryExists(product.CategoryID) {
+		return invalidCategory(c)
+	}
+	if !taxClassExists(h.repos, product.TaxClassID) {
+		return invalidTaxClass(c)
+	}
+
+	// Prices are in the base currency unless another one is given
+	product.Currency = currency.Normalize(product.Currency)
+	if product.Currency == "" {
+		product.Currency = h.currency.Base
+	}
+	if !h.currencySupported(product.Currency) {
+		return invalidCurrency(c)
 	}
 
-	// No existing product found, proceed to create a new one
-	result = db.GetDB().Create(&product)
-	if result.Error != nil {
+	if product.Qty < 0 {
+		return invalidQuantity(c)
+	}
+	if product.Price.IsNegative() {
+		return invalidPrice(c)
+	}
+	if !validDimensions(&product) {
+		return invalidDimensions(c)
+	}
+
+	// No existing product found, proceed to create a new one. Its opening
+	// stock is booked as a receipt so the ledger adds up to Qty.
+	err := h.repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Products.Create(&product); err != nil {
+			return err
+		}
+		if err := pricing.Record(tx, &product, product.CreatedAt, models.PriceInitial, currentUserID(c)); err != nil {
+			return err
+		}
+		if product.Qty == 0 {
+			return nil
+		}
+		_, err := recordMovement(tx, product.ID, nil, models.StockReceipt, product.Qty, product.Qty, "Initial stock", currentUserID(c))
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return productNameConflict(c)
+		case errors.Is(err, repository.ErrInvalidReference):
+			return invalidCategory(c)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Failed to create product",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
 		})
 	}
 
@@ -58,20 +133,136 @@ func CreateProduct(c *fiber.Ctx) error {
 // GetAllProducts - Handler for getting all products
 // GetAllProducts retrieves all products
 // @Summary Get all products
-// @Description Retrieves a list of all products
+// @Description Retrieves a list of all products with their stock on hand (qty), its
+// @Description breakdown by warehouse (locations) and what is available after
+// @Description reservations, optionally only those in a category
+// @Description and, with descendants=true, in any of its subcategories. effective_price
+// @Description is the price after the discount rules listed in discounts; tax breaks it
+// @Description down into net, tax and gross for region (the configured one by default).
 // @Tags Product
 // @Accept json
 // @Produce json
+// @Param include query string false "Set to \"category\" to embed each product's category"
+// @Param category query int false "Only return products in this category"
+// @Param descendants query bool false "Also return products in subcategories of category"
+// @Param currency query string false "Convert prices to this ISO 4217 currency"
+// @Param region query string false "Tax region the prices are taxed for"
+// @Param sort query string false "Set to \"rating\" to list the best rated products first"
 // @Success 200 {array} models.Product
+// @Failure 400 {object} utils.ApiResponse "Category, currency, tax region or sort order does not exist"
 // @Router /api/products [get]
-func GetAllProducts(c *fiber.Ctx) error {
-	var products []models.Product
-	result := db.GetDB().Find(&products)
-	if result.Error != nil {
+func (h *ProductHandler) GetAllProducts(c *fiber.Ctx) error {
+	target, converter, err := h.priceCurrency(c)
+	if err != nil {
+		if errors.Is(err, currency.ErrUnknown) {
+			return invalidCurrency(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve exchange rates",
+			Data:    err.Error(),
+		})
+	}
+	region, ok := taxRegion(c, h.repos, h.tax)
+	if !ok {
+		return invalidRegion(c)
+	}
+	sortByRating := c.Query("sort") == "rating"
+	if c.Query("sort") != "" && !sortByRating {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Unknown sort order",
+			Data:    "sort must be rating",
+		})
+	}
+
+	var filter repository.ProductFilter
+	if c.Query("category") != "" {
+		categoryID := c.QueryInt("category")
+		if categoryID <= 0 {
+			return invalidCategory(c)
+		}
+
+		filter.CategoryIDs, err = h.categoryFilter(uint(categoryID), c.QueryBool("descendants"))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return invalidCategory(c)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Failed to retrieve categories",
+				Data:    err.Error(),
+			})
+		}
+	}
+
+	products, err := h.repos.Products.FindAll(filter)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Failed to retrieve products",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
+		})
+	}
+	if sortByRating {
+		// Best average first; among equal averages the one rated more
+		// often wins, then the oldest product
+		slices.SortFunc(products, func(a, b models.Product) int {
+			if byAverage := b.RatingAverage.Cmp(a.RatingAverage); byAverage != 0 {
+				return byAverage
+			}
+			if byCount := cmp.Compare(b.RatingCount, a.RatingCount); byCount != 0 {
+				return byCount
+			}
+			return cmp.Compare(a.ID, b.ID)
+		})
+	}
+
+	if includes(c, "category") {
+		if err := h.attachCategories(products); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Failed to retrieve categories",
+				Data:    err.Error(),
+			})
+		}
+	}
+
+	if err := fillAvailable(h.repos, products); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve reservations",
+			Data:    err.Error(),
+		})
+	}
+	if err := fillLocations(h.repos, products); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve stock levels",
+			Data:    err.Error(),
+		})
+	}
+	if target != "" {
+		if err := convertPrices(h.repos, converter, products, target); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Failed to convert prices",
+				Data:    err.Error(),
+			})
+		}
+	}
+	if err := fillDiscounts(h.repos, h.currency, products); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to apply discount rules",
+			Data:    err.Error(),
+		})
+	}
+	if err := fillTaxes(h.repos, h.currency, h.tax, products, region); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to apply tax rates",
+			Data:    err.Error(),
 		})
 	}
 
@@ -85,26 +276,113 @@ func GetAllProducts(c *fiber.Ctx) error {
 // GetProduct - Handler for getting a product's details
 // GetProduct retrieves a single product by ID
 // @Summary Get a product
-// @Description Retrieves a product by its ID
+// @Description Retrieves a product by its ID, together with its variants. qty is the stock
+// @Description on hand, locations breaks it down by warehouse and available is what is
+// @Description left of it after active reservations. effective_price is the price after
+// @Description the discount rules listed in discounts; tax breaks it down into net, tax
+// @Description and gross for region (the configured one by default).
 // @Tags Product
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param include query string false "Set to \"category\" to embed the product's category"
+// @Param currency query string false "Convert prices to this ISO 4217 currency"
+// @Param region query string false "Tax region the prices are taxed for"
 // @Success 200 {object} models.Product
+// @Header 200 {string} ETag "Version of the product"
+// @Failure 400 {object} utils.ApiResponse "Unknown currency or tax region"
 // @Failure 404 {object} utils.ApiResponse "Product not found"
 // @Router /api/product/{id} [get]
-func GetProduct(c *fiber.Ctx) error {
-	id := c.Params("id")
-	var product models.Product
-	result := db.GetDB().First(&product, id)
-	if result.Error != nil {
-		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	target, converter, err := h.priceCurrency(c)
+	if err != nil {
+		if errors.Is(err, currency.ErrUnknown) {
+			return invalidCurrency(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
-			Message: "Product not found",
-			Data:    nil,
+			Message: "Failed to retrieve exchange rates",
+			Data:    err.Error(),
 		})
 	}
+	region, ok := taxRegion(c, h.repos, h.tax)
+	if !ok {
+		return invalidRegion(c)
+	}
 
+	product, err := h.repos.Products.FindByID(id)
+	if err != nil {
+		return productNotFound(c)
+	}
+
+	if includes(c, "category") {
+		products := []models.Product{*product}
+		if err := h.attachCategories(products); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Failed to retrieve categories",
+				Data:    err.Error(),
+			})
+		}
+		product = &products[0]
+	}
+
+	variants, err := h.repos.Variants.FindByProduct(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve variants",
+			Data:    err.Error(),
+		})
+	}
+	product.Variants = variants
+
+	products := []models.Product{*product}
+	if err := fillAvailable(h.repos, products); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve reservations",
+			Data:    err.Error(),
+		})
+	}
+	if err := fillLocations(h.repos, products); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve stock levels",
+			Data:    err.Error(),
+		})
+	}
+	if target != "" {
+		if err := convertPrices(h.repos, converter, products, target); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Failed to convert prices",
+				Data:    err.Error(),
+			})
+		}
+	}
+	if err := fillDiscounts(h.repos, h.currency, products); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to apply discount rules",
+			Data:    err.Error(),
+		})
+	}
+	if err := fillTaxes(h.repos, h.currency, h.tax, products, region); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to apply tax rates",
+			Data:    err.Error(),
+		})
+	}
+	product = &products[0]
+
+	setETag(c, product.Version)
 	return c.JSON(utils.ApiResponse{
 		Success: true,
 		Message: "Product retrieved successfully",
@@ -115,36 +393,147 @@ func GetProduct(c *fiber.Ctx) error {
 // UpdateProduct - Handler for updating a product's details
 // UpdateProduct updates a product's details
 // @Summary Update a product
-// @Description Updates a product's details by its ID
+// @Description Updates a product's details by its ID. A change of qty is recorded in the
+// @Description stock ledger as an adjustment; qty cannot drop below the reserved stock.
+// @Description A change of price or currency is recorded in the price history.
 // @Tags Product
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Param product body models.Product true "Product update data"
 // @Success 200 {object} models.Product
+// @Failure 400 {object} utils.ApiResponse "Category or tax class does not exist, or qty, price, weight or a dimension is negative"
 // @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Failure 412 {object} utils.ApiResponse "Product was modified since the given ETag"
 // @Router /api/product/{id} [patch] update product
-func UpdateProduct(c *fiber.Ctx) error {
-	id := c.Params("id")
-	var product models.Product
-	if err := db.GetDB().First(&product, id).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	// Scheduled prices that fell due take effect first, so applying them
+	// later cannot overwrite this change
+	if _, err := pricing.ApplyDue(h.repos, id, time.Now()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
-			Message: "Product not found",
-			Data:    nil,
+			Message: "Failed to apply scheduled prices",
+			Data:    err.Error(),
 		})
 	}
 
-	if err := c.BodyParser(&product); err != nil {
+	product, err := h.repos.Products.FindByID(id)
+	if err != nil {
+		return productNotFound(c)
+	}
+	if expected != 0 && product.Version != expected {
+		return preconditionFailed(c)
+	}
+	model := product.Model
+	qty := product.Qty
+	rating, ratings := product.RatingAverage, product.RatingCount
+	price, priceCurrency := product.Price, product.Currency
+
+	if err := c.BodyParser(product); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Error parsing JSON",
 			Data:    err.Error(),
 		})
 	}
+	product.Model = model
+	product.RatingAverage, product.RatingCount = rating, ratings
+	product.Category = nil
+	product.Variants = nil
+	product.Available = nil
+	product.Locations = nil
+	product.EffectivePrice = nil
+	product.Discounts = nil
+	product.Tax = nil
+	product.Currency = currency.Normalize(product.Currency)
+
+	if !h.categoryExists(product.CategoryID) {
+		return invalidCategory(c)
+	}
+	if !taxClassExists(h.repos, product.TaxClassID) {
+		return invalidTaxClass(c)
+	}
+	if !h.currencySupported(product.Currency) {
+		return invalidCurrency(c)
+	}
+	if product.Qty < 0 {
+		return invalidQuantity(c)
+	}
+	if product.Price.IsNegative() {
+		return invalidPrice(c)
+	}
+	if !validDimensions(product) {
+		return invalidDimensions(c)
+	}
 
-	db.GetDB().Save(&product)
+	// The version check guarantees qty was still the stock on hand, so the
+	// difference is exactly what the ledger has to record. Stock held by
+	// reservations cannot be taken away.
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if product.Qty < qty {
+			if err := tx.Products.Lock(id); err != nil {
+				return err
+			}
+			available, err := availableQty(tx, id)
+			if err != nil {
+				return err
+			}
+			if available < qty-product.Qty {
+				return repository.ErrInsufficientStock
+			}
+		}
 
+		if err := tx.Products.Update(product); err != nil {
+			return err
+		}
+		if !product.Price.Equal(price) || product.Currency != priceCurrency {
+			if err := pricing.Record(tx, product, time.Now(), models.PriceUpdate, currentUserID(c)); err != nil {
+				return err
+			}
+		}
+		if product.Qty == qty {
+			return nil
+		}
+		if product.Qty < qty {
+			if err := drawDownLevels(tx, id, product.Qty); err != nil {
+				return err
+			}
+		}
+		_, err := recordMovement(tx, id, nil, models.StockAdjustment, product.Qty-qty, product.Qty, "Quantity set by product update", currentUserID(c))
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return productNameConflict(c)
+		case errors.Is(err, repository.ErrInvalidReference):
+			return invalidCategory(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return versionConflict(c)
+		case errors.Is(err, repository.ErrNotFound):
+			return productNotFound(c)
+		case errors.Is(err, repository.ErrInsufficientStock):
+			return insufficientStock(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update product",
+			Data:    err.Error(),
+		})
+	}
+
+	setETag(c, product.Version)
 	return c.JSON(utils.ApiResponse{
 		Success: true,
 		Message: "Product updated successfully",
@@ -153,24 +542,40 @@ func UpdateProduct(c *fiber.Ctx) error {
 }
 
 // DeleteProduct - Handler for deleting a product
-// DeleteProduct deletes a product
+// DeleteProduct moves a product to the trash
 // @Summary Delete a product
-// @Description Deletes a product by its ID
+// @Description Moves a product to the trash by its ID; it can be restored until it is purged
 // @Tags Product
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 200 {object} utils.ApiResponse
 // @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Failure 412 {object} utils.ApiResponse "Product was modified since the given ETag"
 // @Router /api/product/{id} [delete]
-func DeleteProduct(c *fiber.Ctx) error {
-	id := c.Params("id")
-	result := db.GetDB().Delete(&models.Product{}, id)
-	if result.Error != nil {
+func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	if err := h.repos.Products.Delete(id, expected); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return productNotFound(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return preconditionFailed(c)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Failed to delete product",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
 		})
 	}
 
@@ -180,3 +585,214 @@ func DeleteProduct(c *fiber.Ctx) error {
 		Data:    nil,
 	})
 }
+
+// GetTrashedProducts - Handler for listing deleted products
+// @Summary List trashed products
+// @Description Retrieves the products that were deleted and can still be restored
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Product
+// @Router /api/products/trash [get]
+func (h *ProductHandler) GetTrashedProducts(c *fiber.Ctx) error {
+	products, err := h.repos.Products.FindDeleted()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve trashed products",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Trashed products retrieved successfully",
+		Data:    products,
+	})
+}
+
+// RestoreProduct - Handler for restoring a deleted product
+// @Summary Restore a product
+// @Description Moves a deleted product out of the trash by its ID. If its category
+// @Description was deleted in the meantime, the product is restored without a category.
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} models.Product
+// @Failure 404 {object} utils.ApiResponse "Product not found in trash"
+// @Router /api/product/{id}/restore [post]
+func (h *ProductHandler) RestoreProduct(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var product *models.Product
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Products.Restore(id); err != nil {
+			return err
+		}
+
+		restored, err := tx.Products.FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		// Detach the product from a category that has been deleted since
+		if restored.CategoryID != nil {
+			if _, err := tx.Categories.FindByID(*restored.CategoryID); errors.Is(err, repository.ErrNotFound) {
+				restored.CategoryID = nil
+				if err := tx.Products.Update(restored); err != nil {
+					return err
+				}
+			}
+		}
+
+		product = restored
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Product not found in trash",
+				Data:    nil,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to restore product",
+			Data:    err.Error(),
+		})
+	}
+
+	setETag(c, product.Version)
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Product restored successfully",
+		Data:    product,
+	})
+}
+
+func productNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Product not found",
+		Data:    nil,
+	})
+}
+
+func productNameConflict(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Product name already exists",
+		Data:    nil,
+	})
+}
+
+func invalidPrice(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Price cannot be negative",
+		Data:    nil,
+	})
+}
+
+func invalidDimensions(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Weight and dimensions cannot be negative",
+		Data:    nil,
+	})
+}
+
+func invalidCategory(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Category does not exist",
+		Data:    nil,
+	})
+}
+
+// validDimensions reports whether none of the weight and dimensions of
+// product is negative
+func validDimensions(product *models.Product) bool {
+	for _, size := range []decimal.Decimal{product.Weight, product.Length, product.Width, product.Height} {
+		if size.IsNegative() {
+			return false
+		}
+	}
+	return true
+}
+
+// categoryExists reports whether a product may point at the category.
+// Products without a category are always allowed.
+func (h *ProductHandler) categoryExists(categoryID *uint) bool {
+	if categoryID == nil {
+		return true
+	}
+	_, err := h.repos.Categories.FindByID(*categoryID)
+	return err == nil
+}
+
+// priceCurrency reads the currency query parameter. The code is empty when
+// prices should stay in each product's own currency.
+func (h *ProductHandler) priceCurrency(c *fiber.Ctx) (string, currency.Converter, error) {
+	target := currency.Normalize(strings.Clone(c.Query("currency")))
+	if target == "" {
+		return "", currency.Converter{}, nil
+	}
+
+	converter, err := loadConverter(h.repos, h.currency)
+	if err != nil {
+		return "", converter, err
+	}
+	if !converter.Supports(target) {
+		return "", converter, currency.ErrUnknown
+	}
+	return target, converter, nil
+}
+
+// currencySupported reports whether prices can be given in the currency
+func (h *ProductHandler) currencySupported(code string) bool {
+	if code == h.currency.Base {
+		return true
+	}
+	_, err := h.repos.Currencies.FindRate(code)
+	return err == nil
+}
+
+// categoryFilter returns the categories a product listing is limited to
+func (h *ProductHandler) categoryFilter(categoryID uint, descendants bool) ([]uint, error) {
+	if descendants {
+		return h.repos.Categories.SubtreeIDs(categoryID)
+	}
+	if _, err := h.repos.Categories.FindByID(categoryID); err != nil {
+		return nil, err
+	}
+	return []uint{categoryID}, nil
+}
+
+// attachCategories fills in the Category of every product in place
+func (h *ProductHandler) attachCategories(products []models.Product) error {
+	categories, err := h.repos.Categories.FindAll()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uint]models.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	for i := range products {
+		if products[i].CategoryID == nil {
+			continue
+		}
+		if category, ok := byID[*products[i].CategoryID]; ok {
+			products[i].Category = &category
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// createProduct creates a product named name and returns its ID
+func (a *testApp) createProduct(name string) uint {
+	a.t.Helper()
+	response := a.do("POST", "/api/product", "1", `{"name":"`+name+`","qty":5,"price":"19.99","currency":"USD"}`)
+	a.expect(response, fiber.StatusCreated)
+	var product struct {
+		ID uint `json:"id"`
+	}
+	a.decode(response, &product)
+	return product.ID
+}
+
+func TestCreateProductDuplicateName(t *testing.T) {
+	app := newTestApp(t)
+	app.createProduct("Lamp")
+
+	response := app.do("POST", "/api/product", "1", `{"name":"Lamp","qty":1,"price":"5.00","currency":"USD"}`)
+	app.expect(response, fiber.StatusConflict)
+}
+
+func TestCreateProductIgnoresServerFields(t *testing.T) {
+	app := newTestApp(t)
+	response := app.do("POST", "/api/product", "1", `{"id":42,"version":7,"deleted_at":"2000-01-01T00:00:00Z","rating_average":"5.00","rating_count":3,"name":"Lamp","qty":5,"price":"19.99","currency":"USD"}`)
+	app.expect(response, fiber.StatusCreated)
+	var product struct {
+		ID            uint   `json:"id"`
+		Version       uint   `json:"version"`
+		RatingAverage string `json:"rating_average"`
+		RatingCount   int    `json:"rating_count"`
+	}
+	app.decode(response, &product)
+	if product.ID == 42 || product.Version != 1 || product.RatingAverage != "0" || product.RatingCount != 0 {
+		t.Fatalf("got product %+v, want a new ID at version 1 without ratings", product)
+	}
+
+	app.expect(app.do("GET", fmt.Sprintf("/api/product/%d", product.ID), "", ""), fiber.StatusOK)
+	var trash []struct{}
+	app.decode(app.do("GET", "/api/products/trash", "1", ""), &trash)
+	if len(trash) != 0 {
+		t.Fatalf("got %d trashed products, want the new product outside the trash", len(trash))
+	}
+}
+
+func TestUpdateProductDuplicateName(t *testing.T) {
+	app := newTestApp(t)
+	app.createProduct("Lamp")
+	desk := app.createProduct("Desk")
+
+	path := fmt.Sprintf("/api/product/%d", desk)
+	app.expect(app.do("PATCH", path, "1", `{"name":"Lamp"}`), fiber.StatusConflict)
+
+	var product struct {
+		Name    string `json:"name"`
+		Version uint   `json:"version"`
+	}
+	app.decode(app.do("GET", path, "", ""), &product)
+	if product.Name != "Desk" || product.Version != 1 {
+		t.Fatalf("got %q at version %d, want the product unchanged", product.Name, product.Version)
+	}
+}
+
+func TestUpdateProductIfMatch(t *testing.T) {
+	app := newTestApp(t)
+	path := fmt.Sprintf("/api/product/%d", app.createProduct("Lamp"))
+
+	response := app.do("PATCH", path, "1", `{"qty":6}`, fiber.HeaderIfMatch, `"1"`)
+	app.expect(response, fiber.StatusOK)
+	if response.ETag != `"2"` {
+		t.Fatalf("got ETag %s after the update, want \"2\"", response.ETag)
+	}
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+	}{
+		{"stale version", `"1"`, fiber.StatusPreconditionFailed},
+		{"unknown version", `"9"`, fiber.StatusPreconditionFailed},
+		{"weak tag", `W/"2"`, fiber.StatusPreconditionFailed},
+		{"several tags", `"1", "2"`, fiber.StatusPreconditionFailed},
+		{"unquoted", `2`, fiber.StatusPreconditionFailed},
+		{"zero", `"0"`, fiber.StatusPreconditionFailed},
+		{"current version", `"2"`, fiber.StatusOK},
+		{"any version", `*`, fiber.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app.t = t
+			app.expect(app.do("PATCH", path, "1", `{"qty":7}`, fiber.HeaderIfMatch, test.ifMatch), test.status)
+		})
+	}
+	app.t = t
+
+	var product struct {
+		Qty     int  `json:"qty"`
+		Version uint `json:"version"`
+	}
+	response = app.do("GET", path, "", "")
+	app.decode(response, &product)
+	if product.Qty != 7 || product.Version != 4 || response.ETag != `"4"` {
+		t.Fatalf("got qty %d at version %d with ETag %s, want qty 7 at version 4", product.Qty, product.Version, response.ETag)
+	}
+}
+
+func TestDeleteProductIfMatch(t *testing.T) {
+	app := newTestApp(t)
+	path := fmt.Sprintf("/api/product/%d", app.createProduct("Lamp"))
+	app.expect(app.do("PATCH", path, "1", `{"qty":6}`), fiber.StatusOK)
+
+	app.expect(app.do("DELETE", path, "1", "", fiber.HeaderIfMatch, `"1"`), fiber.StatusPreconditionFailed)
+	app.expect(app.do("GET", path, "", ""), fiber.StatusOK)
+	app.expect(app.do("DELETE", path, "1", "", fiber.HeaderIfMatch, `"2"`), fiber.StatusOK)
+}
+
+func TestDeleteProductMovesItToTrash(t *testing.T) {
+	app := newTestApp(t)
+	id := app.createProduct("Lamp")
+	app.createProduct("Desk")
+	path := fmt.Sprintf("/api/product/%d", id)
+
+	app.expect(app.do("DELETE", path, "1", ""), fiber.StatusOK)
+	app.expect(app.do("GET", path, "", ""), fiber.StatusNotFound)
+	app.expect(app.do("PATCH", path, "1", `{"qty":1}`), fiber.StatusNotFound)
+	app.expect(app.do("DELETE", path, "1", ""), fiber.StatusNotFound)
+
+	var products []struct {
+		Name string `json:"name"`
+	}
+	app.decode(app.do("GET", "/api/products", "", ""), &products)
+	if len(products) != 1 || products[0].Name != "Desk" {
+		t.Fatalf("got products %v, want only Desk", products)
+	}
+	app.decode(app.do("GET", "/api/products/trash", "1", ""), &products)
+	if len(products) != 1 || products[0].Name != "Lamp" {
+		t.Fatalf("got trash %v, want only Lamp", products)
+	}
+
+	// The name stays taken while the product is in the trash
+	app.expect(app.do("POST", "/api/product", "1", `{"name":"Lamp","qty":1,"price":"5.00","currency":"USD"}`), fiber.StatusConflict)
+
+	response := app.do("POST", path+"/restore", "1", "")
+	app.expect(response, fiber.StatusOK)
+	if response.ETag == "" {
+		t.Fatal("restored product has no ETag")
+	}
+	app.expect(app.do("GET", path, "", ""), fiber.StatusOK)
+	app.expect(app.do("POST", path+"/restore", "1", ""), fiber.StatusNotFound)
+
+	app.decode(app.do("GET", "/api/products/trash", "1", ""), &products)
+	if len(products) != 0 {
+		t.Fatalf("got trash %v after the restore, want it empty", products)
+	}
+}
+
+func TestRestoreProductDetachesDeletedCategory(t *testing.T) {
+	app := newTestApp(t)
+	response := app.do("POST", "/api/category", "1", `{"name":"Lighting"}`)
+	app.expect(response, fiber.StatusCreated)
+	var category struct {
+		ID uint `json:"id"`
+	}
+	app.decode(response, &category)
+
+	response = app.do("POST", "/api/product", "1", fmt.Sprintf(`{"name":"Lamp","qty":5,"price":"19.99","currency":"USD","category_id":%d}`, category.ID))
+	app.expect(response, fiber.StatusCreated)
+	var product struct {
+		ID         uint  `json:"id"`
+		CategoryID *uint `json:"category_id"`
+	}
+	app.decode(response, &product)
+	path := fmt.Sprintf("/api/product/%d", product.ID)
+
+	app.expect(app.do("DELETE", path, "1", ""), fiber.StatusOK)
+	app.expect(app.do("DELETE", fmt.Sprintf("/api/category/%d", category.ID), "1", ""), fiber.StatusOK)
+
+	response = app.do("POST", path+"/restore", "1", "")
+	app.expect(response, fiber.StatusOK)
+	app.decode(response, &product)
+	if product.CategoryID != nil {
+		t.Fatalf("got category %d, want the product restored without one", *product.CategoryID)
+	}
+}
+
+func TestUpdateProductKeepsServerFields(t *testing.T) {
+	app := newTestApp(t)
+	id := app.createProduct("Lamp")
+	path := fmt.Sprintf("/api/product/%d", id)
+
+	response := app.do("PATCH", path, "1", `{"id":42,"version":9,"created_at":"2000-01-01T00:00:00Z","deleted_at":"2000-01-01T00:00:00Z","qty":6}`)
+	app.expect(response, fiber.StatusOK)
+	var product struct {
+		ID        uint      `json:"id"`
+		Qty       int       `json:"qty"`
+		Version   uint      `json:"version"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	app.decode(response, &product)
+	if product.ID != id || product.Qty != 6 || product.Version != 2 {
+		t.Fatalf("got product %d with qty %d at version %d, want %d with qty 6 at version 2", product.ID, product.Qty, product.Version, id)
+	}
+
+	app.decode(app.do("GET", path, "", ""), &product)
+	if product.CreatedAt.Year() == 2000 {
+		t.Fatal("the update changed when the product was created")
+	}
+	var trash []struct{}
+	app.decode(app.do("GET", "/api/products/trash", "1", ""), &trash)
+	if len(trash) != 0 {
+		t.Fatalf("got %d trashed products, want the update not to trash any", len(trash))
+	}
+}
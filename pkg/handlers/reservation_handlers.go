@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/middlewares"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+var (
+	errReservationNotActive = errors.New("reservation is not active")
+	errReservationExpired   = errors.New("reservation has expired")
+)
+
+// ReservationHandler serves the stock reservation endpoints
+type ReservationHandler struct {
+	repos repository.Repositories
+	cfg   config.ReservationConfig
+}
+
+// NewReservationHandler creates a ReservationHandler backed by the given repositories
+func NewReservationHandler(repos repository.Repositories, cfg config.ReservationConfig) *ReservationHandler {
+	return &ReservationHandler{repos: repos, cfg: cfg}
+}
+
+// ReservationRequest is the body of a new reservation. A zero TTLSeconds
+// uses the configured default.
+type ReservationRequest struct {
+	Quantity   int `json:"quantity"`
+	TTLSeconds int `json:"ttl_seconds"`
+}
+
+// CreateReservation - Handler for holding stock of a product
+// @Summary Reserve stock
+// @Description Holds quantity units of a product for ttl_seconds without taking them off
+// @Description the stock on hand. Only available units, those not already held, can be reserved.
+// @Tags Reservation
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reservation body ReservationRequest true "Reservation"
+// @Success 201 {object} models.Reservation
+// @Failure 400 {object} utils.ApiResponse "Invalid reservation"
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Failure 409 {object} utils.ApiResponse "Insufficient stock"
+// @Router /api/product/{id}/reservations [post]
+func (h *ReservationHandler) CreateReservation(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request ReservationRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+
+	ttl := time.Duration(request.TTLSeconds) * time.Second
+	if request.TTLSeconds == 0 {
+		ttl = h.cfg.DefaultTTL
+	}
+	switch {
+	case request.Quantity <= 0:
+		return invalidReservation(c, "quantity must be positive")
+	case ttl <= 0 || ttl > h.cfg.MaxTTL:
+		return invalidReservation(c, fmt.Sprintf("ttl_seconds must be between 1 and %d", int(h.cfg.MaxTTL.Seconds())))
+	}
+
+	reservation := models.Reservation{
+		ProductID: id,
+		UserID:    currentUserID(c),
+		Quantity:  request.Quantity,
+		Status:    models.ReservationActive,
+	}
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Products.Lock(id); err != nil {
+			return err
+		}
+		available, err := availableQty(tx, id)
+		if err != nil {
+			return err
+		}
+		if available < request.Quantity {
+			return repository.ErrInsufficientStock
+		}
+
+		reservation.ExpiresAt = time.Now().Add(ttl)
+		return tx.Reservations.Create(&reservation)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return productNotFound(c)
+		case errors.Is(err, repository.ErrInsufficientStock):
+			return insufficientStock(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to reserve stock",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Stock reserved successfully",
+		Data:    reservation,
+	})
+}
+
+// GetReservation - Handler for getting a reservation
+// @Summary Get a reservation
+// @Description Retrieves a stock reservation of the current user by its ID. Admins can
+// @Description retrieve any reservation.
+// @Tags Reservation
+// @Accept json
+// @Produce json
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} models.Reservation
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Reservation not found"
+// @Router /api/reservations/{id} [get]
+func (h *ReservationHandler) GetReservation(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	reservation, err := h.repos.Reservations.FindByID(id)
+	if err != nil || !canSeeReservation(c, reservation, *userID) {
+		return reservationNotFound(c)
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Reservation retrieved successfully",
+		Data:    reservation,
+	})
+}
+
+// ConfirmReservation - Handler for turning a reservation into a sale
+// @Summary Confirm a reservation
+// @Description Takes the reserved units off the stock on hand and records them as a sale.
+// @Description Only the user who made the reservation, or an admin, can confirm it.
+// @Tags Reservation
+// @Accept json
+// @Produce json
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} models.Reservation
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Reservation not found"
+// @Failure 409 {object} utils.ApiResponse "Reservation is not active or has expired"
+// @Router /api/reservations/{id}/confirm [post]
+func (h *ReservationHandler) ConfirmReservation(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var reservation *models.Reservation
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		found, err := tx.Reservations.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if !canSeeReservation(c, found, *userID) {
+			return repository.ErrNotFound
+		}
+		if err := tx.Products.Lock(found.ProductID); err != nil {
+			return err
+		}
+		if found.Status != models.ReservationActive {
+			return errReservationNotActive
+		}
+		if !found.ExpiresAt.After(time.Now()) {
+			return errReservationExpired
+		}
+
+		if err := tx.Reservations.SetStatus(id, models.ReservationActive, models.ReservationConfirmed); err != nil {
+			return err
+		}
+		balance, err := tx.Products.AdjustQty(found.ProductID, -found.Quantity)
+		if err != nil {
+			return err
+		}
+		if err := drawDownLevels(tx, found.ProductID, balance); err != nil {
+			return err
+		}
+		reason := fmt.Sprintf("Reservation %d confirmed", id)
+		if _, err := recordMovement(tx, found.ProductID, nil, models.StockSale, -found.Quantity, balance, reason, userID); err != nil {
+			return err
+		}
+
+		reservation, err = tx.Reservations.FindByID(id)
+		return err
+	})
+	if err != nil {
+		return h.transitionFailed(c, err, "Failed to confirm reservation")
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Reservation confirmed successfully",
+		Data:    reservation,
+	})
+}
+
+// ReleaseReservation - Handler for giving reserved stock back
+// @Summary Release a reservation
+// @Description Ends an active reservation so its units become available again. Only the
+// @Description user who made the reservation, or an admin, can release it.
+// @Tags Reservation
+// @Accept json
+// @Produce json
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} models.Reservation
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Reservation not found"
+// @Failure 409 {object} utils.ApiResponse "Reservation is not active"
+// @Router /api/reservations/{id}/release [post]
+func (h *ReservationHandler) ReleaseReservation(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var reservation *models.Reservation
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		found, err := tx.Reservations.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if !canSeeReservation(c, found, *userID) {
+			return repository.ErrNotFound
+		}
+
+		if err := tx.Reservations.SetStatus(id, models.ReservationActive, models.ReservationReleased); err != nil {
+			if errors.Is(err, repository.ErrVersionConflict) {
+				err = errReservationNotActive
+			}
+			return err
+		}
+
+		reservation, err = tx.Reservations.FindByID(id)
+		return err
+	})
+	if err != nil {
+		return h.transitionFailed(c, err, "Failed to release reservation")
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Reservation released successfully",
+		Data:    reservation,
+	})
+}
+
+// transitionFailed answers a confirm or release that could not be applied
+func (h *ReservationHandler) transitionFailed(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return reservationNotFound(c)
+	case errors.Is(err, errReservationNotActive), errors.Is(err, repository.ErrVersionConflict):
+		return reservationNotActive(c)
+	case errors.Is(err, errReservationExpired):
+		return reservationExpired(c)
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+		Success: false,
+		Message: message,
+		Data:    err.Error(),
+	})
+}
+
+// availableQty returns the units of a product that are on hand and not
+// held by an active reservation
+func availableQty(repos repository.Repositories, productID uint) (int, error) {
+	product, err := repos.Products.FindByID(productID)
+	if err != nil {
+		return 0, err
+	}
+	reserved, err := repos.Reservations.ReservedQty([]uint{productID}, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return product.Qty - reserved[productID], nil
+}
+
+// fillAvailable sets Available on every product in place
+func fillAvailable(repos repository.Repositories, products []models.Product) error {
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	reserved, err := repos.Reservations.ReservedQty(ids, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		available := products[i].Qty - reserved[products[i].ID]
+		products[i].Available = &available
+	}
+	return nil
+}
+
+// canSeeReservation reports whether the user making the request may see
+// and act on reservation
+func canSeeReservation(c *fiber.Ctx, reservation *models.Reservation, userID uint) bool {
+	if middlewares.IsAdmin(c) {
+		return true
+	}
+	return reservation.UserID != nil && *reservation.UserID == userID
+}
+
+func reservationNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Reservation not found",
+		Data:    nil,
+	})
+}
+
+func invalidReservation(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid reservation",
+		Data:    reason,
+	})
+}
+
+func reservationNotActive(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Reservation is not active",
+		Data:    nil,
+	})
+}
+
+func reservationExpired(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Reservation has expired",
+		Data:    nil,
+	})
+}
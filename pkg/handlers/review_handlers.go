@@ -0,0 +1,471 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/middlewares"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// ratingDecimals rounds the average rating of a product
+const ratingDecimals = 2
+
+// Longest title and body of a review, in characters
+const (
+	maxReviewTitle = 200
+	maxReviewBody  = 5000
+)
+
+// ReviewHandler serves the review endpoints. Users write, change and
+// delete their own reviews; admins moderate every review.
+type ReviewHandler struct {
+	repos repository.Repositories
+}
+
+// NewReviewHandler creates a ReviewHandler backed by the given repositories
+func NewReviewHandler(repos repository.Repositories) *ReviewHandler {
+	return &ReviewHandler{repos: repos}
+}
+
+// ReviewRequest is the body of a review
+type ReviewRequest struct {
+	Rating int    `json:"rating" example:"5"`
+	Title  string `json:"title" example:"Does what it says"`
+	Body   string `json:"body" example:"Sturdy and well made."`
+}
+
+// ReviewStatusRequest is the body of a moderation decision
+type ReviewStatusRequest struct {
+	Status string `json:"status" example:"approved"`
+}
+
+// CreateReview - Handler for reviewing a product
+// @Summary Review a product
+// @Description Rates a product from 1 to 5 stars as the current user, with an optional title
+// @Description and body. Every user reviews a product once. New reviews are pending until an
+// @Description admin approves them; only approved reviews are listed and count towards the
+// @Description product's rating_average and rating_count.
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param review body ReviewRequest true "Review"
+// @Success 201 {object} models.Review
+// @Failure 400 {object} utils.ApiResponse "Invalid review"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Failure 409 {object} utils.ApiResponse "Product already reviewed"
+// @Router /api/product/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	productID, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request ReviewRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if reason := request.normalize(); reason != "" {
+		return invalidReview(c, reason)
+	}
+	if _, err := h.repos.Products.FindByID(productID); err != nil {
+		return productNotFound(c)
+	}
+
+	review := models.Review{
+		ProductID: productID,
+		UserID:    userID,
+		Rating:    request.Rating,
+		Title:     request.Title,
+		Body:      request.Body,
+		Status:    models.ReviewPending,
+	}
+	err = changeReviews(h.repos, productID, func(tx repository.Repositories) error {
+		return tx.Reviews.Create(&review)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Product already reviewed",
+				Data:    nil,
+			})
+		case errors.Is(err, repository.ErrInvalidReference), errors.Is(err, repository.ErrNotFound):
+			return productNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create review",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Review created successfully",
+		Data:    review,
+	})
+}
+
+// GetProductReviews - Handler for listing the reviews of a product
+// @Summary Get the reviews of a product
+// @Description Retrieves a page of the approved reviews of a product, newest first
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param page query int false "Page, counted from 1"
+// @Param limit query int false "Reviews per page, up to 100 (20 by default)"
+// @Success 200 {object} models.ReviewPage
+// @Failure 400 {object} utils.ApiResponse "Invalid page"
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/product/{id}/reviews [get]
+func (h *ReviewHandler) GetProductReviews(c *fiber.Ctx) error {
+	productID, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+	page, limit, ok := paramPage(c)
+	if !ok {
+		return invalidPage(c)
+	}
+	if _, err := h.repos.Products.FindByID(productID); err != nil {
+		return productNotFound(c)
+	}
+
+	return h.respondReviews(c, repository.ReviewFilter{ProductID: productID, Status: models.ReviewApproved}, page, limit)
+}
+
+// GetReviews - Handler for listing reviews for moderation
+// @Summary Get all reviews
+// @Description Retrieves a page of the reviews of every product, newest first, optionally
+// @Description only those in a moderation status or of one product. Admins only.
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param status query string false "pending, approved or rejected"
+// @Param product query int false "Only return reviews of this product"
+// @Param page query int false "Page, counted from 1"
+// @Param limit query int false "Reviews per page, up to 100 (20 by default)"
+// @Success 200 {object} models.ReviewPage
+// @Failure 400 {object} utils.ApiResponse "Invalid page or review status"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Router /api/reviews [get]
+func (h *ReviewHandler) GetReviews(c *fiber.Ctx) error {
+	page, limit, ok := paramPage(c)
+	if !ok {
+		return invalidPage(c)
+	}
+
+	filter := repository.ReviewFilter{Status: c.Query("status")}
+	if filter.Status != "" && !models.IsReviewStatus(filter.Status) {
+		return invalidReviewStatus(c)
+	}
+	if c.Query("product") != "" {
+		productID := c.QueryInt("product")
+		if productID <= 0 {
+			return invalidID(c)
+		}
+		filter.ProductID = uint(productID)
+	}
+
+	return h.respondReviews(c, filter, page, limit)
+}
+
+// UpdateReview - Handler for changing a review
+// @Summary Update a review
+// @Description Changes the rating, title or body of one of the current user's reviews; fields
+// @Description left out of the body keep their value. The review goes back to pending until an
+// @Description admin approves it again.
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param review body ReviewRequest true "Review update data"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} utils.ApiResponse "Invalid review"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Review not found"
+// @Router /api/review/{id} [patch]
+func (h *ReviewHandler) UpdateReview(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	review, err := h.repos.Reviews.FindByID(id)
+	if err != nil || review.UserID == nil || *review.UserID != *userID {
+		return reviewNotFound(c)
+	}
+
+	request := ReviewRequest{Rating: review.Rating, Title: review.Title, Body: review.Body}
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if reason := request.normalize(); reason != "" {
+		return invalidReview(c, reason)
+	}
+
+	return h.saveReview(c, review, "Review updated successfully", func(tx repository.Repositories, review *models.Review) error {
+		// Fields left out of the body keep the value they have now, not
+		// the one read before the lock was taken
+		request := ReviewRequest{Rating: review.Rating, Title: review.Title, Body: review.Body}
+		if err := c.BodyParser(&request); err != nil {
+			return err
+		}
+		request.normalize()
+		review.Rating, review.Title, review.Body = request.Rating, request.Title, request.Body
+		review.Status = models.ReviewPending
+		return tx.Reviews.Update(review)
+	})
+}
+
+// ModerateReview - Handler for approving or rejecting a review
+// @Summary Moderate a review
+// @Description Sets the moderation status of a review to pending, approved or rejected, and
+// @Description works out the rating of its product again. Admins only.
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param status body ReviewStatusRequest true "Moderation decision"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} utils.ApiResponse "Invalid review status"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Review not found"
+// @Router /api/review/{id}/status [put]
+func (h *ReviewHandler) ModerateReview(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request ReviewStatusRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if !models.IsReviewStatus(request.Status) {
+		return invalidReviewStatus(c)
+	}
+
+	review, err := h.repos.Reviews.FindByID(id)
+	if err != nil {
+		return reviewNotFound(c)
+	}
+
+	return h.saveReview(c, review, "Review moderated successfully", func(tx repository.Repositories, review *models.Review) error {
+		review.Status = request.Status
+		return tx.Reviews.SetStatus(review.ID, review.Status)
+	})
+}
+
+// DeleteReview - Handler for deleting a review
+// @Summary Delete a review
+// @Description Deletes a review by its ID and works out the rating of its product again. Users
+// @Description can only delete their own reviews; admins can delete any.
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Review not found"
+// @Router /api/review/{id} [delete]
+func (h *ReviewHandler) DeleteReview(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	review, err := h.repos.Reviews.FindByID(id)
+	if err != nil {
+		return reviewNotFound(c)
+	}
+	ownReview := review.UserID != nil && *review.UserID == *userID
+	if !ownReview && !middlewares.IsAdmin(c) {
+		return reviewNotFound(c)
+	}
+
+	err = changeReviews(h.repos, review.ProductID, func(tx repository.Repositories) error {
+		return tx.Reviews.Delete(id)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return reviewNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete review",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Review deleted successfully",
+		Data:    nil,
+	})
+}
+
+// saveReview reads review again once its product is locked, has change
+// write it and responds with the review as saved. Reading it under the
+// lock keeps change from undoing a change made to it in the meantime.
+func (h *ReviewHandler) saveReview(c *fiber.Ctx, review *models.Review, message string, change func(tx repository.Repositories, review *models.Review) error) error {
+	err := changeReviews(h.repos, review.ProductID, func(tx repository.Repositories) error {
+		current, err := tx.Reviews.FindByID(review.ID)
+		if err != nil {
+			return err
+		}
+		if err := change(tx, current); err != nil {
+			return err
+		}
+		saved, err := tx.Reviews.FindByID(review.ID)
+		if err != nil {
+			return err
+		}
+		*review = *saved
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return reviewNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update review",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: message,
+		Data:    review,
+	})
+}
+
+// respondReviews responds with page of the reviews matching filter
+func (h *ReviewHandler) respondReviews(c *fiber.Ctx, filter repository.ReviewFilter, page, limit int) error {
+	reviews, total, err := h.repos.Reviews.Find(filter, page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve reviews",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Reviews retrieved successfully",
+		Data:    models.ReviewPage{Reviews: reviews, Page: page, Limit: limit, Total: total},
+	})
+}
+
+// normalize trims the title and body of the request and returns why it
+// is not a valid review, or an empty string when it is
+func (r *ReviewRequest) normalize() string {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Body = strings.TrimSpace(r.Body)
+	switch {
+	case r.Rating < models.MinRating || r.Rating > models.MaxRating:
+		return "rating must be from 1 to 5"
+	case utf8.RuneCountInString(r.Title) > maxReviewTitle:
+		return "title cannot be longer than 200 characters"
+	case utf8.RuneCountInString(r.Body) > maxReviewBody:
+		return "body cannot be longer than 5000 characters"
+	}
+	return ""
+}
+
+// changeReviews runs fn, which changes reviews of a product, in a
+// transaction and then works out the product's rating again from its
+// approved reviews. The product is locked first, so concurrent changes to
+// its reviews are applied one after the other and the last to commit has
+// seen all the others. The product is only written, and its version only
+// bumped, when its rating changed.
+func changeReviews(repos repository.Repositories, productID uint, fn func(tx repository.Repositories) error) error {
+	return repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Products.Lock(productID); err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		count, sum, err := tx.Reviews.Stats(productID)
+		if err != nil {
+			return err
+		}
+		average := decimal.Zero
+		if count > 0 {
+			average = decimal.NewFromInt(int64(sum)).Div(decimal.NewFromInt(int64(count)), ratingDecimals)
+		}
+
+		// Products in the trash are not found but still keep their rating
+		product, err := tx.Products.FindByID(productID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		if err == nil && product.RatingCount == count && product.RatingAverage.Equal(average) {
+			return nil
+		}
+		return tx.Products.SetRating(productID, average, count)
+	})
+}
+
+func reviewNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Review not found",
+		Data:    nil,
+	})
+}
+
+func invalidReview(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid review",
+		Data:    reason,
+	})
+}
+
+func invalidReviewStatus(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid review status",
+		Data:    "status must be one of pending, approved, rejected",
+	})
+}
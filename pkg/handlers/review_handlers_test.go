@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/middlewares"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+// review is a review as the review endpoints return it
+type review struct {
+	ID     uint   `json:"id"`
+	Rating int    `json:"rating"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Status string `json:"status"`
+}
+
+// createReview reviews a product as user and returns the review
+func (a *testApp) createReview(user string, productID uint, body string) review {
+	a.t.Helper()
+	response := a.do("POST", fmt.Sprintf("/api/product/%d/reviews", productID), user, body)
+	a.expect(response, fiber.StatusCreated)
+	var created review
+	a.decode(response, &created)
+	return created
+}
+
+// rating returns the rating average and count of a product
+func (a *testApp) rating(productID uint) (string, int) {
+	a.t.Helper()
+	response := a.do("GET", fmt.Sprintf("/api/product/%d", productID), "", "")
+	a.expect(response, fiber.StatusOK)
+	var product struct {
+		RatingAverage string `json:"rating_average"`
+		RatingCount   int    `json:"rating_count"`
+	}
+	a.decode(response, &product)
+	return product.RatingAverage, product.RatingCount
+}
+
+func TestModerateAndUpdateReview(t *testing.T) {
+	app := newTestApp(t)
+	lamp := app.createProduct("Lamp")
+	created := app.createReview("1", lamp, `{"rating":4,"title":"Bright","body":"Lights the room."}`)
+	path := fmt.Sprintf("/api/review/%d", created.ID)
+
+	var moderated review
+	app.decode(app.do("PUT", path+"/status", "1 admin", `{"status":"approved"}`), &moderated)
+	if moderated.Status != "approved" || moderated.Title != "Bright" || moderated.Rating != 4 {
+		t.Fatalf("got %+v after approving, want the approved review unchanged otherwise", moderated)
+	}
+	if average, count := app.rating(lamp); average != "4.00" || count != 1 {
+		t.Fatalf("got rating %s of %d after approving, want 4.00 of 1", average, count)
+	}
+
+	var updated review
+	app.decode(app.do("PATCH", path, "1", `{"title":"  Too bright  "}`), &updated)
+	if updated.Status != "pending" || updated.Title != "Too bright" || updated.Rating != 4 || updated.Body != "Lights the room." {
+		t.Fatalf("got %+v after updating the title, want the new title, the rest kept and pending", updated)
+	}
+	if average, count := app.rating(lamp); average != "0" || count != 0 {
+		t.Fatalf("got rating %s of %d after the update, want 0 of 0", average, count)
+	}
+
+	app.expect(app.do("PATCH", path, "1", `{"rating":6}`), fiber.StatusBadRequest)
+	app.expect(app.do("PUT", path+"/status", "1 admin", `{"status":"hidden"}`), fiber.StatusBadRequest)
+}
+
+// racingReviews runs race, once, right after a review is first read
+// outside a transaction, as if another request changed it just then
+type racingReviews struct {
+	repository.ReviewRepository
+	race func()
+}
+
+func (r *racingReviews) FindByID(id uint) (*models.Review, error) {
+	review, err := r.ReviewRepository.FindByID(id)
+	if r.race != nil {
+		r.race()
+		r.race = nil
+	}
+	return review, err
+}
+
+// racingApp serves the review endpoints of a with race run after the
+// first read of a review
+func (a *testApp) racingApp(race func()) *fiber.App {
+	repos := a.repos
+	repos.Reviews = &racingReviews{ReviewRepository: a.repos.Reviews, race: race}
+	reviewHandler := NewReviewHandler(repos)
+
+	app := fiber.New()
+	app.Patch("/api/review/:id", authenticate, reviewHandler.UpdateReview)
+	app.Put("/api/review/:id/status", authenticate, middlewares.Admin(), reviewHandler.ModerateReview)
+	return app
+}
+
+func TestReviewChangesKeepConcurrentUpdates(t *testing.T) {
+	app := newTestApp(t)
+	lamp := app.createProduct("Lamp")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		user   string
+		body   string
+		want   review
+	}{
+		{"moderating", "PUT", "/status", "1 admin", `{"status":"approved"}`, review{Rating: 5, Title: "Bright after all", Body: "Changed", Status: "approved"}},
+		{"updating", "PATCH", "", "1", `{"title":"Too bright"}`, review{Rating: 5, Title: "Too bright", Body: "Changed", Status: "pending"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app.t = t
+			created := app.createReview("1", lamp, `{"rating":2,"title":"Dim"}`)
+			app.expect(app.do("PUT", fmt.Sprintf("/api/review/%d/status", created.ID), "1 admin", `{"status":"rejected"}`), fiber.StatusOK)
+
+			racing := app.racingApp(func() {
+				changed := &models.Review{ID: created.ID, Rating: 5, Title: "Bright after all", Body: "Changed", Status: "pending"}
+				if err := app.repos.Reviews.Update(changed); err != nil {
+					t.Error(err)
+				}
+			})
+			req := httptest.NewRequest(test.method, fmt.Sprintf("/api/review/%d%s", created.ID, test.path), strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(testUserHeader, test.user)
+			resp, err := racing.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("got status %d, want 200", resp.StatusCode)
+			}
+
+			stored, err := app.repos.Reviews.FindByID(created.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := review{Rating: stored.Rating, Title: stored.Title, Body: stored.Body, Status: stored.Status}
+			if got != test.want {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+			app.expect(app.do("DELETE", fmt.Sprintf("/api/review/%d", created.ID), "1", ""), fiber.StatusOK)
+		})
+	}
+	app.t = t
+}
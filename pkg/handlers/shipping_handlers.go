@@ -0,0 +1,704 @@
+package handlers
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/shipping"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// ShippingHandler serves the shipping zone, shipping method and shipping
+// quote endpoints
+type ShippingHandler struct {
+	repos    repository.Repositories
+	currency config.CurrencyConfig
+	tax      config.TaxConfig
+}
+
+// NewShippingHandler creates a ShippingHandler backed by the given
+// repositories
+func NewShippingHandler(repos repository.Repositories, currencyCfg config.CurrencyConfig, taxCfg config.TaxConfig) *ShippingHandler {
+	return &ShippingHandler{repos: repos, currency: currencyCfg, tax: taxCfg}
+}
+
+// ShippingZoneRequest is the body of a shipping zone
+type ShippingZoneRequest struct {
+	Name    string   `json:"name" example:"EU"`
+	Regions []string `json:"regions" example:"DE,AT"`
+}
+
+// ShippingQuoteRequest is the body of a shipping quote. Without items the
+// current user's cart is quoted.
+type ShippingQuoteRequest struct {
+	Items    []CartItemRequest `json:"items"`
+	Region   string            `json:"region" example:"DE"`
+	Currency string            `json:"currency" example:"USD"`
+}
+
+// CreateShippingZone - Handler for creating a new shipping zone
+// @Summary Create a shipping zone
+// @Description Creates a shipping zone covering regions such as "DE" or "US-CA", without any
+// @Description methods. A country also covers its subdivisions unless another zone lists them.
+// @Description A region can belong to one zone only. Admins only.
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param zone body ShippingZoneRequest true "Shipping zone"
+// @Success 201 {object} models.ShippingZone
+// @Failure 400 {object} utils.ApiResponse "Invalid shipping zone"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 409 {object} utils.ApiResponse "Zone name already exists or region already in another zone"
+// @Router /api/shipping-zone [post]
+func (h *ShippingHandler) CreateShippingZone(c *fiber.Ctx) error {
+	var request ShippingZoneRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	zone, reason := request.zone()
+	if reason != "" {
+		return invalidShippingZone(c, reason)
+	}
+	if region, err := h.regionTaken(zone); err != nil || region != "" {
+		return h.regionConflict(c, region, err)
+	}
+
+	if err := h.repos.Shipping.CreateZone(&zone); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return shippingZoneNameConflict(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create shipping zone",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Shipping zone created successfully",
+		Data:    zone,
+	})
+}
+
+// GetShippingZones - Handler for listing the shipping zones
+// @Summary Get all shipping zones
+// @Description Retrieves every shipping zone with its methods
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.ShippingZone
+// @Router /api/shipping-zones [get]
+func (h *ShippingHandler) GetShippingZones(c *fiber.Ctx) error {
+	zones, err := h.repos.Shipping.FindZones()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve shipping zones",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Shipping zones retrieved successfully",
+		Data:    zones,
+	})
+}
+
+// GetShippingZone - Handler for getting a shipping zone
+// @Summary Get a shipping zone
+// @Description Retrieves a shipping zone by its ID, with its methods
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping zone ID"
+// @Success 200 {object} models.ShippingZone
+// @Failure 404 {object} utils.ApiResponse "Shipping zone not found"
+// @Router /api/shipping-zone/{id} [get]
+func (h *ShippingHandler) GetShippingZone(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	zone, err := h.repos.Shipping.FindZone(id)
+	if err != nil {
+		return shippingZoneNotFound(c)
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Shipping zone retrieved successfully",
+		Data:    zone,
+	})
+}
+
+// UpdateShippingZone - Handler for updating a shipping zone
+// @Summary Update a shipping zone
+// @Description Replaces the name and regions of a shipping zone by its ID. Admins only.
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping zone ID"
+// @Param zone body ShippingZoneRequest true "Shipping zone update data"
+// @Success 200 {object} models.ShippingZone
+// @Failure 400 {object} utils.ApiResponse "Invalid shipping zone"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Shipping zone not found"
+// @Failure 409 {object} utils.ApiResponse "Zone name already exists or region already in another zone"
+// @Router /api/shipping-zone/{id} [patch]
+func (h *ShippingHandler) UpdateShippingZone(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request ShippingZoneRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	zone, reason := request.zone()
+	if reason != "" {
+		return invalidShippingZone(c, reason)
+	}
+	zone.ID = id
+	if region, err := h.regionTaken(zone); err != nil || region != "" {
+		return h.regionConflict(c, region, err)
+	}
+
+	if err := h.repos.Shipping.UpdateZone(&zone); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return shippingZoneNotFound(c)
+		case errors.Is(err, repository.ErrDuplicate):
+			return shippingZoneNameConflict(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update shipping zone",
+			Data:    err.Error(),
+		})
+	}
+
+	updated, err := h.repos.Shipping.FindZone(id)
+	if err != nil {
+		return shippingZoneNotFound(c)
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Shipping zone updated successfully",
+		Data:    updated,
+	})
+}
+
+// DeleteShippingZone - Handler for deleting a shipping zone
+// @Summary Delete a shipping zone
+// @Description Deletes a shipping zone by its ID together with its methods. Admins only.
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping zone ID"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Shipping zone not found"
+// @Router /api/shipping-zone/{id} [delete]
+func (h *ShippingHandler) DeleteShippingZone(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if err := h.repos.Shipping.DeleteZone(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return shippingZoneNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete shipping zone",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Shipping zone deleted successfully",
+		Data:    nil,
+	})
+}
+
+// CreateShippingMethod - Handler for adding a method to a shipping zone
+// @Summary Create a shipping method
+// @Description Adds a way of shipping to a zone. Type names the rate calculator: "flat" charges
+// @Description price, "free_over" charges price for parcels worth less than threshold and
+// @Description nothing for the others, and "weight_tier" charges the price of the first of tiers
+// @Description the parcel's weight in kilograms is up to. Amounts are in currency, the base
+// @Description currency by default. Admins only.
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping zone ID"
+// @Param method body models.ShippingMethod true "Shipping method"
+// @Success 201 {object} models.ShippingMethod
+// @Failure 400 {object} utils.ApiResponse "Invalid shipping method or unknown currency"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Shipping zone not found"
+// @Router /api/shipping-zone/{id}/methods [post]
+func (h *ShippingHandler) CreateShippingMethod(c *fiber.Ctx) error {
+	zoneID, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var method models.ShippingMethod
+	if err := c.BodyParser(&method); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	method.ID = 0
+	method.ZoneID = zoneID
+	if !h.methodCurrency(&method) {
+		return invalidCurrency(c)
+	}
+	if reason := checkMethod(&method); reason != "" {
+		return invalidShippingMethod(c, reason)
+	}
+
+	if err := h.repos.Shipping.CreateMethod(&method); err != nil {
+		if errors.Is(err, repository.ErrInvalidReference) {
+			return shippingZoneNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create shipping method",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Shipping method created successfully",
+		Data:    method,
+	})
+}
+
+// UpdateShippingMethod - Handler for updating a shipping method
+// @Summary Update a shipping method
+// @Description Updates a method of a shipping zone; fields left out of the body keep their
+// @Description value. Admins only.
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping zone ID"
+// @Param methodId path int true "Shipping method ID"
+// @Param method body models.ShippingMethod true "Shipping method update data"
+// @Success 200 {object} models.ShippingMethod
+// @Failure 400 {object} utils.ApiResponse "Invalid shipping method or unknown currency"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Shipping method not found"
+// @Router /api/shipping-zone/{id}/methods/{methodId} [patch]
+func (h *ShippingHandler) UpdateShippingMethod(c *fiber.Ctx) error {
+	method, ok := h.zoneMethod(c)
+	if !ok {
+		return shippingMethodNotFound(c)
+	}
+	id, zoneID, createdAt := method.ID, method.ZoneID, method.CreatedAt
+
+	if err := c.BodyParser(method); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	method.ID, method.ZoneID, method.CreatedAt = id, zoneID, createdAt
+	if !h.methodCurrency(method) {
+		return invalidCurrency(c)
+	}
+	if reason := checkMethod(method); reason != "" {
+		return invalidShippingMethod(c, reason)
+	}
+
+	if err := h.repos.Shipping.UpdateMethod(method); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return shippingMethodNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update shipping method",
+			Data:    err.Error(),
+		})
+	}
+
+	updated, err := h.repos.Shipping.FindMethod(id)
+	if err != nil {
+		return shippingMethodNotFound(c)
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Shipping method updated successfully",
+		Data:    updated,
+	})
+}
+
+// DeleteShippingMethod - Handler for deleting a shipping method
+// @Summary Delete a shipping method
+// @Description Deletes a method of a shipping zone. Admins only.
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping zone ID"
+// @Param methodId path int true "Shipping method ID"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Shipping method not found"
+// @Router /api/shipping-zone/{id}/methods/{methodId} [delete]
+func (h *ShippingHandler) DeleteShippingMethod(c *fiber.Ctx) error {
+	method, ok := h.zoneMethod(c)
+	if !ok {
+		return shippingMethodNotFound(c)
+	}
+
+	if err := h.repos.Shipping.DeleteMethod(method.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return shippingMethodNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete shipping method",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Shipping method deleted successfully",
+		Data:    nil,
+	})
+}
+
+// QuoteShipping - Handler for quoting shipping
+// @Summary Quote shipping
+// @Description Prices shipping the given items, or the current user's cart when there are none,
+// @Description to region with every method of the zone covering it, cheapest first, in
+// @Description currency (the base currency by default). Each unit weighs the larger of its
+// @Description weight and its volumetric weight, length x width x height / 5000. The goods are
+// @Description valued at their current prices after discounts, tax included. A region no zone
+// @Description covers gets no options.
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param quote body ShippingQuoteRequest true "Items and destination"
+// @Success 200 {object} models.ShippingQuote
+// @Failure 400 {object} utils.ApiResponse "Invalid shipping quote or unknown currency"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/shipping/quote [post]
+func (h *ShippingHandler) QuoteShipping(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+
+	var request ShippingQuoteRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+
+	region := pricing.NormalizeRegion(request.Region)
+	if !pricing.ValidRegion(region) {
+		return invalidShippingQuote(c, "region must be up to 16 letters, digits and hyphens")
+	}
+	target := currency.Normalize(request.Currency)
+	if target == "" {
+		target = h.currency.Base
+	}
+	if !h.currencySupported(target) {
+		return invalidCurrency(c)
+	}
+	items, ok := requestedItems(*userID, request.Items)
+	if !ok {
+		return invalidShippingQuote(c, "quantity must be positive")
+	}
+
+	quote, err := h.quote(*userID, items, region, target)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return productNotFound(c)
+		case errors.Is(err, errEmptyOrder):
+			return invalidShippingQuote(c, "there is nothing to ship")
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to quote shipping",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Shipping quoted successfully",
+		Data:    quote,
+	})
+}
+
+// quote prices shipping items, or the cart of userID when there are none,
+// to region in currency target. The goods are taxed as they would be at
+// checkout: for region when it has tax rates, else for the configured
+// region. Products no longer around fail an explicit list of items with
+// ErrNotFound but are left out of a cart.
+func (h *ShippingHandler) quote(userID uint, items []models.CartItem, region, target string) (*models.ShippingQuote, error) {
+	fromCart := len(items) == 0
+	if fromCart {
+		var err error
+		if items, err = h.repos.Carts.FindByUser(userID); err != nil {
+			return nil, err
+		}
+	}
+
+	taxRegion, ok := checkRegion(h.repos, h.tax, region)
+	if !ok {
+		taxRegion = h.tax.Region
+	}
+	cart, err := priceCart(h.repos, h.currency, h.tax, userID, items, target, taxRegion)
+	if err != nil {
+		return nil, err
+	}
+	if !fromCart && len(cart.Items) < len(items) {
+		return nil, repository.ErrNotFound
+	}
+	if len(cart.Items) == 0 {
+		return nil, errEmptyOrder
+	}
+
+	parcelItems := make([]shipping.Item, 0, len(cart.Items))
+	for _, line := range cart.Items {
+		product, err := h.repos.Products.FindByID(line.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		parcelItems = append(parcelItems, shipping.Item{
+			Quantity: line.Quantity,
+			Weight:   product.Weight,
+			Length:   product.Length,
+			Width:    product.Width,
+			Height:   product.Height,
+		})
+	}
+	parcel := shipping.NewParcel(parcelItems, cart.Total, target)
+
+	quote := &models.ShippingQuote{
+		Region:   region,
+		Currency: target,
+		Weight:   parcel.Weight,
+		Value:    parcel.Value,
+		Options:  []models.ShippingOption{},
+	}
+	zones, err := h.repos.Shipping.FindZones()
+	if err != nil {
+		return nil, err
+	}
+	zone := shipping.MatchZone(zones, region)
+	if zone == nil {
+		return quote, nil
+	}
+
+	converter, err := loadConverter(h.repos, h.currency)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Options, err = shipping.Quote(converter, zone.Methods, parcel, target); err != nil {
+		return nil, err
+	}
+	quote.ZoneID = &zone.ID
+	quote.Zone = zone.Name
+	return quote, nil
+}
+
+// zone turns the request into a zone. It returns why when the request is
+// not valid. Regions are stored in upper case, once each.
+func (r ShippingZoneRequest) zone() (models.ShippingZone, string) {
+	name := strings.TrimSpace(r.Name)
+	if name == "" {
+		return models.ShippingZone{}, "name is required"
+	}
+	if len(r.Regions) == 0 {
+		return models.ShippingZone{}, "at least one region is required"
+	}
+
+	regions := make(models.ShippingRegions, 0, len(r.Regions))
+	for _, region := range r.Regions {
+		region = pricing.NormalizeRegion(region)
+		if !pricing.ValidRegion(region) {
+			return models.ShippingZone{}, "regions must be up to 16 letters, digits and hyphens"
+		}
+		if !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+	return models.ShippingZone{Name: name, Regions: regions}, ""
+}
+
+// regionTaken returns a region of zone that another zone already lists,
+// or an empty string when there is none
+func (h *ShippingHandler) regionTaken(zone models.ShippingZone) (string, error) {
+	zones, err := h.repos.Shipping.FindZones()
+	if err != nil {
+		return "", err
+	}
+	for _, other := range zones {
+		if other.ID == zone.ID {
+			continue
+		}
+		for _, region := range zone.Regions {
+			if slices.Contains(other.Regions, region) {
+				return region, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// regionConflict responds to the outcome of regionTaken
+func (h *ShippingHandler) regionConflict(c *fiber.Ctx, region string, err error) error {
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve shipping zones",
+			Data:    err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Region already belongs to another shipping zone",
+		Data:    region,
+	})
+}
+
+// zoneMethod loads the method named by the ":methodId" route parameter,
+// reporting false unless it belongs to the zone named by ":id"
+func (h *ShippingHandler) zoneMethod(c *fiber.Ctx) (*models.ShippingMethod, bool) {
+	zoneID, err := paramID(c)
+	if err != nil {
+		return nil, false
+	}
+	id, err := paramUint(c, "methodId")
+	if err != nil {
+		return nil, false
+	}
+	method, err := h.repos.Shipping.FindMethod(id)
+	if err != nil || method.ZoneID != zoneID {
+		return nil, false
+	}
+	return method, true
+}
+
+// methodCurrency normalizes the currency of method in place, defaulting
+// to the base currency, and reports whether it is supported
+func (h *ShippingHandler) methodCurrency(method *models.ShippingMethod) bool {
+	method.Currency = currency.Normalize(method.Currency)
+	if method.Currency == "" {
+		method.Currency = h.currency.Base
+	}
+	return h.currencySupported(method.Currency)
+}
+
+// checkMethod normalizes method in place and returns why it cannot be
+// priced, or an empty string when it can
+func checkMethod(method *models.ShippingMethod) string {
+	method.Name = strings.TrimSpace(method.Name)
+	if method.Name == "" {
+		return "name is required"
+	}
+	method.Type = strings.TrimSpace(method.Type)
+	if method.Tiers == nil {
+		method.Tiers = models.ShippingTiers{}
+	}
+	if _, err := shipping.New(*method); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// currencySupported reports whether code is the base currency or has an
+// exchange rate
+func (h *ShippingHandler) currencySupported(code string) bool {
+	if code == h.currency.Base {
+		return true
+	}
+	_, err := h.repos.Currencies.FindRate(code)
+	return err == nil
+}
+
+func shippingZoneNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Shipping zone not found",
+		Data:    nil,
+	})
+}
+
+func shippingMethodNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Shipping method not found",
+		Data:    nil,
+	})
+}
+
+func shippingZoneNameConflict(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Shipping zone name already exists",
+		Data:    nil,
+	})
+}
+
+func invalidShippingZone(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid shipping zone",
+		Data:    reason,
+	})
+}
+
+func invalidShippingMethod(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid shipping method",
+		Data:    reason,
+	})
+}
+
+func invalidShippingQuote(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid shipping quote",
+		Data:    reason,
+	})
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// StockHandler serves the stock ledger endpoints
+type StockHandler struct {
+	repos repository.Repositories
+}
+
+// NewStockHandler creates a StockHandler backed by the given repositories
+func NewStockHandler(repos repository.Repositories) *StockHandler {
+	return &StockHandler{repos: repos}
+}
+
+// StockMovementRequest is the body of a new stock movement. Quantity is
+// the number of units received, sold or returned; for an adjustment it is
+// the signed change. WarehouseID books the movement against the stock of
+// one warehouse instead of the unassigned stock.
+type StockMovementRequest struct {
+	Type        string `json:"type" enums:"receipt,sale,adjustment,return"`
+	Quantity    int    `json:"quantity"`
+	Reason      string `json:"reason"`
+	WarehouseID *uint  `json:"warehouse_id"`
+}
+
+// CreateStockMovement - Handler for recording a stock movement
+// @Summary Record a stock movement
+// @Description Appends a receipt, sale, adjustment or return to the product's stock ledger
+// @Description and updates its quantity on hand in the same transaction. Stock held by
+// @Description active reservations cannot be taken off. With warehouse_id the movement
+// @Description changes the stock of that warehouse; otherwise units taken off come out of
+// @Description unassigned stock first and then out of the warehouses in ID order.
+// @Tags Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param movement body StockMovementRequest true "Stock movement"
+// @Success 201 {object} models.StockMovement
+// @Failure 400 {object} utils.ApiResponse "Invalid stock movement or warehouse"
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Failure 409 {object} utils.ApiResponse "Insufficient stock"
+// @Router /api/product/{id}/stock-movements [post]
+func (h *StockHandler) CreateStockMovement(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request StockMovementRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+
+	delta, err := movementDelta(request.Type, request.Quantity)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Invalid stock movement",
+			Data:    err.Error(),
+		})
+	}
+
+	var movement *models.StockMovement
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if delta < 0 || request.WarehouseID != nil {
+			if err := tx.Products.Lock(id); err != nil {
+				return err
+			}
+		}
+		if delta < 0 {
+			available, err := availableQty(tx, id)
+			if err != nil {
+				return err
+			}
+			if available+delta < 0 {
+				return repository.ErrInsufficientStock
+			}
+		}
+
+		if request.WarehouseID != nil {
+			if err := lockWarehouses(tx, request.WarehouseID); err != nil {
+				return err
+			}
+			if _, err := tx.Warehouses.AdjustLevel(id, *request.WarehouseID, delta); err != nil {
+				return err
+			}
+		}
+
+		balance, err := tx.Products.AdjustQty(id, delta)
+		if err != nil {
+			return err
+		}
+		if request.WarehouseID == nil && delta < 0 {
+			if err := drawDownLevels(tx, id, balance); err != nil {
+				return err
+			}
+		}
+		movement, err = recordMovement(tx, id, request.WarehouseID, request.Type, delta, balance, request.Reason, currentUserID(c))
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return productNotFound(c)
+		case errors.Is(err, errUnknownWarehouse), errors.Is(err, repository.ErrInvalidReference):
+			return invalidWarehouse(c)
+		case errors.Is(err, repository.ErrInsufficientStock):
+			return insufficientStock(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to record stock movement",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Stock movement recorded successfully",
+		Data:    movement,
+	})
+}
+
+// GetStockMovements - Handler for listing the stock history of a product
+// @Summary Get stock history
+// @Description Retrieves the stock ledger of a product, oldest movement first
+// @Tags Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.StockMovement
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/product/{id}/stock-movements [get]
+func (h *StockHandler) GetStockMovements(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if _, err := h.repos.Products.FindByID(id); err != nil {
+		return productNotFound(c)
+	}
+
+	movements, err := h.repos.Stock.FindByProduct(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve stock movements",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Stock movements retrieved successfully",
+		Data:    movements,
+	})
+}
+
+// movementDelta turns a requested movement into the signed change it
+// makes to the quantity on hand
+func movementDelta(movementType string, quantity int) (int, error) {
+	switch movementType {
+	case models.StockReceipt, models.StockReturn:
+		if quantity <= 0 {
+			return 0, errors.New("quantity must be positive")
+		}
+		return quantity, nil
+	case models.StockSale:
+		if quantity <= 0 {
+			return 0, errors.New("quantity must be positive")
+		}
+		return -quantity, nil
+	case models.StockAdjustment:
+		if quantity == 0 {
+			return 0, errors.New("quantity must not be zero")
+		}
+		return quantity, nil
+	default:
+		return 0, errors.New("type must be receipt, sale, adjustment or return")
+	}
+}
+
+// recordMovement appends a movement to the ledger. The caller has already
+// applied delta to the product, leaving balance units on hand, in tx.
+// warehouseID names the warehouse the movement was booked against, if any.
+func recordMovement(tx repository.Repositories, productID uint, warehouseID *uint, movementType string, delta, balance int, reason string, actorID *uint) (*models.StockMovement, error) {
+	movement := &models.StockMovement{
+		ProductID:   productID,
+		WarehouseID: warehouseID,
+		Type:        movementType,
+		Quantity:    delta,
+		Balance:     balance,
+		Reason:      reason,
+		ActorID:     actorID,
+	}
+	if err := tx.Stock.Create(movement); err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// recordVariantMovement appends a movement of the stock of a variant to
+// the ledger of its product. The caller has already applied delta to the
+// variant, leaving balance units on hand, in tx.
+func recordVariantMovement(tx repository.Repositories, productID, variantID uint, movementType string, delta, balance int, reason string, actorID *uint) (*models.StockMovement, error) {
+	movement := &models.StockMovement{
+		ProductID: productID,
+		VariantID: &variantID,
+		Type:      movementType,
+		Quantity:  delta,
+		Balance:   balance,
+		Reason:    reason,
+		ActorID:   actorID,
+	}
+	if err := tx.Stock.Create(movement); err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// drawDownLevels takes stock out of the warehouses of a product until
+// their levels add up to no more than the onHand units left. Unassigned
+// stock is used up first; warehouses then give up stock in ID order. The
+// caller must hold the product lock.
+func drawDownLevels(tx repository.Repositories, productID uint, onHand int) error {
+	levels, err := tx.Warehouses.FindLevels([]uint{productID})
+	if err != nil {
+		return err
+	}
+
+	excess := -onHand
+	for _, level := range levels {
+		excess += level.Qty
+	}
+	for _, level := range levels {
+		if excess <= 0 {
+			break
+		}
+		take := min(excess, level.Qty)
+		if take == 0 {
+			continue
+		}
+		if _, err := tx.Warehouses.AdjustLevel(productID, level.WarehouseID, -take); err != nil {
+			return err
+		}
+		excess -= take
+	}
+	return nil
+}
+
+func insufficientStock(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Insufficient stock",
+		Data:    nil,
+	})
+}
+
+func invalidQuantity(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Quantity cannot be negative",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/pricing"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// TaxHandler serves the tax class and tax rate endpoints
+type TaxHandler struct {
+	repos repository.Repositories
+}
+
+// NewTaxHandler creates a TaxHandler backed by the given repositories
+func NewTaxHandler(repos repository.Repositories) *TaxHandler {
+	return &TaxHandler{repos: repos}
+}
+
+// TaxClassRequest is the body of a tax class
+type TaxClassRequest struct {
+	Name string `json:"name" example:"Reduced"`
+}
+
+// TaxRateRequest is the body of a tax rate, a percentage from 0 to 100
+type TaxRateRequest struct {
+	Rate decimal.Decimal `json:"rate" swaggertype:"string" example:"7"`
+}
+
+// CreateTaxClass - Handler for creating a new tax class
+// @Summary Create a tax class
+// @Description Creates a tax class without any rates. Admins only.
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param class body TaxClassRequest true "Tax class"
+// @Success 201 {object} models.TaxClass
+// @Failure 400 {object} utils.ApiResponse "Invalid tax class"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 409 {object} utils.ApiResponse "Tax class name already exists"
+// @Router /api/tax-class [post]
+func (h *TaxHandler) CreateTaxClass(c *fiber.Ctx) error {
+	var request TaxClassRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return invalidTaxClassName(c)
+	}
+
+	class := models.TaxClass{Name: name}
+	if err := h.repos.Taxes.CreateClass(&class); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return taxClassNameConflict(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create tax class",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Tax class created successfully",
+		Data:    class,
+	})
+}
+
+// GetTaxClasses - Handler for listing the tax classes
+// @Summary Get all tax classes
+// @Description Retrieves every tax class with its rate in each region
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.TaxClass
+// @Router /api/tax-classes [get]
+func (h *TaxHandler) GetTaxClasses(c *fiber.Ctx) error {
+	classes, err := h.repos.Taxes.FindClasses()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve tax classes",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Tax classes retrieved successfully",
+		Data:    classes,
+	})
+}
+
+// GetTaxClass - Handler for getting a tax class
+// @Summary Get a tax class
+// @Description Retrieves a tax class by its ID, with its rate in each region
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Success 200 {object} models.TaxClass
+// @Failure 404 {object} utils.ApiResponse "Tax class not found"
+// @Router /api/tax-class/{id} [get]
+func (h *TaxHandler) GetTaxClass(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	class, err := h.repos.Taxes.FindClass(id)
+	if err != nil {
+		return taxClassNotFound(c)
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Tax class retrieved successfully",
+		Data:    class,
+	})
+}
+
+// UpdateTaxClass - Handler for renaming a tax class
+// @Summary Update a tax class
+// @Description Renames a tax class by its ID. Admins only.
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Param class body TaxClassRequest true "Tax class update data"
+// @Success 200 {object} models.TaxClass
+// @Failure 400 {object} utils.ApiResponse "Invalid tax class"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Tax class not found"
+// @Failure 409 {object} utils.ApiResponse "Tax class name already exists"
+// @Router /api/tax-class/{id} [patch]
+func (h *TaxHandler) UpdateTaxClass(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var request TaxClassRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return invalidTaxClassName(c)
+	}
+
+	if err := h.repos.Taxes.UpdateClass(&models.TaxClass{ID: id, Name: name}); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return taxClassNotFound(c)
+		case errors.Is(err, repository.ErrDuplicate):
+			return taxClassNameConflict(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update tax class",
+			Data:    err.Error(),
+		})
+	}
+
+	class, err := h.repos.Taxes.FindClass(id)
+	if err != nil {
+		return taxClassNotFound(c)
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Tax class updated successfully",
+		Data:    class,
+	})
+}
+
+// DeleteTaxClass - Handler for deleting a tax class
+// @Summary Delete a tax class
+// @Description Deletes a tax class by its ID together with its rates. Categories and products
+// @Description that were assigned the class are left without one. Admins only.
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Tax class not found"
+// @Router /api/tax-class/{id} [delete]
+func (h *TaxHandler) DeleteTaxClass(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if err := h.repos.Taxes.DeleteClass(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return taxClassNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete tax class",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Tax class deleted successfully",
+		Data:    nil,
+	})
+}
+
+// PutTaxRate - Handler for setting the rate of a tax class in a region
+// @Summary Set a tax rate
+// @Description Creates or replaces the percentage a tax class is taxed at in a region, such
+// @Description as "DE" or "US-CA". Admins only.
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Param region path string true "Tax region code"
+// @Param rate body TaxRateRequest true "Tax rate"
+// @Success 200 {object} models.TaxRate
+// @Failure 400 {object} utils.ApiResponse "Invalid tax rate"
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Tax class not found"
+// @Router /api/tax-class/{id}/rates/{region} [put]
+func (h *TaxHandler) PutTaxRate(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+	region := paramRegion(c)
+	if !pricing.ValidRegion(region) {
+		return invalidTaxRate(c, "region must be up to 16 letters, digits and hyphens")
+	}
+
+	var request TaxRateRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if request.Rate.IsNegative() || request.Rate.GreaterThan(decimal.NewFromInt(100)) {
+		return invalidTaxRate(c, "rate must be between 0 and 100")
+	}
+
+	rate := models.TaxRate{TaxClassID: id, Region: region, Rate: request.Rate}
+	if err := h.repos.Taxes.SaveRate(&rate); err != nil {
+		if errors.Is(err, repository.ErrInvalidReference) {
+			return taxClassNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to save tax rate",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Tax rate saved successfully",
+		Data:    rate,
+	})
+}
+
+// DeleteTaxRate - Handler for removing the rate of a tax class in a region
+// @Summary Delete a tax rate
+// @Description Removes the rate of a tax class in a region; goods of the class are then not
+// @Description taxed there. Admins only.
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Param region path string true "Tax region code"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 403 {object} utils.ApiResponse "Forbidden"
+// @Failure 404 {object} utils.ApiResponse "Tax rate not found"
+// @Router /api/tax-class/{id}/rates/{region} [delete]
+func (h *TaxHandler) DeleteTaxRate(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if err := h.repos.Taxes.DeleteRate(id, paramRegion(c)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Tax rate not found",
+				Data:    nil,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete tax rate",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Tax rate deleted successfully",
+		Data:    nil,
+	})
+}
+
+// taxRegion reads the region query parameter, defaulting to the configured
+// region. It reports false for a region no tax class has a rate in, so
+// that a mistyped region is refused rather than quietly left untaxed.
+func taxRegion(c *fiber.Ctx, repos repository.Repositories, cfg config.TaxConfig) (string, bool) {
+	return checkRegion(repos, cfg, strings.Clone(c.Query("region")))
+}
+
+// checkRegion normalizes region as taxRegion does
+func checkRegion(repos repository.Repositories, cfg config.TaxConfig, region string) (string, bool) {
+	region = pricing.NormalizeRegion(region)
+	if region == "" || region == cfg.Region {
+		return cfg.Region, true
+	}
+	if !pricing.ValidRegion(region) {
+		return region, false
+	}
+	rates, err := repos.Taxes.FindRatesByRegion(region)
+	return region, err == nil && len(rates) > 0
+}
+
+// fillTaxes fills in the Tax of every product in place for region.
+// Products are taxed in the currency they are shown in, after discounts.
+func fillTaxes(repos repository.Repositories, currencyCfg config.CurrencyConfig, taxCfg config.TaxConfig, products []models.Product, region string) error {
+	converter, err := loadConverter(repos, currencyCfg)
+	if err != nil {
+		return err
+	}
+	taxer, err := pricing.NewTaxer(repos, converter, region, taxCfg.PricesIncludeTax)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		taxer.Apply(&products[i])
+	}
+	return nil
+}
+
+// taxClassExists reports whether a category or product may point at the
+// tax class. Leaving the class out is always allowed.
+func taxClassExists(repos repository.Repositories, classID *uint) bool {
+	if classID == nil {
+		return true
+	}
+	_, err := repos.Taxes.FindClass(*classID)
+	return err == nil
+}
+
+func taxClassNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Tax class not found",
+		Data:    nil,
+	})
+}
+
+func taxClassNameConflict(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Tax class name already exists",
+		Data:    nil,
+	})
+}
+
+func invalidTaxClassName(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid tax class",
+		Data:    "name is required",
+	})
+}
+
+func invalidTaxClass(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Tax class does not exist",
+		Data:    nil,
+	})
+}
+
+func invalidTaxRate(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid tax rate",
+		Data:    reason,
+	})
+}
+
+func invalidRegion(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Unknown tax region",
+		Data:    nil,
+	})
+}
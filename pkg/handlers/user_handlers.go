@@ -1,13 +1,26 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
-	"github.com/santoadji21/santoadji21-go-fiber-product-api/internal/db"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/middlewares"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// UserHandler serves the user and authentication endpoints
+type UserHandler struct {
+	repos repository.Repositories
+}
+
+// NewUserHandler creates a UserHandler backed by the given repositories
+func NewUserHandler(repos repository.Repositories) *UserHandler {
+	return &UserHandler{repos: repos}
+}
+
 // CreateUser creates a new user
 // @Summary Create a new user
 // @Description Create a new user with the given details
@@ -17,7 +30,7 @@ import (
 // @Param   user body     models.User   true  "User Info"
 // @Success 201 {object}  models.User
 // @Router /api/users [post]
-func CreateUser(c *fiber.Ctx) error {
+func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 	user := new(models.User)
 
 	if err := c.BodyParser(user); err != nil {
@@ -28,16 +41,15 @@ func CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	user.Model = models.Model{}
+
+	// Admin rights are only granted with the admin command
+	user.Admin = false
+
 	// Check if a user with the same email already exists
-	var existingUser models.User
-	result := db.GetDB().Where("email = ?", user.Email).First(&existingUser)
-	if result.Error == nil {
+	if _, err := h.repos.Users.FindByEmail(user.Email); err == nil {
 		// A user with the same email was found
-		return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
-			Success: false,
-			Message: "Email already in use",
-			Data:    nil,
-		})
+		return emailConflict(c)
 	}
 
 	// Hash the password
@@ -52,13 +64,15 @@ func CreateUser(c *fiber.Ctx) error {
 	user.Password = string(hash)
 
 	// Create the user
-	result = db.GetDB().Create(user)
-	if result.Error != nil {
+	if err := h.repos.Users.Create(user); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return emailConflict(c)
+		}
 		// Handle other potential errors
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Failed to create user",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
 		})
 	}
 
@@ -79,15 +93,13 @@ func CreateUser(c *fiber.Ctx) error {
 // @Produce json
 // @Success 200 {array} models.User
 // @Router /api/users [get]
-func GetAllUsers(c *fiber.Ctx) error {
-	var users []models.User
-
-	result := db.GetDB().Find(&users)
-	if result.Error != nil {
+func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
+	users, err := h.repos.Users.FindAll()
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Failed to query users",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
 		})
 	}
 
@@ -111,22 +123,26 @@ func GetAllUsers(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path int true "User ID"
 // @Success 200 {object} models.User
+// @Header 200 {string} ETag "Version of the user"
 // @Failure 404 {object} utils.ApiResponse
 // @Router /api/users/{id} [get]
-func GetUser(c *fiber.Ctx) error {
-	userID := c.Params("id")
-	var user models.User
+func (h *UserHandler) GetUser(c *fiber.Ctx) error {
+	userID, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
 
-	result := db.GetDB().First(&user, userID)
-	if result.Error != nil {
+	user, err := h.repos.Users.FindByID(userID)
+	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "User not found",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
 		})
 	}
 
 	user.Password = ""
+	setETag(c, user.Version)
 	return c.JSON(utils.ApiResponse{
 		Success: true,
 		Message: "User retrieved successfully",
@@ -136,25 +152,43 @@ func GetUser(c *fiber.Ctx) error {
 
 // UpdateUser updates a user's details
 // @Summary Update user
-// @Description Updates a user's details by their ID
+// @Description Updates a user's details by their ID. Users can only update their own
+// @Description account; admins can update any.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Param   user body    models.User   true  "User Info"
 // @Success 200 {object} models.User
+// @Failure 401 {object} utils.ApiResponse
 // @Failure 404 {object} utils.ApiResponse
+// @Failure 412 {object} utils.ApiResponse
 // @Router /api/users/{id} [patch]
-func UpdateUser(c *fiber.Ctx) error {
-	userID := c.Params("id")
-	var user models.User
+func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
+	userID, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+	currentID := currentUserID(c)
+	if currentID == nil {
+		return unauthorized(c)
+	}
+	if !canManageUser(c, userID, *currentID) {
+		return userNotFound(c)
+	}
 
-	if err := db.GetDB().First(&user, userID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
-			Success: false,
-			Message: "User not found",
-			Data:    nil,
-		})
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	user, err := h.repos.Users.FindByID(userID)
+	if err != nil {
+		return userNotFound(c)
+	}
+	if expected != 0 && user.Version != expected {
+		return preconditionFailed(c)
 	}
 
 	type UpdateUserInput struct {
@@ -172,8 +206,35 @@ func UpdateUser(c *fiber.Ctx) error {
 		})
 	}
 
-	db.GetDB().Model(&user).Updates(models.User{FirstName: input.FirstName, LastName: input.LastName, Email: input.Email})
+	// Only overwrite the fields present in the payload
+	if input.FirstName != "" {
+		user.FirstName = input.FirstName
+	}
+	if input.LastName != "" {
+		user.LastName = input.LastName
+	}
+	if input.Email != "" {
+		user.Email = input.Email
+	}
+
+	if err := h.repos.Users.Update(user); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return emailConflict(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return versionConflict(c)
+		case errors.Is(err, repository.ErrNotFound):
+			return userNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update user",
+			Data:    err.Error(),
+		})
+	}
+
 	user.Password = ""
+	setETag(c, user.Version)
 	return c.JSON(utils.ApiResponse{
 		Success: true,
 		Message: "User updated successfully",
@@ -181,34 +242,49 @@ func UpdateUser(c *fiber.Ctx) error {
 	})
 }
 
-// DeleteUser deletes a user
+// DeleteUser moves a user to the trash
 // @Summary Delete user
-// @Description Deletes a user by their ID
+// @Description Moves a user to the trash by their ID. Users can only delete their own
+// @Description account; admins can delete any.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 200 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse
 // @Failure 404 {object} utils.ApiResponse
+// @Failure 412 {object} utils.ApiResponse
 // @Router /api/users/{id} [delete]
-func DeleteUser(c *fiber.Ctx) error {
-	userID := c.Params("id")
-	var user models.User
-	result := db.GetDB().Delete(&models.User{}, userID)
+func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
+	userID, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+	currentID := currentUserID(c)
+	if currentID == nil {
+		return unauthorized(c)
+	}
+	if !canManageUser(c, userID, *currentID) {
+		return userNotFound(c)
+	}
 
-	if err := db.GetDB().First(&user, userID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
-			Success: false,
-			Message: "User not found",
-			Data:    nil,
-		})
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
 	}
 
-	if result.Error != nil {
+	if err := h.repos.Users.Delete(userID, expected); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return userNotFound(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return preconditionFailed(c)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
 			Success: false,
 			Message: "Failed to delete user",
-			Data:    result.Error.Error(),
+			Data:    err.Error(),
 		})
 	}
 
@@ -218,3 +294,28 @@ func DeleteUser(c *fiber.Ctx) error {
 		Data:    nil,
 	})
 }
+
+// canManageUser reports whether the user making the request, currentID,
+// may change or delete the account with the given ID
+func canManageUser(c *fiber.Ctx, userID, currentID uint) bool {
+	if middlewares.IsAdmin(c) {
+		return true
+	}
+	return userID == currentID
+}
+
+func userNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "User not found",
+		Data:    nil,
+	})
+}
+
+func emailConflict(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Email already in use",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCreateUserDuplicateEmail(t *testing.T) {
+	app := newTestApp(t)
+	app.createUser("ada@example.com")
+
+	response := app.do("POST", "/api/users", "", `{"firstName":"Ada","lastName":"King","email":"ada@example.com","password":"other"}`)
+	app.expect(response, fiber.StatusConflict)
+}
+
+func TestCreateUserIgnoresServerFields(t *testing.T) {
+	app := newTestApp(t)
+	response := app.do("POST", "/api/users", "", `{"id":42,"deleted_at":"2000-01-01T00:00:00Z","firstName":"Ada","lastName":"Lovelace","email":"ada@example.com","password":"secret"}`)
+	app.expect(response, fiber.StatusCreated)
+	var user struct {
+		ID uint `json:"id"`
+	}
+	app.decode(response, &user)
+	if user.ID == 42 {
+		t.Fatal("the user was created with the ID the client chose")
+	}
+	app.expect(app.do("GET", fmt.Sprintf("/api/users/%d", user.ID), "", ""), fiber.StatusOK)
+}
+
+func TestCreateUserIgnoresAdmin(t *testing.T) {
+	app := newTestApp(t)
+	response := app.do("POST", "/api/users", "", `{"firstName":"Eve","lastName":"X","email":"eve@example.com","password":"secret","admin":true}`)
+	app.expect(response, fiber.StatusCreated)
+
+	var user struct {
+		Admin    bool   `json:"admin"`
+		Password string `json:"password"`
+	}
+	app.decode(response, &user)
+	if user.Admin {
+		t.Fatal("a user registered themselves as an admin")
+	}
+	if user.Password != "" {
+		t.Fatal("the response carries the password hash")
+	}
+}
+
+func TestUpdateUserDuplicateEmail(t *testing.T) {
+	app := newTestApp(t)
+	app.createUser("ada@example.com")
+	grace := app.createUser("grace@example.com")
+	me := fmt.Sprint(grace)
+
+	response := app.do("PATCH", fmt.Sprintf("/api/users/%d", grace), me, `{"email":"ada@example.com"}`)
+	app.expect(response, fiber.StatusConflict)
+}
+
+func TestUpdateUserOwnership(t *testing.T) {
+	app := newTestApp(t)
+	ada := app.createUser("ada@example.com")
+	grace := app.createUser("grace@example.com")
+	path := fmt.Sprintf("/api/users/%d", ada)
+
+	tests := []struct {
+		name   string
+		user   string
+		status int
+	}{
+		{"anonymous", "", fiber.StatusUnauthorized},
+		{"another user", fmt.Sprint(grace), fiber.StatusNotFound},
+		{"the user", fmt.Sprint(ada), fiber.StatusOK},
+		{"an admin", fmt.Sprintf("%d admin", grace), fiber.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app.t = t
+			app.expect(app.do("PATCH", path, test.user, `{"lastName":"King"}`), test.status)
+		})
+	}
+}
+
+func TestUpdateUserIfMatch(t *testing.T) {
+	app := newTestApp(t)
+	ada := app.createUser("ada@example.com")
+	path := fmt.Sprintf("/api/users/%d", ada)
+	me := fmt.Sprint(ada)
+
+	response := app.do("PATCH", path, me, `{"lastName":"King"}`, fiber.HeaderIfMatch, `"1"`)
+	app.expect(response, fiber.StatusOK)
+	if response.ETag != `"2"` {
+		t.Fatalf("got ETag %s after the update, want \"2\"", response.ETag)
+	}
+	app.expect(app.do("PATCH", path, me, `{"lastName":"Byron"}`, fiber.HeaderIfMatch, `"1"`), fiber.StatusPreconditionFailed)
+	app.expect(app.do("DELETE", path, me, "", fiber.HeaderIfMatch, `"1"`), fiber.StatusPreconditionFailed)
+	app.expect(app.do("DELETE", path, me, "", fiber.HeaderIfMatch, `"2"`), fiber.StatusOK)
+}
+
+func TestDeleteUserSoftDeletes(t *testing.T) {
+	app := newTestApp(t)
+	ada := app.createUser("ada@example.com")
+	path := fmt.Sprintf("/api/users/%d", ada)
+	me := fmt.Sprint(ada)
+
+	app.expect(app.do("DELETE", path, me, ""), fiber.StatusOK)
+	app.expect(app.do("GET", path, "", ""), fiber.StatusNotFound)
+	app.expect(app.do("DELETE", path, me, ""), fiber.StatusNotFound)
+
+	// The unique index on email still covers the deleted user
+	response := app.do("POST", "/api/users", "", `{"firstName":"Ada","lastName":"King","email":"ada@example.com","password":"secret"}`)
+	app.expect(response, fiber.StatusConflict)
+
+	deleted, err := app.repos.Users.FindByID(ada)
+	if err == nil {
+		t.Fatalf("found deleted user %v", deleted)
+	}
+}
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// VariantHandler serves the endpoints for the variants of a product
+type VariantHandler struct {
+	repos repository.Repositories
+}
+
+// NewVariantHandler creates a VariantHandler backed by the given repositories
+func NewVariantHandler(repos repository.Repositories) *VariantHandler {
+	return &VariantHandler{repos: repos}
+}
+
+// CreateVariant - Handler for adding a variant to a product
+// @Summary Create a product variant
+// @Description Adds a variant with its own SKU, option values, stock and optional price override.
+// @Description A non-zero qty is recorded in the variant's stock ledger as the initial receipt.
+// @Tags Variant
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variant body models.Variant true "Variant Info"
+// @Success 201 {object} models.Variant
+// @Failure 400 {object} utils.ApiResponse "SKU is required, or price or qty is negative"
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Failure 409 {object} utils.ApiResponse "SKU already exists"
+// @Router /api/product/{id}/variants [post]
+func (h *VariantHandler) CreateVariant(c *fiber.Ctx) error {
+	productID, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	var variant models.Variant
+	if err := c.BodyParser(&variant); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	variant.Model = models.Model{}
+	variant.ProductID = productID
+	if variant.Options == nil {
+		variant.Options = models.VariantOptions{}
+	}
+
+	if variant.SKU == "" {
+		return skuRequired(c)
+	}
+	if variant.Price != nil && variant.Price.IsNegative() {
+		return invalidPrice(c)
+	}
+	if variant.Qty < 0 {
+		return invalidQuantity(c)
+	}
+
+	if _, err := h.repos.Products.FindByID(productID); err != nil {
+		return productNotFound(c)
+	}
+
+	// Check if a variant with the same SKU already exists
+	if _, err := h.repos.Variants.FindBySKU(variant.SKU); err == nil {
+		return skuConflict(c)
+	}
+
+	// Its opening stock is booked as a receipt so the ledger adds up to Qty
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Variants.Create(&variant); err != nil {
+			return err
+		}
+		if variant.Qty == 0 {
+			return nil
+		}
+		_, err := recordVariantMovement(tx, variant.ProductID, variant.ID, models.StockReceipt, variant.Qty, variant.Qty, "Initial stock", currentUserID(c))
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return skuConflict(c)
+		case errors.Is(err, repository.ErrInvalidReference):
+			return productNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create variant",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Variant created successfully",
+		Data:    variant,
+	})
+}
+
+// GetVariants - Handler for listing the variants of a product
+// @Summary Get product variants
+// @Description Retrieves every variant of a product
+// @Tags Variant
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.Variant
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/product/{id}/variants [get]
+func (h *VariantHandler) GetVariants(c *fiber.Ctx) error {
+	productID, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if _, err := h.repos.Products.FindByID(productID); err != nil {
+		return productNotFound(c)
+	}
+
+	variants, err := h.repos.Variants.FindByProduct(productID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve variants",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Variants retrieved successfully",
+		Data:    variants,
+	})
+}
+
+// GetVariant - Handler for getting one variant of a product
+// @Summary Get a product variant
+// @Description Retrieves a variant of a product by its ID
+// @Tags Variant
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Success 200 {object} models.Variant
+// @Header 200 {string} ETag "Version of the variant"
+// @Failure 404 {object} utils.ApiResponse "Variant not found"
+// @Router /api/product/{id}/variants/{variantId} [get]
+func (h *VariantHandler) GetVariant(c *fiber.Ctx) error {
+	productID, variantID, err := variantParams(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	variant, err := h.findVariant(productID, variantID)
+	if err != nil {
+		return variantNotFound(c)
+	}
+
+	setETag(c, variant.Version)
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Variant retrieved successfully",
+		Data:    variant,
+	})
+}
+
+// UpdateVariant - Handler for updating a variant of a product
+// @Summary Update a product variant
+// @Description Updates a variant of a product by its ID. A change of qty is recorded in the
+// @Description variant's stock ledger as an adjustment.
+// @Tags Variant
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Param variant body models.Variant true "Variant update data"
+// @Success 200 {object} models.Variant
+// @Failure 400 {object} utils.ApiResponse "SKU is required, or price or qty is negative"
+// @Failure 404 {object} utils.ApiResponse "Variant not found"
+// @Failure 409 {object} utils.ApiResponse "SKU already exists"
+// @Failure 412 {object} utils.ApiResponse "Variant was modified since the given ETag"
+// @Router /api/product/{id}/variants/{variantId} [patch]
+func (h *VariantHandler) UpdateVariant(c *fiber.Ctx) error {
+	productID, variantID, err := variantParams(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	variant, err := h.findVariant(productID, variantID)
+	if err != nil {
+		return variantNotFound(c)
+	}
+	if expected != 0 && variant.Version != expected {
+		return preconditionFailed(c)
+	}
+	model := variant.Model
+	qty := variant.Qty
+
+	if err := c.BodyParser(variant); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	variant.Model = model
+	variant.ProductID = productID
+	if variant.Options == nil {
+		variant.Options = models.VariantOptions{}
+	}
+
+	if variant.SKU == "" {
+		return skuRequired(c)
+	}
+	if variant.Price != nil && variant.Price.IsNegative() {
+		return invalidPrice(c)
+	}
+	if variant.Qty < 0 {
+		return invalidQuantity(c)
+	}
+
+	// The version check guarantees qty was still the stock on hand, so the
+	// difference is exactly what the ledger has to record
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Variants.Update(variant); err != nil {
+			return err
+		}
+		if variant.Qty == qty {
+			return nil
+		}
+		_, err := recordVariantMovement(tx, variant.ProductID, variant.ID, models.StockAdjustment, variant.Qty-qty, variant.Qty, "Quantity set by variant update", currentUserID(c))
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return skuConflict(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return versionConflict(c)
+		case errors.Is(err, repository.ErrNotFound):
+			return variantNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update variant",
+			Data:    err.Error(),
+		})
+	}
+
+	setETag(c, variant.Version)
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Variant updated successfully",
+		Data:    variant,
+	})
+}
+
+// DeleteVariant - Handler for deleting a variant of a product
+// @Summary Delete a product variant
+// @Description Moves a variant of a product to the trash by its ID
+// @Tags Variant
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Param If-Match header string false "ETag of the version being deleted"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse "Variant not found"
+// @Failure 412 {object} utils.ApiResponse "Variant was modified since the given ETag"
+// @Router /api/product/{id}/variants/{variantId} [delete]
+func (h *VariantHandler) DeleteVariant(c *fiber.Ctx) error {
+	productID, variantID, err := variantParams(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	if _, err := h.findVariant(productID, variantID); err != nil {
+		return variantNotFound(c)
+	}
+
+	if err := h.repos.Variants.Delete(variantID, expected); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return variantNotFound(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return preconditionFailed(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete variant",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Variant deleted successfully",
+		Data:    nil,
+	})
+}
+
+// findVariant loads a variant, which must belong to the given product
+// and the product must not be in the trash
+func (h *VariantHandler) findVariant(productID, variantID uint) (*models.Variant, error) {
+	if _, err := h.repos.Products.FindByID(productID); err != nil {
+		return nil, err
+	}
+	variant, err := h.repos.Variants.FindByID(variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant.ProductID != productID {
+		return nil, repository.ErrNotFound
+	}
+	return variant, nil
+}
+
+// variantParams parses the ":id" and ":variantId" route parameters
+func variantParams(c *fiber.Ctx) (uint, uint, error) {
+	productID, err := paramID(c)
+	if err != nil {
+		return 0, 0, err
+	}
+	variantID, err := paramUint(c, "variantId")
+	if err != nil {
+		return 0, 0, err
+	}
+	return productID, variantID, nil
+}
+
+func variantNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Variant not found",
+		Data:    nil,
+	})
+}
+
+func skuConflict(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "SKU already exists",
+		Data:    nil,
+	})
+}
+
+func skuRequired(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "SKU is required",
+		Data:    nil,
+	})
+}
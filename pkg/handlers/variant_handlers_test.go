@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// createVariant adds a variant with sku and qty units to a product and
+// returns its ID
+func (a *testApp) createVariant(productID uint, sku string, qty int) uint {
+	a.t.Helper()
+	response := a.do("POST", fmt.Sprintf("/api/product/%d/variants", productID), "1", fmt.Sprintf(`{"sku":%q,"qty":%d}`, sku, qty))
+	a.expect(response, fiber.StatusCreated)
+	var variant struct {
+		ID uint `json:"id"`
+	}
+	a.decode(response, &variant)
+	return variant.ID
+}
+
+func TestVariantNegativeQty(t *testing.T) {
+	app := newTestApp(t)
+	product := app.createProduct("Shirt")
+	path := fmt.Sprintf("/api/product/%d/variants", product)
+
+	app.expect(app.do("POST", path, "1", `{"sku":"SHIRT-S","qty":-1}`), fiber.StatusBadRequest)
+
+	variant := app.createVariant(product, "SHIRT-M", 2)
+	variantPath := fmt.Sprintf("%s/%d", path, variant)
+	app.expect(app.do("PATCH", variantPath, "1", `{"sku":"SHIRT-M","qty":-3}`), fiber.StatusBadRequest)
+
+	var got struct {
+		Qty     int  `json:"qty"`
+		Version uint `json:"version"`
+	}
+	app.decode(app.do("GET", variantPath, "", ""), &got)
+	if got.Qty != 2 || got.Version != 1 {
+		t.Fatalf("got qty %d at version %d, want the variant unchanged", got.Qty, got.Version)
+	}
+}
+
+func TestVariantStockChangesAreRecorded(t *testing.T) {
+	app := newTestApp(t)
+	product := app.createProduct("Shirt")
+	variant := app.createVariant(product, "SHIRT-M", 4)
+	variantPath := fmt.Sprintf("/api/product/%d/variants/%d", product, variant)
+
+	app.expect(app.do("PATCH", variantPath, "1", `{"sku":"SHIRT-M","qty":1}`), fiber.StatusOK)
+	app.expect(app.do("PATCH", variantPath, "1", `{"sku":"SHIRT-M-2","qty":1}`), fiber.StatusOK)
+
+	var movements []struct {
+		VariantID *uint  `json:"variant_id"`
+		Type      string `json:"type"`
+		Quantity  int    `json:"quantity"`
+		Balance   int    `json:"balance"`
+	}
+	app.decode(app.do("GET", fmt.Sprintf("/api/product/%d/stock-movements", product), "1", ""), &movements)
+
+	want := []struct {
+		Type     string
+		Quantity int
+		Balance  int
+	}{
+		{"receipt", 4, 4},
+		{"adjustment", -3, 1},
+	}
+	var variantMovements int
+	for _, movement := range movements {
+		if movement.VariantID == nil {
+			continue
+		}
+		if *movement.VariantID != variant || variantMovements >= len(want) {
+			t.Fatalf("got an unexpected variant movement %+v", movement)
+		}
+		w := want[variantMovements]
+		if movement.Type != w.Type || movement.Quantity != w.Quantity || movement.Balance != w.Balance {
+			t.Fatalf("got movement %+v, want %+v", movement, w)
+		}
+		variantMovements++
+	}
+	if variantMovements != len(want) {
+		t.Fatalf("got %d variant movements, want %d", variantMovements, len(want))
+	}
+}
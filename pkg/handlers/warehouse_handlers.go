@@ -0,0 +1,561 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+var (
+	errWarehouseInUse   = errors.New("warehouse still holds stock")
+	errUnknownWarehouse = errors.New("warehouse does not exist")
+	errUnknownProduct   = errors.New("product does not exist")
+)
+
+// WarehouseHandler serves the warehouse and stock transfer endpoints
+type WarehouseHandler struct {
+	repos repository.Repositories
+}
+
+// NewWarehouseHandler creates a WarehouseHandler backed by the given repositories
+func NewWarehouseHandler(repos repository.Repositories) *WarehouseHandler {
+	return &WarehouseHandler{repos: repos}
+}
+
+// TransferRequest is the body of a new stock transfer. Leaving out
+// from_warehouse_id takes the units from unassigned stock; leaving out
+// to_warehouse_id puts them back there.
+type TransferRequest struct {
+	ProductID       uint   `json:"product_id"`
+	FromWarehouseID *uint  `json:"from_warehouse_id"`
+	ToWarehouseID   *uint  `json:"to_warehouse_id"`
+	Quantity        int    `json:"quantity"`
+	Reason          string `json:"reason"`
+}
+
+// CreateWarehouse - Handler for creating a new warehouse
+// @Summary Create a new warehouse
+// @Description Create a new warehouse with the given name and address
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param warehouse body models.Warehouse true "Warehouse Info"
+// @Success 201 {object} models.Warehouse
+// @Failure 409 {object} utils.ApiResponse "Warehouse name already exists"
+// @Router /api/warehouse [post]
+func (h *WarehouseHandler) CreateWarehouse(c *fiber.Ctx) error {
+	var warehouse models.Warehouse
+	if err := c.BodyParser(&warehouse); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+
+	if _, err := h.repos.Warehouses.FindByName(warehouse.Name); err == nil {
+		return warehouseNameConflict(c)
+	}
+
+	if err := h.repos.Warehouses.Create(&warehouse); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return warehouseNameConflict(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create warehouse",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Warehouse created successfully",
+		Data:    warehouse,
+	})
+}
+
+// GetAllWarehouses - Handler for getting all warehouses
+// @Summary Get all warehouses
+// @Description Retrieves a list of all warehouses
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Warehouse
+// @Router /api/warehouses [get]
+func (h *WarehouseHandler) GetAllWarehouses(c *fiber.Ctx) error {
+	warehouses, err := h.repos.Warehouses.FindAll()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve warehouses",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Warehouses retrieved successfully",
+		Data:    warehouses,
+	})
+}
+
+// GetWarehouse - Handler for getting a warehouse's details
+// @Summary Get a warehouse
+// @Description Retrieves a warehouse by its ID
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Success 200 {object} models.Warehouse
+// @Header 200 {string} ETag "Version of the warehouse"
+// @Failure 404 {object} utils.ApiResponse "Warehouse not found"
+// @Router /api/warehouse/{id} [get]
+func (h *WarehouseHandler) GetWarehouse(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	warehouse, err := h.repos.Warehouses.FindByID(id)
+	if err != nil {
+		return warehouseNotFound(c)
+	}
+
+	setETag(c, warehouse.Version)
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Warehouse retrieved successfully",
+		Data:    warehouse,
+	})
+}
+
+// UpdateWarehouse - Handler for updating a warehouse's details
+// @Summary Update a warehouse
+// @Description Updates a warehouse's name or address by its ID
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Param warehouse body models.Warehouse true "Warehouse update data"
+// @Success 200 {object} models.Warehouse
+// @Failure 404 {object} utils.ApiResponse "Warehouse not found"
+// @Failure 409 {object} utils.ApiResponse "Warehouse name already exists"
+// @Failure 412 {object} utils.ApiResponse "Warehouse was modified since the given ETag"
+// @Router /api/warehouse/{id} [patch]
+func (h *WarehouseHandler) UpdateWarehouse(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	warehouse, err := h.repos.Warehouses.FindByID(id)
+	if err != nil {
+		return warehouseNotFound(c)
+	}
+	if expected != 0 && warehouse.Version != expected {
+		return preconditionFailed(c)
+	}
+	version := warehouse.Version
+
+	if err := c.BodyParser(warehouse); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	warehouse.ID = id
+	warehouse.Version = version
+
+	if err := h.repos.Warehouses.Update(warehouse); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return warehouseNameConflict(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return versionConflict(c)
+		case errors.Is(err, repository.ErrNotFound):
+			return warehouseNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update warehouse",
+			Data:    err.Error(),
+		})
+	}
+
+	setETag(c, warehouse.Version)
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Warehouse updated successfully",
+		Data:    warehouse,
+	})
+}
+
+// DeleteWarehouse - Handler for deleting a warehouse
+// @Summary Delete a warehouse
+// @Description Moves a warehouse to the trash by its ID. A warehouse that still holds stock
+// @Description cannot be deleted; transfer the stock out first.
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Param If-Match header string false "ETag of the version being deleted"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 404 {object} utils.ApiResponse "Warehouse not found"
+// @Failure 409 {object} utils.ApiResponse "Warehouse still holds stock"
+// @Failure 412 {object} utils.ApiResponse "Warehouse was modified since the given ETag"
+// @Router /api/warehouse/{id} [delete]
+func (h *WarehouseHandler) DeleteWarehouse(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	expected, ok := ifMatchVersion(c)
+	if !ok {
+		return preconditionFailed(c)
+	}
+
+	err = h.repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Warehouses.Lock(id); err != nil {
+			return err
+		}
+		stock, err := tx.Warehouses.StockInWarehouse(id)
+		if err != nil {
+			return err
+		}
+		if stock > 0 {
+			return errWarehouseInUse
+		}
+		return tx.Warehouses.Delete(id, expected)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return warehouseNotFound(c)
+		case errors.Is(err, repository.ErrVersionConflict):
+			return preconditionFailed(c)
+		case errors.Is(err, errWarehouseInUse):
+			return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Warehouse still holds stock",
+				Data:    nil,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete warehouse",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Warehouse deleted successfully",
+		Data:    nil,
+	})
+}
+
+// CreateTransfer - Handler for moving stock between warehouses
+// @Summary Transfer stock
+// @Description Moves units of a product from one warehouse to another in a single transaction.
+// @Description A missing from_warehouse_id or to_warehouse_id stands for unassigned stock.
+// @Description The product's total quantity on hand does not change.
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param transfer body TransferRequest true "Stock transfer"
+// @Success 201 {object} models.Transfer
+// @Failure 400 {object} utils.ApiResponse "Invalid transfer"
+// @Failure 409 {object} utils.ApiResponse "Insufficient stock"
+// @Router /api/transfers [post]
+func (h *WarehouseHandler) CreateTransfer(c *fiber.Ctx) error {
+	var request TransferRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+
+	switch {
+	case request.ProductID == 0:
+		return invalidTransfer(c, "product_id is required")
+	case request.Quantity <= 0:
+		return invalidTransfer(c, "quantity must be positive")
+	case sameWarehouse(request.FromWarehouseID, request.ToWarehouseID):
+		return invalidTransfer(c, "from_warehouse_id and to_warehouse_id must differ")
+	}
+
+	transfer := models.Transfer{
+		ProductID:       request.ProductID,
+		FromWarehouseID: request.FromWarehouseID,
+		ToWarehouseID:   request.ToWarehouseID,
+		Quantity:        request.Quantity,
+		Reason:          request.Reason,
+		ActorID:         currentUserID(c),
+	}
+
+	// The product lock keeps the unassigned stock stable while it is
+	// counted; the warehouse locks keep the warehouses from being deleted.
+	err := h.repos.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Products.Lock(transfer.ProductID); err != nil {
+			return err
+		}
+		product, err := tx.Products.FindByID(transfer.ProductID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return errUnknownProduct
+			}
+			return err
+		}
+		if err := lockWarehouses(tx, transfer.FromWarehouseID, transfer.ToWarehouseID); err != nil {
+			return err
+		}
+
+		if transfer.FromWarehouseID == nil {
+			unassigned, err := unassignedQty(tx, *product)
+			if err != nil {
+				return err
+			}
+			if unassigned < transfer.Quantity {
+				return repository.ErrInsufficientStock
+			}
+		} else if _, err := tx.Warehouses.AdjustLevel(product.ID, *transfer.FromWarehouseID, -transfer.Quantity); err != nil {
+			return err
+		}
+
+		if transfer.ToWarehouseID != nil {
+			if _, err := tx.Warehouses.AdjustLevel(product.ID, *transfer.ToWarehouseID, transfer.Quantity); err != nil {
+				return err
+			}
+		}
+		return tx.Transfers.Create(&transfer)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errUnknownProduct):
+			return invalidTransfer(c, err.Error())
+		case errors.Is(err, errUnknownWarehouse), errors.Is(err, repository.ErrInvalidReference):
+			return invalidTransfer(c, errUnknownWarehouse.Error())
+		case errors.Is(err, repository.ErrInsufficientStock):
+			return insufficientStock(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to transfer stock",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Stock transferred successfully",
+		Data:    transfer,
+	})
+}
+
+// GetTransfer - Handler for getting a stock transfer
+// @Summary Get a transfer
+// @Description Retrieves a stock transfer by its ID
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} models.Transfer
+// @Failure 404 {object} utils.ApiResponse "Transfer not found"
+// @Router /api/transfers/{id} [get]
+func (h *WarehouseHandler) GetTransfer(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	transfer, err := h.repos.Transfers.FindByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Transfer not found",
+			Data:    nil,
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Transfer retrieved successfully",
+		Data:    transfer,
+	})
+}
+
+// GetProductTransfers - Handler for listing the transfers of a product
+// @Summary Get product transfers
+// @Description Retrieves the stock transfers of a product, oldest first
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.Transfer
+// @Failure 404 {object} utils.ApiResponse "Product not found"
+// @Router /api/product/{id}/transfers [get]
+func (h *WarehouseHandler) GetProductTransfers(c *fiber.Ctx) error {
+	id, err := paramID(c)
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if _, err := h.repos.Products.FindByID(id); err != nil {
+		return productNotFound(c)
+	}
+
+	transfers, err := h.repos.Transfers.FindByProduct(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve transfers",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Transfers retrieved successfully",
+		Data:    transfers,
+	})
+}
+
+// lockWarehouses locks the given warehouses and makes sure none of them
+// is missing or in the trash. Nil IDs are skipped.
+func lockWarehouses(tx repository.Repositories, ids ...*uint) error {
+	var locked []uint
+	for _, id := range ids {
+		if id != nil {
+			locked = append(locked, *id)
+		}
+	}
+	if err := tx.Warehouses.Lock(locked...); err != nil {
+		return err
+	}
+
+	for _, id := range locked {
+		if _, err := tx.Warehouses.FindByID(id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return errUnknownWarehouse
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// unassignedQty returns the units of a product on hand that are not held
+// by any warehouse
+func unassignedQty(repos repository.Repositories, product models.Product) (int, error) {
+	levels, err := repos.Warehouses.FindLevels([]uint{product.ID})
+	if err != nil {
+		return 0, err
+	}
+	unassigned := product.Qty
+	for _, level := range levels {
+		unassigned -= level.Qty
+	}
+	return unassigned, nil
+}
+
+// fillLocations sets Locations on every product in place: one line per
+// warehouse holding stock, then one for the stock not in any warehouse
+func fillLocations(repos repository.Repositories, products []models.Product) error {
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	levels, err := repos.Warehouses.FindLevels(ids)
+	if err != nil {
+		return err
+	}
+	warehouses, err := repos.Warehouses.FindAll()
+	if err != nil {
+		return err
+	}
+	names := make(map[uint]string, len(warehouses))
+	for _, warehouse := range warehouses {
+		names[warehouse.ID] = warehouse.Name
+	}
+
+	byProduct := make(map[uint][]models.WarehouseStock)
+	for _, level := range levels {
+		byProduct[level.ProductID] = append(byProduct[level.ProductID], level)
+	}
+
+	for i := range products {
+		locations := []models.StockLevel{}
+		unassigned := products[i].Qty
+		for _, level := range byProduct[products[i].ID] {
+			unassigned -= level.Qty
+			if level.Qty == 0 {
+				continue
+			}
+			warehouseID := level.WarehouseID
+			locations = append(locations, models.StockLevel{
+				WarehouseID: &warehouseID,
+				Warehouse:   names[warehouseID],
+				Qty:         level.Qty,
+			})
+		}
+		if unassigned > 0 {
+			locations = append(locations, models.StockLevel{Qty: unassigned})
+		}
+		products[i].Locations = locations
+	}
+	return nil
+}
+
+func sameWarehouse(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func warehouseNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Warehouse not found",
+		Data:    nil,
+	})
+}
+
+func warehouseNameConflict(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Warehouse name already exists",
+		Data:    nil,
+	})
+}
+
+func invalidWarehouse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Warehouse does not exist",
+		Data:    nil,
+	})
+}
+
+func invalidTransfer(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid transfer",
+		Data:    reason,
+	})
+}
@@ -0,0 +1,564 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
+)
+
+// maxWishlistName is the longest name of a wishlist, in characters
+const maxWishlistName = 100
+
+// shareTokenBytes is how many random bytes a share token is made of
+const shareTokenBytes = 16
+
+// WishlistHandler serves the wishlist endpoints. Every user manages
+// their own wishlists; a shared wishlist can be viewed by anyone who
+// has its share token.
+type WishlistHandler struct {
+	repos repository.Repositories
+}
+
+// NewWishlistHandler creates a WishlistHandler backed by the given repositories
+func NewWishlistHandler(repos repository.Repositories) *WishlistHandler {
+	return &WishlistHandler{repos: repos}
+}
+
+// WishlistRequest is the body of a wishlist
+type WishlistRequest struct {
+	Name string `json:"name" example:"Birthday"`
+}
+
+// WishlistItemRequest is the body of a product added to a wishlist
+type WishlistItemRequest struct {
+	ProductID uint `json:"product_id" example:"1"`
+}
+
+// CreateWishlist - Handler for creating a wishlist
+// @Summary Create a wishlist
+// @Description Creates an empty wishlist for the current user. A user's wishlists have
+// @Description different names.
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param wishlist body WishlistRequest true "Wishlist"
+// @Success 201 {object} models.Wishlist
+// @Failure 400 {object} utils.ApiResponse "Invalid wishlist"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 409 {object} utils.ApiResponse "Wishlist name already exists"
+// @Router /api/wishlist [post]
+func (h *WishlistHandler) CreateWishlist(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+
+	var request WishlistRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	name, reason := request.name()
+	if reason != "" {
+		return invalidWishlist(c, reason)
+	}
+
+	wishlist := models.Wishlist{UserID: *userID, Name: name}
+	if err := h.repos.Wishlists.Create(&wishlist); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return wishlistNameConflict(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to create wishlist",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Wishlist created successfully",
+		Data:    wishlist,
+	})
+}
+
+// GetWishlists - Handler for listing the current user's wishlists
+// @Summary Get the wishlists
+// @Description Retrieves the current user's wishlists with their products, in the order they
+// @Description were created. Products in the trash are left out.
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Wishlist
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Router /api/wishlists [get]
+func (h *WishlistHandler) GetWishlists(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+
+	wishlists, err := h.repos.Wishlists.FindByUser(*userID)
+	if err == nil {
+		err = h.attachProducts(wishlists)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve wishlists",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Wishlists retrieved successfully",
+		Data:    wishlists,
+	})
+}
+
+// GetWishlist - Handler for getting a wishlist
+// @Summary Get a wishlist by ID
+// @Description Retrieves one of the current user's wishlists with its products. Products in
+// @Description the trash are left out.
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Success 200 {object} models.Wishlist
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Wishlist not found"
+// @Router /api/wishlist/{id} [get]
+func (h *WishlistHandler) GetWishlist(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	wishlist, ok := h.ownWishlist(c, *userID)
+	if !ok {
+		return wishlistNotFound(c)
+	}
+	return h.respondWishlist(c, fiber.StatusOK, "Wishlist retrieved successfully", wishlist.ID)
+}
+
+// GetSharedWishlist - Handler for viewing a shared wishlist
+// @Summary Get a shared wishlist
+// @Description Retrieves a wishlist with its products through its share token, without
+// @Description signing in. The wishlist cannot be changed this way. Products in the trash
+// @Description are left out.
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} models.Wishlist
+// @Failure 404 {object} utils.ApiResponse "Wishlist not found"
+// @Router /api/wishlists/shared/{token} [get]
+func (h *WishlistHandler) GetSharedWishlist(c *fiber.Ctx) error {
+	wishlist, err := h.repos.Wishlists.FindByShareToken(c.Params("token"))
+	if err != nil {
+		return wishlistNotFound(c)
+	}
+	return h.respondWishlist(c, fiber.StatusOK, "Wishlist retrieved successfully", wishlist.ID)
+}
+
+// UpdateWishlist - Handler for renaming a wishlist
+// @Summary Update a wishlist
+// @Description Renames one of the current user's wishlists
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param wishlist body WishlistRequest true "Wishlist update data"
+// @Success 200 {object} models.Wishlist
+// @Failure 400 {object} utils.ApiResponse "Invalid wishlist"
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Wishlist not found"
+// @Failure 409 {object} utils.ApiResponse "Wishlist name already exists"
+// @Router /api/wishlist/{id} [patch]
+func (h *WishlistHandler) UpdateWishlist(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	wishlist, ok := h.ownWishlist(c, *userID)
+	if !ok {
+		return wishlistNotFound(c)
+	}
+
+	var request WishlistRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	name, reason := request.name()
+	if reason != "" {
+		return invalidWishlist(c, reason)
+	}
+	wishlist.Name = name
+
+	return h.saveWishlist(c, wishlist, "Wishlist updated successfully")
+}
+
+// DeleteWishlist - Handler for deleting a wishlist
+// @Summary Delete a wishlist
+// @Description Deletes one of the current user's wishlists. The products on it are left as
+// @Description they are.
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Success 200 {object} utils.ApiResponse
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Wishlist not found"
+// @Router /api/wishlist/{id} [delete]
+func (h *WishlistHandler) DeleteWishlist(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	wishlist, ok := h.ownWishlist(c, *userID)
+	if !ok {
+		return wishlistNotFound(c)
+	}
+
+	if err := h.repos.Wishlists.Delete(wishlist.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return wishlistNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to delete wishlist",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.JSON(utils.ApiResponse{
+		Success: true,
+		Message: "Wishlist deleted successfully",
+		Data:    nil,
+	})
+}
+
+// AddWishlistItem - Handler for adding a product to a wishlist
+// @Summary Add a product to a wishlist
+// @Description Adds a product to one of the current user's wishlists
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param item body WishlistItemRequest true "Wishlist item"
+// @Success 201 {object} models.Wishlist
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Wishlist or product not found"
+// @Failure 409 {object} utils.ApiResponse "Product already on the wishlist"
+// @Router /api/wishlist/{id}/items [post]
+func (h *WishlistHandler) AddWishlistItem(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	wishlist, ok := h.ownWishlist(c, *userID)
+	if !ok {
+		return wishlistNotFound(c)
+	}
+
+	var request WishlistItemRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Error parsing JSON",
+			Data:    err.Error(),
+		})
+	}
+	if _, err := h.repos.Products.FindByID(request.ProductID); err != nil {
+		return productNotFound(c)
+	}
+
+	item := models.WishlistItem{WishlistID: wishlist.ID, ProductID: request.ProductID}
+	if err := h.repos.Wishlists.AddItem(&item); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Product already on the wishlist",
+				Data:    nil,
+			})
+		case errors.Is(err, repository.ErrInvalidReference):
+			return wishlistNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to add wishlist item",
+			Data:    err.Error(),
+		})
+	}
+
+	return h.respondWishlist(c, fiber.StatusCreated, "Wishlist item added successfully", wishlist.ID)
+}
+
+// RemoveWishlistItem - Handler for removing a product from a wishlist
+// @Summary Remove a product from a wishlist
+// @Description Removes a product from one of the current user's wishlists
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param productId path int true "Product ID"
+// @Success 200 {object} models.Wishlist
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Wishlist or wishlist item not found"
+// @Router /api/wishlist/{id}/items/{productId} [delete]
+func (h *WishlistHandler) RemoveWishlistItem(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	wishlist, ok := h.ownWishlist(c, *userID)
+	if !ok {
+		return wishlistNotFound(c)
+	}
+	productID, err := paramUint(c, "productId")
+	if err != nil {
+		return invalidID(c)
+	}
+
+	if err := h.repos.Wishlists.RemoveItem(wishlist.ID, productID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Wishlist item not found",
+				Data:    nil,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to remove wishlist item",
+			Data:    err.Error(),
+		})
+	}
+
+	return h.respondWishlist(c, fiber.StatusOK, "Wishlist item removed successfully", wishlist.ID)
+}
+
+// ShareWishlist - Handler for sharing a wishlist
+// @Summary Share a wishlist
+// @Description Gives one of the current user's wishlists a new share_token, which lets anyone
+// @Description view it through GET /api/wishlists/shared/{token}. A token handed out before
+// @Description stops working.
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Success 200 {object} models.Wishlist
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Wishlist not found"
+// @Router /api/wishlist/{id}/share [post]
+func (h *WishlistHandler) ShareWishlist(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	wishlist, ok := h.ownWishlist(c, *userID)
+	if !ok {
+		return wishlistNotFound(c)
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to share wishlist",
+			Data:    err.Error(),
+		})
+	}
+	wishlist.ShareToken = &token
+
+	return h.saveWishlist(c, wishlist, "Wishlist shared successfully")
+}
+
+// UnshareWishlist - Handler for no longer sharing a wishlist
+// @Summary Stop sharing a wishlist
+// @Description Removes the share_token of one of the current user's wishlists, so it can no
+// @Description longer be viewed without signing in
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Success 200 {object} models.Wishlist
+// @Failure 401 {object} utils.ApiResponse "Unauthorized"
+// @Failure 404 {object} utils.ApiResponse "Wishlist not found"
+// @Router /api/wishlist/{id}/share [delete]
+func (h *WishlistHandler) UnshareWishlist(c *fiber.Ctx) error {
+	userID := currentUserID(c)
+	if userID == nil {
+		return unauthorized(c)
+	}
+	wishlist, ok := h.ownWishlist(c, *userID)
+	if !ok {
+		return wishlistNotFound(c)
+	}
+	wishlist.ShareToken = nil
+
+	return h.saveWishlist(c, wishlist, "Wishlist no longer shared")
+}
+
+// ownWishlist loads the wishlist named by the ":id" route parameter,
+// reporting false unless it belongs to the user
+func (h *WishlistHandler) ownWishlist(c *fiber.Ctx, userID uint) (*models.Wishlist, bool) {
+	id, err := paramID(c)
+	if err != nil {
+		return nil, false
+	}
+	wishlist, err := h.repos.Wishlists.FindByID(id)
+	if err != nil || wishlist.UserID != userID {
+		return nil, false
+	}
+	return wishlist, true
+}
+
+// saveWishlist writes the name and share token of wishlist and responds
+// with it
+func (h *WishlistHandler) saveWishlist(c *fiber.Ctx, wishlist *models.Wishlist, message string) error {
+	if err := h.repos.Wishlists.Update(wishlist); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return wishlistNotFound(c)
+		case errors.Is(err, repository.ErrDuplicate):
+			return wishlistNameConflict(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to update wishlist",
+			Data:    err.Error(),
+		})
+	}
+	return h.respondWishlist(c, fiber.StatusOK, message, wishlist.ID)
+}
+
+// respondWishlist responds with the wishlist as it is now stored, with
+// its products
+func (h *WishlistHandler) respondWishlist(c *fiber.Ctx, status int, message string, id uint) error {
+	wishlist, err := h.repos.Wishlists.FindByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return wishlistNotFound(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve wishlist",
+			Data:    err.Error(),
+		})
+	}
+
+	wishlists := []models.Wishlist{*wishlist}
+	if err := h.attachProducts(wishlists); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(utils.ApiResponse{
+			Success: false,
+			Message: "Failed to retrieve wishlist products",
+			Data:    err.Error(),
+		})
+	}
+
+	return c.Status(status).JSON(utils.ApiResponse{
+		Success: true,
+		Message: message,
+		Data:    wishlists[0],
+	})
+}
+
+// attachProducts fills in the product of every wishlist item with how
+// many units are available. Items of products moved to the trash since
+// the wishlists were read are dropped.
+func (h *WishlistHandler) attachProducts(wishlists []models.Wishlist) error {
+	var products []models.Product
+	for _, wishlist := range wishlists {
+		for _, item := range wishlist.Items {
+			product, err := h.repos.Products.FindByID(item.ProductID)
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			products = append(products, *product)
+		}
+	}
+	if err := fillAvailable(h.repos, products); err != nil {
+		return err
+	}
+
+	byID := make(map[uint]*models.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+	for i := range wishlists {
+		items := []models.WishlistItem{}
+		for _, item := range wishlists[i].Items {
+			if product, ok := byID[item.ProductID]; ok {
+				item.Product = product
+				items = append(items, item)
+			}
+		}
+		wishlists[i].Items = items
+	}
+	return nil
+}
+
+// name returns the trimmed name of the request, or why it is not a valid
+// wishlist name
+func (r WishlistRequest) name() (string, string) {
+	name := strings.TrimSpace(r.Name)
+	switch {
+	case name == "":
+		return "", "name is required"
+	case utf8.RuneCountInString(name) > maxWishlistName:
+		return "", "name cannot be longer than 100 characters"
+	}
+	return name, ""
+}
+
+// newShareToken returns a random, unguessable share token
+func newShareToken() (string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func wishlistNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Wishlist not found",
+		Data:    nil,
+	})
+}
+
+func wishlistNameConflict(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Wishlist name already exists",
+		Data:    nil,
+	})
+}
+
+func invalidWishlist(c *fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(utils.ApiResponse{
+		Success: false,
+		Message: "Invalid wishlist",
+		Data:    reason,
+	})
+}
@@ -3,6 +3,7 @@ package middlewares
 import (
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/utils"
 )
@@ -15,6 +16,35 @@ func Protected() fiber.Handler {
 	})
 }
 
+// Admin only lets requests through whose token carries the admin claim.
+// It must run after Protected.
+func Admin() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !IsAdmin(c) {
+			return c.Status(fiber.StatusForbidden).JSON(utils.ApiResponse{
+				Success: false,
+				Message: "Forbidden",
+				Data:    nil,
+			})
+		}
+		return c.Next()
+	}
+}
+
+// IsAdmin reports whether the request's token grants admin rights
+func IsAdmin(c *fiber.Ctx) bool {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return false
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return false
+	}
+	admin, _ := claims["admin"].(bool)
+	return admin
+}
+
 func jwtError(c *fiber.Ctx, err error) error {
 	return c.Status(fiber.StatusUnauthorized).JSON(utils.ApiResponse{
 		Success: false,
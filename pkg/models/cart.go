@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// CartItem is one line of a user's cart. VariantID names the variant of
+// the product the line is for, if any. A user has at most one line per
+// product and one per variant.
+type CartItem struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uint      `json:"user_id" gorm:"not null"`
+	ProductID uint      `json:"product_id" gorm:"not null"`
+	VariantID *uint     `json:"variant_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+// Cart is a user's cart priced at the current prices, all in Currency.
+// Subtotal is the sum of its lines and Total what it would cost, tax
+// included; Net and Tax split Total, and Taxes breaks them down by rate.
+// It is computed on every read and never stored.
+type Cart struct {
+	UserID           uint            `json:"user_id"`
+	Currency         string          `json:"currency" example:"USD"`
+	TaxRegion        string          `json:"tax_region" example:"DE"`
+	PricesIncludeTax bool            `json:"prices_include_tax"`
+	Items            []CartLine      `json:"items"`
+	Subtotal         decimal.Decimal `json:"subtotal" swaggertype:"string" example:"35.98"`
+	Net              decimal.Decimal `json:"net" swaggertype:"string" example:"35.98"`
+	Tax              decimal.Decimal `json:"tax" swaggertype:"string" example:"6.84"`
+	Total            decimal.Decimal `json:"total" swaggertype:"string" example:"42.82"`
+	Taxes            []TaxBreakdown  `json:"taxes"`
+}
+
+// CartLine is a cart item with the product's current name and unit
+// price, after the discount rules in force, and the rate it is taxed at.
+// Lines for a variant carry its SKU and are priced at its price override,
+// if it has one. Available is how many units can still be bought.
+type CartLine struct {
+	ProductID uint            `json:"product_id"`
+	VariantID *uint           `json:"variant_id"`
+	Name      string          `json:"name"`
+	SKU       string          `json:"sku,omitempty" example:"TSHIRT-RED-M"`
+	Quantity  int             `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unit_price" swaggertype:"string" example:"17.99"`
+	LineTotal decimal.Decimal `json:"line_total" swaggertype:"string" example:"35.98"`
+	TaxRate   decimal.Decimal `json:"tax_rate" swaggertype:"string" example:"19"`
+	Available int             `json:"available"`
+}
@@ -2,5 +2,27 @@ package models
 
 type Category struct {
 	Model
-	Name string `json:"name" gorm:"unique"`
+	Name     string `json:"name" gorm:"unique"`
+	ParentID *uint  `json:"parent_id"`
+	// TaxClassID is the tax class of the products in the category. A
+	// category without one inherits the class of its parent.
+	TaxClassID *uint `json:"tax_class_id"`
+}
+
+// CategoryNode is a category together with its subcategories
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
+
+// Breadcrumb names one step on the path from a root category
+type Breadcrumb struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// CategoryDetail is a category with its breadcrumbs, root first
+type CategoryDetail struct {
+	Category
+	Breadcrumbs []Breadcrumb `json:"breadcrumbs"`
 }
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// Coupon is a code that takes a percentage or a fixed amount off an
+// order. Fixed amounts and MinOrderValue are in Currency. A nil UsageLimit
+// or PerUserLimit means no limit. Redeemed counts the redemptions so far
+// and is only ever changed by redeeming the coupon or releasing a
+// redemption.
+type Coupon struct {
+	Model
+	Code          string          `json:"code" gorm:"unique;not null" example:"SPRING10"`
+	Description   string          `json:"description"`
+	Type          string          `json:"type" gorm:"not null" example:"percentage"`
+	Value         decimal.Decimal `json:"value" swaggertype:"string" example:"10"`
+	Currency      string          `json:"currency" example:"USD"`
+	MinOrderValue decimal.Decimal `json:"min_order_value" swaggertype:"string" example:"50.00"`
+	UsageLimit    *int            `json:"usage_limit"`
+	PerUserLimit  *int            `json:"per_user_limit"`
+	StartsAt      *time.Time      `json:"starts_at"`
+	EndsAt        *time.Time      `json:"ends_at"`
+	Redeemed      int             `json:"redeemed"`
+}
+
+// InForce reports whether the coupon's validity window covers at
+func (c Coupon) InForce(at time.Time) bool {
+	if c.StartsAt != nil && at.Before(*c.StartsAt) {
+		return false
+	}
+	return c.EndsAt == nil || at.Before(*c.EndsAt)
+}
+
+// CouponRedemption is one entry of the ledger of coupon uses. Discount is
+// what the coupon took off OrderValue, both in Currency. OrderID is the
+// order the coupon was redeemed for; when that order is cancelled, fails
+// or is refunded the use is given back and ReleasedAt records when.
+// Released redemptions stay in the ledger but no longer count as uses.
+type CouponRedemption struct {
+	ID         uint            `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time       `json:"created_at"`
+	CouponID   uint            `json:"coupon_id" gorm:"not null;index"`
+	UserID     *uint           `json:"user_id"`
+	OrderID    *uint           `json:"order_id" gorm:"uniqueIndex"`
+	OrderValue decimal.Decimal `json:"order_value" swaggertype:"string" example:"80.00"`
+	Discount   decimal.Decimal `json:"discount" swaggertype:"string" example:"8.00"`
+	Currency   string          `json:"currency" example:"USD"`
+	ReleasedAt *time.Time      `json:"released_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// Kinds of discount
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// DiscountRule takes a percentage or a fixed amount off the price of the
+// products it is scoped to: one product, every product in a category and
+// its subcategories, or, with neither set, every product. A fixed Value is
+// an amount in Currency. The rule is in force from StartsAt until EndsAt;
+// either may be left open.
+//
+// Rules are applied highest Priority first, each to the price left by the
+// ones before it. An Exclusive rule is never combined with another: it
+// applies alone when it comes first and is skipped otherwise.
+type DiscountRule struct {
+	Model
+	Name       string          `json:"name"`
+	Type       string          `json:"type" gorm:"not null" example:"percentage"`
+	Value      decimal.Decimal `json:"value" swaggertype:"string" example:"10"`
+	Currency   string          `json:"currency,omitempty" example:"USD"`
+	ProductID  *uint           `json:"product_id"`
+	CategoryID *uint           `json:"category_id"`
+	StartsAt   *time.Time      `json:"starts_at"`
+	EndsAt     *time.Time      `json:"ends_at"`
+	Priority   int             `json:"priority"`
+	Exclusive  bool            `json:"exclusive"`
+}
+
+// InForce reports whether the rule's date range covers at
+func (r DiscountRule) InForce(at time.Time) bool {
+	if r.StartsAt != nil && at.Before(*r.StartsAt) {
+		return false
+	}
+	return r.EndsAt == nil || at.Before(*r.EndsAt)
+}
+
+// AppliedDiscount is a discount rule that applied to a price and the
+// amount it took off
+type AppliedDiscount struct {
+	RuleID uint            `json:"rule_id"`
+	Name   string          `json:"name"`
+	Type   string          `json:"type" example:"percentage"`
+	Value  decimal.Decimal `json:"value" swaggertype:"string" example:"10"`
+	Amount decimal.Decimal `json:"amount" swaggertype:"string" example:"1.99"`
+}
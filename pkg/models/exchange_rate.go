@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// ExchangeRate is what one unit of the base currency is worth in another
+// currency. Decimals is how many digits after the point amounts in that
+// currency are rounded to.
+type ExchangeRate struct {
+	Currency  string          `json:"currency" gorm:"primaryKey"`
+	Rate      decimal.Decimal `json:"rate" swaggertype:"string" example:"0.92"`
+	Decimals  int32           `json:"decimals"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// ProductPrice is an explicit price of a product in one currency. It is
+// used instead of converting the product's own price.
+type ProductPrice struct {
+	ProductID uint            `json:"product_id" gorm:"primaryKey;autoIncrement:false"`
+	Currency  string          `json:"currency" gorm:"primaryKey"`
+	Price     decimal.Decimal `json:"price" swaggertype:"string" example:"17.99"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Invoice is the invoice of a paid order. Numbers are handed out in
+// sequence when an order is paid and are never handed out again.
+type Invoice struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	OrderID   uint      `json:"order_id" gorm:"not null;uniqueIndex"`
+	Number    uint      `json:"number" gorm:"not null;uniqueIndex"`
+}
+
+// Code returns the number as printed on the invoice, e.g. INV-000042
+func (i Invoice) Code() string {
+	return fmt.Sprintf("INV-%06d", i.Number)
+}
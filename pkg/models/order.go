@@ -0,0 +1,105 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// States of an order
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderFulfilled = "fulfilled"
+	OrderShipped   = "shipped"
+	OrderDelivered = "delivered"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+	OrderFailed    = "failed"
+)
+
+// orderTransitions lists the states each state of an order can move to.
+// Cancelled, refunded and failed orders are final.
+var orderTransitions = map[string][]string{
+	OrderPending:   {OrderPaid, OrderCancelled, OrderFailed},
+	OrderPaid:      {OrderFulfilled, OrderRefunded},
+	OrderFulfilled: {OrderShipped, OrderRefunded},
+	OrderShipped:   {OrderDelivered},
+	OrderDelivered: {OrderRefunded},
+	OrderCancelled: {},
+	OrderRefunded:  {},
+	OrderFailed:    {},
+}
+
+// IsOrderStatus reports whether status is a state an order can be in
+func IsOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransition reports whether an order may move from one state to another
+func CanTransition(from, to string) bool {
+	return slices.Contains(orderTransitions[from], to)
+}
+
+// Restocks reports whether moving an order into status puts its items
+// back in stock
+func Restocks(status string) bool {
+	return status == OrderCancelled || status == OrderRefunded || status == OrderFailed
+}
+
+// Order is a checked out cart. Subtotal is the sum of its items, Discount
+// what the coupon, if any, took off it, and Total what is owed, tax
+// included. Net and Tax split Total, and Taxes breaks them down by rate.
+// Whether item prices already included tax is kept in PricesIncludeTax.
+// All amounts are in Currency. Items keep the name, price and tax rate
+// each product had at checkout, so later changes to the catalog do not
+// alter the order.
+type Order struct {
+	ID               uint            `json:"id" gorm:"primarykey"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	UserID           *uint           `json:"user_id" gorm:"index"`
+	Status           string          `json:"status" gorm:"not null;default:pending"`
+	Currency         string          `json:"currency" gorm:"not null" example:"USD"`
+	Subtotal         decimal.Decimal `json:"subtotal" swaggertype:"string" example:"80.00"`
+	Discount         decimal.Decimal `json:"discount" swaggertype:"string" example:"8.00"`
+	Net              decimal.Decimal `json:"net" swaggertype:"string" example:"72.00"`
+	Tax              decimal.Decimal `json:"tax" swaggertype:"string" example:"13.68"`
+	Total            decimal.Decimal `json:"total" swaggertype:"string" example:"85.68"`
+	TaxRegion        string          `json:"tax_region" example:"DE"`
+	PricesIncludeTax bool            `json:"prices_include_tax"`
+	CouponID         *uint           `json:"coupon_id"`
+	CouponCode       string          `json:"coupon_code,omitempty" example:"SPRING10"`
+	Items            []OrderItem     `json:"items" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Taxes            []OrderTax      `json:"taxes" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// OrderItem is one line of an order, for a product or one of its
+// variants. ProductID and VariantID are cleared if the product or variant
+// is purged; Name, SKU and UnitPrice stay as they were at checkout.
+type OrderItem struct {
+	ID        uint            `json:"id" gorm:"primarykey"`
+	OrderID   uint            `json:"order_id" gorm:"not null;index"`
+	ProductID *uint           `json:"product_id"`
+	VariantID *uint           `json:"variant_id"`
+	Name      string          `json:"name" gorm:"not null"`
+	SKU       string          `json:"sku,omitempty" gorm:"column:sku" example:"TSHIRT-RED-M"`
+	UnitPrice decimal.Decimal `json:"unit_price" swaggertype:"string" example:"17.99"`
+	Quantity  int             `json:"quantity"`
+	LineTotal decimal.Decimal `json:"line_total" swaggertype:"string" example:"35.98"`
+	TaxRate   decimal.Decimal `json:"tax_rate" swaggertype:"string" example:"19"`
+}
+
+// OrderTransition is one entry of the history of an order's states. The
+// entry recording the order being placed has an empty From.
+type OrderTransition struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	OrderID   uint      `json:"order_id" gorm:"not null;index"`
+	From      string    `json:"from" gorm:"column:from_status" example:"pending"`
+	To        string    `json:"to" gorm:"column:to_status;not null" example:"paid"`
+	Note      string    `json:"note"`
+	ActorID   *uint     `json:"actor_id"`
+}
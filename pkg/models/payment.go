@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// States of a payment
+const (
+	PaymentPending   = "pending"
+	PaymentSucceeded = "succeeded"
+	PaymentFailed    = "failed"
+	PaymentRefunded  = "refunded"
+)
+
+// Payment is an attempt to pay for an order through a payment provider.
+// IntentID is the provider's reference for it. A payment stays pending
+// until the provider reports the outcome through its webhook.
+type Payment struct {
+	ID        uint            `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	OrderID   uint            `json:"order_id" gorm:"not null;index"`
+	Provider  string          `json:"provider" gorm:"not null;uniqueIndex:idx_payments_provider_intent_id" example:"mock"`
+	IntentID  string          `json:"intent_id" gorm:"not null;uniqueIndex:idx_payments_provider_intent_id" example:"pi_4f2a9c"`
+	Method    string          `json:"method" example:"mock_card"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"72.00"`
+	Currency  string          `json:"currency" gorm:"not null" example:"USD"`
+	Status    string          `json:"status" gorm:"not null;default:pending"`
+}
+
+// PaymentEvent records a webhook delivery that has been processed, so a
+// provider delivering the same event again does not apply it twice
+type PaymentEvent struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	Provider  string    `json:"provider" gorm:"not null;uniqueIndex:idx_payment_events_provider_event_id"`
+	EventID   string    `json:"event_id" gorm:"not null;uniqueIndex:idx_payment_events_provider_event_id"`
+	Type      string    `json:"type" gorm:"not null"`
+	PaymentID *uint     `json:"payment_id"`
+}
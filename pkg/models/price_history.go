@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// Sources of a price change
+const (
+	PriceInitial  = "initial"
+	PriceUpdate   = "update"
+	PriceSchedule = "schedule"
+)
+
+// States of a scheduled price
+const (
+	SchedulePending   = "pending"
+	ScheduleApplied   = "applied"
+	ScheduleCancelled = "cancelled"
+)
+
+// PriceChange is one entry of a product's append-only price history. The
+// product cost Price in Currency from EffectiveAt until the next entry.
+type PriceChange struct {
+	ID          uint            `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ProductID   uint            `json:"product_id" gorm:"not null;index"`
+	Price       decimal.Decimal `json:"price" swaggertype:"string" example:"19.99"`
+	Currency    string          `json:"currency" example:"USD"`
+	EffectiveAt time.Time       `json:"effective_at"`
+	Source      string          `json:"source" gorm:"not null"`
+	ActorID     *uint           `json:"actor_id"`
+}
+
+// TableName keeps the history in one table named after what it holds
+func (PriceChange) TableName() string {
+	return "price_history"
+}
+
+// ScheduledPrice is a price a product switches to at EffectiveAt. Pending
+// schedules are applied automatically once they are due.
+type ScheduledPrice struct {
+	ID          uint            `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	ProductID   uint            `json:"product_id" gorm:"not null;index"`
+	Price       decimal.Decimal `json:"price" swaggertype:"string" example:"17.99"`
+	EffectiveAt time.Time       `json:"effective_at"`
+	Status      string          `json:"status" gorm:"not null;default:pending"`
+	ActorID     *uint           `json:"actor_id"`
+}
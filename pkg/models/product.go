@@ -1,12 +1,42 @@
 package models
 
+import "github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+
 // Product represents the product model
 type Product struct {
 	Model
-	Name        string  `json:"name" gorm:"unique;column:name"`
-	Description string  `json:"description"`
-	Qty         int     `json:"qty"`
-	Price       float64 `json:"price"`
-	Discount    float64 `json:"discount"`
-	CategoryID  uint    `json:"category_id"`
+	Name        string          `json:"name" gorm:"unique;column:name"`
+	Description string          `json:"description"`
+	Qty         int             `json:"qty"`
+	Price       decimal.Decimal `json:"price" swaggertype:"string" example:"19.99"`
+	Currency    string          `json:"currency" example:"USD"`
+	CategoryID  *uint           `json:"category_id"`
+	Category    *Category       `json:"category,omitempty" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Variants    []Variant       `json:"variants,omitempty" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	// TaxClassID overrides the tax class the product inherits from its
+	// category
+	TaxClassID *uint `json:"tax_class_id"`
+	// Weight is in kilograms and Length, Width and Height in centimetres.
+	// They are used to quote shipping; zero means not known.
+	Weight decimal.Decimal `json:"weight" swaggertype:"string" example:"0.25"`
+	Length decimal.Decimal `json:"length" swaggertype:"string" example:"30"`
+	Width  decimal.Decimal `json:"width" swaggertype:"string" example:"20"`
+	Height decimal.Decimal `json:"height" swaggertype:"string" example:"2"`
+	// RatingAverage and RatingCount sum up the approved reviews of the
+	// product. They are kept up to date as reviews change and cannot be
+	// set directly.
+	RatingAverage decimal.Decimal `json:"rating_average" swaggertype:"string" example:"4.25"`
+	RatingCount   int             `json:"rating_count" example:"12"`
+	// Available is Qty minus the active reservations. It is only filled in
+	// on reads and never stored.
+	Available *int `json:"available,omitempty" gorm:"-"`
+	// Locations breaks Qty down by warehouse. It is only filled in on reads.
+	Locations []StockLevel `json:"locations,omitempty" gorm:"-"`
+	// EffectivePrice is Price after the discount rules in force, which
+	// are listed in Discounts. Both are only filled in on reads.
+	EffectivePrice *decimal.Decimal  `json:"effective_price,omitempty" gorm:"-" swaggertype:"string" example:"17.99"`
+	Discounts      []AppliedDiscount `json:"discounts,omitempty" gorm:"-"`
+	// Tax splits EffectivePrice into net, tax and gross in the region the
+	// product is shown for. It is only filled in on reads.
+	Tax *TaxBreakdown `json:"tax,omitempty" gorm:"-"`
 }
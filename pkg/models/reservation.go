@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// States of a stock reservation
+const (
+	ReservationActive    = "active"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// Reservation holds Quantity units of a product until ExpiresAt without
+// taking them off the stock on hand. Confirming it books the units as a
+// sale; releasing it or letting it expire gives them back.
+type Reservation struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ProductID uint      `json:"product_id" gorm:"not null;index"`
+	UserID    *uint     `json:"user_id"`
+	Quantity  int       `json:"quantity"`
+	Status    string    `json:"status" gorm:"not null;default:active"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Moderation states of a review
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Bounds of a review's rating, in stars
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// IsReviewStatus reports whether status is a state a review can be in
+func IsReviewStatus(status string) bool {
+	return slices.Contains([]string{ReviewPending, ReviewApproved, ReviewRejected}, status)
+}
+
+// Review is a user's rating of a product from 1 to 5 stars, with an
+// optional title and text. A user reviews a product once. Reviews wait
+// in pending until a moderator approves or rejects them; only approved
+// reviews are shown and count towards the product's rating. UserID is
+// nil once the reviewer's account has been purged.
+type Review struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ProductID uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_reviews_product_user"`
+	UserID    *uint     `json:"user_id" gorm:"uniqueIndex:idx_reviews_product_user"`
+	Rating    int       `json:"rating" gorm:"not null" example:"5"`
+	Title     string    `json:"title" example:"Does what it says"`
+	Body      string    `json:"body" example:"Sturdy and well made."`
+	Status    string    `json:"status" gorm:"not null;index" example:"approved"`
+}
+
+// ReviewPage is one page of reviews, newest first. Total is the number of
+// reviews on all pages together.
+type ReviewPage struct {
+	Reviews []Review `json:"reviews"`
+	Page    int      `json:"page" example:"1"`
+	Limit   int      `json:"limit" example:"20"`
+	Total   int64    `json:"total" example:"42"`
+}
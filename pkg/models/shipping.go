@@ -0,0 +1,133 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// Kinds of shipping rate calculators
+const (
+	ShippingFlat       = "flat"
+	ShippingWeightTier = "weight_tier"
+	ShippingFreeOver   = "free_over"
+)
+
+// ShippingZone is a set of destination regions shipped to on the same
+// terms. A region such as "US" also covers its subdivisions, such as
+// "US-CA", unless another zone lists them itself. A region belongs to at
+// most one zone.
+type ShippingZone struct {
+	ID        uint             `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Name      string           `json:"name" gorm:"unique;not null" example:"EU"`
+	Regions   ShippingRegions  `json:"regions" swaggertype:"array,string" example:"DE,AT"`
+	Methods   []ShippingMethod `json:"methods" gorm:"foreignKey:ZoneID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// ShippingMethod is one way of shipping to a zone, priced by the rate
+// calculator named by Type. Amounts are in Currency and weights in
+// kilograms. Flat methods charge Price; free_over methods charge Price
+// below Threshold and nothing from it on; weight_tier methods charge the
+// price of the first tier the parcel's weight is up to.
+type ShippingMethod struct {
+	ID        uint             `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	ZoneID    uint             `json:"zone_id" gorm:"not null;index"`
+	Name      string           `json:"name" gorm:"not null" example:"Standard"`
+	Type      string           `json:"type" gorm:"not null" example:"flat"`
+	Currency  string           `json:"currency" gorm:"not null" example:"USD"`
+	Price     decimal.Decimal  `json:"price" swaggertype:"string" example:"4.90"`
+	Threshold *decimal.Decimal `json:"threshold" swaggertype:"string" example:"50.00"`
+	Tiers     ShippingTiers    `json:"tiers"`
+}
+
+// ShippingTier prices parcels weighing up to UpTo kilograms
+type ShippingTier struct {
+	UpTo  decimal.Decimal `json:"up_to" swaggertype:"string" example:"2"`
+	Price decimal.Decimal `json:"price" swaggertype:"string" example:"6.90"`
+}
+
+// ShippingRegions lists the regions of a zone. It is stored as a JSON
+// document.
+type ShippingRegions []string
+
+// Value implements driver.Valuer
+func (r ShippingRegions) Value() (driver.Value, error) {
+	return jsonValue(r)
+}
+
+// Scan implements sql.Scanner
+func (r *ShippingRegions) Scan(value interface{}) error {
+	return scanJSON(value, r)
+}
+
+// ShippingTiers lists the tiers of a weight_tier method, lightest first.
+// It is stored as a JSON document.
+type ShippingTiers []ShippingTier
+
+// Value implements driver.Valuer
+func (t ShippingTiers) Value() (driver.Value, error) {
+	return jsonValue(t)
+}
+
+// Scan implements sql.Scanner
+func (t *ShippingTiers) Scan(value interface{}) error {
+	return scanJSON(value, t)
+}
+
+// ShippingQuote lists what it costs to ship a parcel to Region with each
+// method of the zone covering it, cheapest first. Weight is the parcel's
+// chargeable weight in kilograms and Value what its goods are worth, both
+// as the calculators see them. A region no zone covers has no options.
+type ShippingQuote struct {
+	Region   string           `json:"region" example:"DE"`
+	ZoneID   *uint            `json:"zone_id"`
+	Zone     string           `json:"zone,omitempty" example:"EU"`
+	Currency string           `json:"currency" example:"USD"`
+	Weight   decimal.Decimal  `json:"weight" swaggertype:"string" example:"1.25"`
+	Value    decimal.Decimal  `json:"value" swaggertype:"string" example:"35.98"`
+	Options  []ShippingOption `json:"options"`
+}
+
+// ShippingOption is the price of shipping a parcel with one method
+type ShippingOption struct {
+	MethodID uint            `json:"method_id"`
+	Name     string          `json:"name" example:"Standard"`
+	Type     string          `json:"type" example:"flat"`
+	Price    decimal.Decimal `json:"price" swaggertype:"string" example:"4.90"`
+}
+
+// jsonValue stores v as a JSON document. Nil lists are stored as empty
+// ones.
+func jsonValue[T any](v []T) (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// scanJSON reads a JSON document column into dst
+func scanJSON(value interface{}, dst interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", value, dst)
+	}
+	return json.Unmarshal(data, dst)
+}
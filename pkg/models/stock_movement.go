@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Kinds of stock movement
+const (
+	StockReceipt    = "receipt"
+	StockSale       = "sale"
+	StockAdjustment = "adjustment"
+	StockReturn     = "return"
+)
+
+// StockMovement is one entry of the append-only stock ledger. Quantity is
+// the signed change to the product's on-hand stock and Balance the stock
+// right after it, so the ledger of a product always sums to its Qty.
+// Movements of the stock of a variant have VariantID set and count
+// towards the variant's Qty instead. WarehouseID is set when the movement
+// went in or out of a warehouse.
+type StockMovement struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time `json:"created_at"`
+	ProductID   uint      `json:"product_id" gorm:"not null;index"`
+	VariantID   *uint     `json:"variant_id"`
+	WarehouseID *uint     `json:"warehouse_id"`
+	Type        string    `json:"type" gorm:"not null"`
+	Quantity    int       `json:"quantity"`
+	Balance     int       `json:"balance"`
+	Reason      string    `json:"reason"`
+	ActorID     *uint     `json:"actor_id"`
+}
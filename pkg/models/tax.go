@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// TaxClass groups the goods that are taxed alike, such as standard or
+// reduced rate goods. Categories are assigned a class, which the products
+// in them and in their subcategories inherit unless they name their own.
+// Rates lists what the class bears in each region.
+type TaxClass struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" gorm:"unique;not null" example:"Reduced"`
+	Rates     []TaxRate `json:"rates" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TaxRate is the percentage of tax a class bears in one region. Goods
+// sold into a region their class has no rate for are not taxed.
+type TaxRate struct {
+	TaxClassID uint            `json:"tax_class_id" gorm:"primaryKey;autoIncrement:false"`
+	Region     string          `json:"region" gorm:"primaryKey" example:"DE"`
+	Rate       decimal.Decimal `json:"rate" swaggertype:"string" example:"19"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// TaxBreakdown splits an amount into its net part and the tax on it at
+// Rate percent. Net plus Tax is always Gross.
+type TaxBreakdown struct {
+	Rate  decimal.Decimal `json:"rate" swaggertype:"string" example:"19"`
+	Net   decimal.Decimal `json:"net" swaggertype:"string" example:"100.00"`
+	Tax   decimal.Decimal `json:"tax" swaggertype:"string" example:"19.00"`
+	Gross decimal.Decimal `json:"gross" swaggertype:"string" example:"119.00"`
+}
+
+// OrderTax is the tax an order bears at one rate, after its discount
+type OrderTax struct {
+	ID      uint `json:"id" gorm:"primarykey"`
+	OrderID uint `json:"order_id" gorm:"not null;index"`
+	TaxBreakdown
+}
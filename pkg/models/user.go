@@ -7,5 +7,8 @@ type User struct {
     LastName  string `json:"lastName" gorm:"column:last_name"`
     Email     string `json:"email" gorm:"unique;column:email"`
     Password  string `json:"password,omitempty" gorm:"password"`
+    // Admin grants admin rights to the user's tokens. It is only set with
+    // the admin command, never through the API.
+    Admin     bool   `json:"admin" gorm:"column:admin;not null;default:false"`
 }
 
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// Variant is one sellable version of a product, such as a size or colour,
+// with its own SKU and stock. A nil Price means the product's price applies.
+type Variant struct {
+	Model
+	ProductID uint             `json:"product_id" gorm:"not null;index"`
+	SKU       string           `json:"sku" gorm:"column:sku;unique;not null"`
+	Options   VariantOptions   `json:"options"`
+	Price     *decimal.Decimal `json:"price" swaggertype:"string" example:"21.50"`
+	Qty       int              `json:"qty"`
+}
+
+// VariantOptions maps option names to values, e.g. {"size": "M"}.
+// It is stored as a JSON document.
+type VariantOptions map[string]string
+
+// Value implements driver.Valuer
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (o *VariantOptions) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*o = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into VariantOptions", value)
+	}
+	return json.Unmarshal(data, o)
+}
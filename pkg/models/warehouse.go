@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Warehouse is a location that holds stock
+type Warehouse struct {
+	Model
+	Name    string `json:"name" gorm:"unique"`
+	Address string `json:"address"`
+}
+
+// WarehouseStock is the stock of one product held in one warehouse.
+// The levels of a product never add up to more than its Qty; the rest is
+// stock that has not been assigned to a warehouse.
+type WarehouseStock struct {
+	ProductID   uint      `json:"product_id" gorm:"primaryKey;autoIncrement:false"`
+	WarehouseID uint      `json:"warehouse_id" gorm:"primaryKey;autoIncrement:false"`
+	Qty         int       `json:"qty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// StockLevel is one line of a product's stock breakdown by location.
+// A nil WarehouseID stands for stock not assigned to any warehouse.
+type StockLevel struct {
+	WarehouseID *uint  `json:"warehouse_id"`
+	Warehouse   string `json:"warehouse"`
+	Qty         int    `json:"qty"`
+}
+
+// Transfer moves stock of a product from one location to another. A nil
+// warehouse on either side stands for unassigned stock.
+type Transfer struct {
+	ID              uint      `json:"id" gorm:"primarykey"`
+	CreatedAt       time.Time `json:"created_at"`
+	ProductID       uint      `json:"product_id" gorm:"not null;index"`
+	FromWarehouseID *uint     `json:"from_warehouse_id"`
+	ToWarehouseID   *uint     `json:"to_warehouse_id"`
+	Quantity        int       `json:"quantity"`
+	Reason          string    `json:"reason"`
+	ActorID         *uint     `json:"actor_id"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Wishlist is a named list of products a user saved for later. A user's
+// wishlists have different names. ShareToken is set while the wishlist is
+// shared: anyone who knows it can view the wishlist, but only its owner
+// can change it.
+type Wishlist struct {
+	ID         uint           `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	UserID     uint           `json:"user_id" gorm:"not null;uniqueIndex:idx_wishlists_user_id_name"`
+	Name       string         `json:"name" gorm:"not null;uniqueIndex:idx_wishlists_user_id_name" example:"Birthday"`
+	ShareToken *string        `json:"share_token" gorm:"uniqueIndex" example:"3f2a9c0d5e8b41f7a6c2d9e0b1f4a7c3"`
+	Items      []WishlistItem `json:"items" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// WishlistItem is a product on a wishlist. A product is on a wishlist at
+// most once. Product is filled in when the wishlist is returned.
+type WishlistItem struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	WishlistID uint      `json:"wishlist_id" gorm:"not null;uniqueIndex:idx_wishlist_items_wishlist_id_product_id"`
+	ProductID  uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_wishlist_items_wishlist_id_product_id"`
+	Product    *Product  `json:"product,omitempty" gorm:"-"`
+}
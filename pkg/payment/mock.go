@@ -0,0 +1,193 @@
+package payment
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+// MethodDeclined is the method the mock provider declines; payments made
+// with any other method succeed
+const MethodDeclined = "mock_declined"
+
+// deliveryAttempts is how often the mock provider tries to deliver an
+// event before giving up
+const deliveryAttempts = 5
+
+// MockProvider is a payment provider that needs no outside service, for
+// development and CI. It keeps its intents in memory and, like a real
+// provider, posts the outcome of every capture to the webhook in the
+// background, signed with the webhook secret. Deliveries that fail are
+// retried a few times.
+type MockProvider struct {
+	secret     string
+	webhookURL string
+	client     *http.Client
+
+	mu      sync.Mutex
+	intents map[string]Intent
+	refunds map[string]Refund
+}
+
+// NewMockProvider creates a mock provider that signs its events with
+// secret and posts them to webhookURL
+func NewMockProvider(secret, webhookURL string) *MockProvider {
+	return &MockProvider{
+		secret:     secret,
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		intents:    make(map[string]Intent),
+		refunds:    make(map[string]Refund),
+	}
+}
+
+// Name returns "mock"
+func (m *MockProvider) Name() string {
+	return "mock"
+}
+
+func (m *MockProvider) CreateIntent(request IntentRequest) (*Intent, error) {
+	if !request.Amount.GreaterThan(decimal.Zero) {
+		return nil, errors.New("amount must be positive")
+	}
+
+	intent := Intent{
+		ID:       "pi_" + randomID(),
+		OrderID:  request.OrderID,
+		Amount:   request.Amount,
+		Currency: request.Currency,
+		Method:   request.Method,
+		Status:   IntentRequiresCapture,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.intents[intent.ID] = intent
+	return &intent, nil
+}
+
+// Capture settles the intent at once and posts the outcome to the webhook
+// without waiting for it to be delivered
+func (m *MockProvider) Capture(intentID string) (*Intent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	intent, ok := m.intents[intentID]
+	if !ok {
+		return nil, ErrUnknownIntent
+	}
+	if intent.Status != IntentRequiresCapture {
+		return nil, ErrNotCapturable
+	}
+
+	event := Event{
+		ID:        "evt_" + randomID(),
+		Type:      EventSucceeded,
+		IntentID:  intent.ID,
+		OrderID:   intent.OrderID,
+		Amount:    intent.Amount,
+		Currency:  intent.Currency,
+		CreatedAt: time.Now(),
+	}
+	intent.Status = IntentSucceeded
+	if intent.Method == MethodDeclined {
+		event.Type = EventFailed
+		intent.Status = IntentFailed
+	}
+	m.intents[intent.ID] = intent
+
+	go m.deliver(event)
+	return &intent, nil
+}
+
+func (m *MockProvider) Refund(intentID string, amount decimal.Decimal, idempotencyKey string) (*Refund, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if refund, ok := m.refunds[idempotencyKey]; ok {
+		if refund.IntentID != intentID || !refund.Amount.Equal(amount) {
+			return nil, ErrIdempotencyKeyReused
+		}
+		return &refund, nil
+	}
+
+	intent, ok := m.intents[intentID]
+	if !ok {
+		return nil, ErrUnknownIntent
+	}
+	if intent.Status != IntentSucceeded || amount.GreaterThan(intent.Amount) {
+		return nil, ErrNotRefundable
+	}
+
+	intent.Status = IntentRefunded
+	m.intents[intent.ID] = intent
+	refund := Refund{ID: "re_" + randomID(), IntentID: intent.ID, Amount: amount, Currency: intent.Currency}
+	m.refunds[idempotencyKey] = refund
+	return &refund, nil
+}
+
+func (m *MockProvider) ParseWebhook(payload []byte, signature string) (*Event, error) {
+	if err := Verify(m.secret, payload, signature, time.Now()); err != nil {
+		return nil, err
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// deliver posts event to the webhook until it is accepted or every
+// attempt has failed, waiting a little longer after each failure
+func (m *MockProvider) deliver(event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode payment event %s: %v", event.ID, err)
+		return
+	}
+
+	for attempt := 1; attempt <= deliveryAttempts; attempt++ {
+		if err = m.post(payload); err == nil {
+			return
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+	log.Printf("Failed to deliver payment event %s: %v", event.ID, err)
+}
+
+func (m *MockProvider) post(payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, m.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(m.secret, payload, time.Now()))
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// randomID returns 24 random hexadecimal digits
+func randomID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,162 @@
+// Package payment takes payments for orders through a payment provider.
+// Providers report the outcome of a payment asynchronously by posting a
+// signed event to the webhook of the API.
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+)
+
+var (
+	// ErrInvalidSignature is returned for a webhook event whose signature
+	// is missing, malformed, too old or does not match its payload
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrUnknownIntent is returned when a provider has no intent with the
+	// given ID
+	ErrUnknownIntent = errors.New("unknown payment intent")
+
+	// ErrNotCapturable is returned when an intent has already been captured
+	ErrNotCapturable = errors.New("payment intent cannot be captured")
+
+	// ErrNotRefundable is returned when an intent was never paid, has
+	// already been refunded or is worth less than the refund asked for
+	ErrNotRefundable = errors.New("payment intent cannot be refunded")
+
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent
+	// again with a different intent or amount
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused for a different request")
+)
+
+// SignatureHeader is the header webhook events carry their signature in.
+// Its value has the form "t=<unix time>,v1=<hex HMAC-SHA256>", where the
+// HMAC covers the time, a dot and the raw body.
+const SignatureHeader = "X-Payment-Signature"
+
+// SignatureTolerance is how old a signature may be before it is refused,
+// which keeps captured deliveries from being replayed later
+const SignatureTolerance = 5 * time.Minute
+
+// Types of webhook events
+const (
+	EventSucceeded = "payment.succeeded"
+	EventFailed    = "payment.failed"
+)
+
+// States of an intent
+const (
+	IntentRequiresCapture = "requires_capture"
+	IntentSucceeded       = "succeeded"
+	IntentFailed          = "failed"
+	IntentRefunded        = "refunded"
+)
+
+// IntentRequest asks a provider to collect Amount in Currency for an
+// order. Method names the means of payment the provider should charge.
+type IntentRequest struct {
+	OrderID  uint
+	Amount   decimal.Decimal
+	Currency string
+	Method   string
+}
+
+// Intent is a provider's record of a payment it has been asked to collect
+type Intent struct {
+	ID       string
+	OrderID  uint
+	Amount   decimal.Decimal
+	Currency string
+	Method   string
+	Status   string
+}
+
+// Refund is money a provider has paid back on an intent
+type Refund struct {
+	ID       string
+	IntentID string
+	Amount   decimal.Decimal
+	Currency string
+}
+
+// Event is what a provider posts to the webhook when the outcome of a
+// payment is known. ID is unique per event, so a provider delivering the
+// same event twice sends the same ID.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type" example:"payment.succeeded"`
+	IntentID  string          `json:"intent_id"`
+	OrderID   uint            `json:"order_id"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"72.00"`
+	Currency  string          `json:"currency" example:"USD"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Provider collects payments. CreateIntent registers a payment and
+// Capture asks for the money; the outcome arrives later as a webhook
+// event, which ParseWebhook authenticates and decodes. Refund pays back
+// part or all of a captured intent; refunds sent with the same
+// idempotency key are paid back once, and repeating one returns the
+// first refund, so a refund can safely be asked for again.
+type Provider interface {
+	Name() string
+	CreateIntent(request IntentRequest) (*Intent, error)
+	Capture(intentID string) (*Intent, error)
+	Refund(intentID string, amount decimal.Decimal, idempotencyKey string) (*Refund, error)
+	ParseWebhook(payload []byte, signature string) (*Event, error)
+}
+
+// Sign returns the SignatureHeader value for payload signed with secret at
+// the given time
+func Sign(secret string, payload []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, digest(secret, timestamp, payload))
+}
+
+// Verify checks that signature is a SignatureHeader value for payload
+// signed with secret no longer than SignatureTolerance before now. An
+// empty secret verifies nothing.
+func Verify(secret string, payload []byte, signature string, now time.Time) error {
+	if secret == "" {
+		return ErrInvalidSignature
+	}
+
+	var timestamp, mac string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			mac = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || mac == "" {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(mac), []byte(digest(secret, timestamp, payload))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func digest(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
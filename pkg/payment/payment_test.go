@@ -0,0 +1,53 @@
+package payment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	const secret = "whsec_test"
+	payload := []byte(`{"id":"evt_1","type":"payment.succeeded"}`)
+	now := time.Now()
+	valid := Sign(secret, payload, now)
+	_, mac, _ := strings.Cut(valid, ",")
+	tampered := valid[:len(valid)-1] + "0"
+	if tampered == valid {
+		tampered = valid[:len(valid)-1] + "1"
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		payload   []byte
+		signature string
+		ok        bool
+	}{
+		{"valid", secret, payload, valid, true},
+		{"spaces after the comma", secret, payload, strings.Replace(valid, ",", ", ", 1), true},
+		{"empty signature", secret, payload, "", false},
+		{"no time", secret, payload, mac, false},
+		{"no digest", secret, payload, strings.TrimSuffix(valid, mac), false},
+		{"time not a number", secret, payload, "t=now," + mac, false},
+		{"garbage", secret, payload, "not a signature", false},
+		{"other secret", "whsec_other", payload, valid, false},
+		{"empty secret", "", payload, valid, false},
+		{"payload changed", secret, []byte(`{"id":"evt_1","type":"payment.failed"}`), valid, false},
+		{"digest changed", secret, payload, tampered, false},
+		{"too old", secret, payload, Sign(secret, payload, now.Add(-SignatureTolerance-time.Minute)), false},
+		{"from the future", secret, payload, Sign(secret, payload, now.Add(SignatureTolerance+time.Minute)), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Verify(test.secret, test.payload, test.signature, now)
+			if test.ok && err != nil {
+				t.Fatalf("got %v, want the signature accepted", err)
+			}
+			if !test.ok && !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("got %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
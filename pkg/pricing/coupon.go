@@ -0,0 +1,151 @@
+package pricing
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+var (
+	// ErrCouponNotActive is returned outside a coupon's validity window
+	ErrCouponNotActive = errors.New("coupon is not active")
+
+	// ErrBelowMinimum is returned when the order is worth less than the
+	// coupon's minimum order value
+	ErrBelowMinimum = errors.New("order value is below the coupon's minimum")
+
+	// ErrCouponUsedUp is returned when a coupon has reached its usage limit
+	ErrCouponUsedUp = errors.New("coupon has reached its usage limit")
+
+	// ErrUserLimit is returned when a user has already redeemed a coupon as
+	// often as its per-user limit allows
+	ErrUserLimit = errors.New("coupon has reached its limit for this user")
+)
+
+// CouponQuote is what a coupon takes off an order. All amounts are in
+// Currency, the currency of the order. RedemptionID is the ledger entry
+// RedeemCoupon recorded.
+type CouponQuote struct {
+	CouponID     uint            `json:"coupon_id"`
+	RedemptionID uint            `json:"-"`
+	Code         string          `json:"code" example:"SPRING10"`
+	OrderValue   decimal.Decimal `json:"order_value" swaggertype:"string" example:"80.00"`
+	Discount     decimal.Decimal `json:"discount" swaggertype:"string" example:"8.00"`
+	Total        decimal.Decimal `json:"total" swaggertype:"string" example:"72.00"`
+	Currency     string          `json:"currency" example:"USD"`
+}
+
+// NormalizeCode returns a coupon code in the form it is stored in.
+// Codes are matched case-insensitively.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// QuoteCoupon works out what the coupon with the given code takes off an
+// order worth orderValue in orderCurrency at the given time, checking
+// every condition the coupon sets. userID may be nil, in which case the
+// per-user limit is not checked. It returns repository.ErrNotFound for an
+// unknown code and currency.ErrUnknown when the coupon's amounts cannot
+// be converted to orderCurrency.
+func QuoteCoupon(repos repository.Repositories, converter currency.Converter, code string, orderValue decimal.Decimal, orderCurrency string, userID *uint, at time.Time) (*CouponQuote, error) {
+	coupon, err := repos.Coupons.FindByCode(NormalizeCode(code))
+	if err != nil {
+		return nil, err
+	}
+	return quote(repos, converter, coupon, orderValue, orderCurrency, userID, at)
+}
+
+// RedeemCoupon quotes the coupon like QuoteCoupon and records the
+// redemption against its limits. It must run inside a transaction: the
+// coupon stays locked until it ends, so concurrent redemptions by the
+// same user cannot both slip under the per-user limit.
+func RedeemCoupon(tx repository.Repositories, converter currency.Converter, code string, orderValue decimal.Decimal, orderCurrency string, userID *uint, at time.Time) (*CouponQuote, error) {
+	coupon, err := tx.Coupons.FindByCode(NormalizeCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Coupons.Lock(coupon.ID); err != nil {
+		return nil, err
+	}
+	// Read it again now that nobody else can redeem it
+	coupon, err = tx.Coupons.FindByID(coupon.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := quote(tx, converter, coupon, orderValue, orderCurrency, userID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	redemption := models.CouponRedemption{
+		CouponID:   coupon.ID,
+		UserID:     userID,
+		OrderValue: quote.OrderValue,
+		Discount:   quote.Discount,
+		Currency:   quote.Currency,
+	}
+	err = tx.Coupons.Redeem(&redemption)
+	if errors.Is(err, repository.ErrLimitReached) {
+		return nil, ErrCouponUsedUp
+	}
+	if err != nil {
+		return nil, err
+	}
+	quote.RedemptionID = redemption.ID
+	return quote, nil
+}
+
+func quote(repos repository.Repositories, converter currency.Converter, coupon *models.Coupon, orderValue decimal.Decimal, orderCurrency string, userID *uint, at time.Time) (*CouponQuote, error) {
+	if !coupon.InForce(at) {
+		return nil, ErrCouponNotActive
+	}
+	if coupon.UsageLimit != nil && coupon.Redeemed >= *coupon.UsageLimit {
+		return nil, ErrCouponUsedUp
+	}
+	if userID != nil && coupon.PerUserLimit != nil {
+		used, err := repos.Coupons.CountRedemptions(coupon.ID, *userID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(*coupon.PerUserLimit) {
+			return nil, ErrUserLimit
+		}
+	}
+
+	minimum, err := converter.Convert(coupon.MinOrderValue, coupon.Currency, orderCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if orderValue.LessThan(minimum) {
+		return nil, ErrBelowMinimum
+	}
+
+	var discount decimal.Decimal
+	switch coupon.Type {
+	case models.DiscountPercentage:
+		discount = converter.Round(orderValue.Percent(coupon.Value), orderCurrency)
+	case models.DiscountFixed:
+		discount, err = converter.Convert(coupon.Value, coupon.Currency, orderCurrency)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if discount.GreaterThan(orderValue) {
+		discount = orderValue
+	}
+
+	return &CouponQuote{
+		CouponID:   coupon.ID,
+		Code:       coupon.Code,
+		OrderValue: orderValue,
+		Discount:   discount,
+		Total:      orderValue.Sub(discount),
+		Currency:   orderCurrency,
+	}, nil
+}
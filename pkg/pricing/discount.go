@@ -0,0 +1,121 @@
+package pricing
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+// Discounter applies the discount rules in force at one point in time to
+// product prices
+type Discounter struct {
+	rules     []models.DiscountRule
+	parents   map[uint]*uint
+	converter currency.Converter
+}
+
+// NewDiscounter reads the rules in force at the given time. Fixed amounts
+// are converted to the currency of each product with converter.
+func NewDiscounter(repos repository.Repositories, converter currency.Converter, at time.Time) (*Discounter, error) {
+	rules, err := repos.Discounts.FindInForce(at)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Discounter{rules: rules, converter: converter}
+	for _, rule := range rules {
+		if rule.CategoryID == nil {
+			continue
+		}
+		// Category rules cover subcategories, so the tree is needed
+		categories, err := repos.Categories.FindAll()
+		if err != nil {
+			return nil, err
+		}
+		d.parents = make(map[uint]*uint, len(categories))
+		for _, category := range categories {
+			d.parents[category.ID] = category.ParentID
+		}
+		break
+	}
+	return d, nil
+}
+
+// Apply fills in the EffectivePrice and Discounts of product from its
+// Price and Currency. Rules are taken highest priority first, each one
+// off the price left by the ones before it, and never take the price
+// below zero. A fixed rule whose currency can no longer be converted is
+// skipped.
+func (d *Discounter) Apply(product *models.Product) {
+	remaining := product.Price
+	applied := []models.AppliedDiscount{}
+
+	for _, rule := range d.rules {
+		if !d.covers(rule, *product) {
+			continue
+		}
+		if rule.Exclusive && len(applied) > 0 {
+			continue
+		}
+
+		var amount decimal.Decimal
+		switch rule.Type {
+		case models.DiscountPercentage:
+			amount = d.converter.Round(remaining.Percent(rule.Value), product.Currency)
+		case models.DiscountFixed:
+			converted, err := d.converter.Convert(rule.Value, rule.Currency, product.Currency)
+			if err != nil {
+				continue
+			}
+			amount = converted
+		default:
+			continue
+		}
+		if amount.GreaterThan(remaining) {
+			amount = remaining
+		}
+
+		remaining = remaining.Sub(amount)
+		applied = append(applied, models.AppliedDiscount{
+			RuleID: rule.ID,
+			Name:   rule.Name,
+			Type:   rule.Type,
+			Value:  rule.Value,
+			Amount: amount,
+		})
+		if rule.Exclusive {
+			break
+		}
+	}
+
+	product.EffectivePrice = &remaining
+	product.Discounts = applied
+}
+
+// covers reports whether rule is scoped to product
+func (d *Discounter) covers(rule models.DiscountRule, product models.Product) bool {
+	switch {
+	case rule.ProductID != nil:
+		return *rule.ProductID == product.ID
+	case rule.CategoryID != nil:
+		return d.inSubtree(product.CategoryID, *rule.CategoryID)
+	default:
+		return true
+	}
+}
+
+// inSubtree reports whether categoryID is root or one of its descendants
+func (d *Discounter) inSubtree(categoryID *uint, root uint) bool {
+	seen := make(map[uint]bool)
+	for categoryID != nil && !seen[*categoryID] {
+		if *categoryID == root {
+			return true
+		}
+		seen[*categoryID] = true
+		categoryID = d.parents[*categoryID]
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+// Package pricing keeps the price history of products, applies their
+// scheduled prices and works out what discounts and coupons take off.
+package pricing
+
+import (
+	"errors"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+// Effective is the price of a product at a point in time. Scheduled is
+// set when the price comes from a schedule that has not been applied yet.
+type Effective struct {
+	ProductID uint            `json:"product_id"`
+	At        time.Time       `json:"at"`
+	Price     decimal.Decimal `json:"price" swaggertype:"string" example:"19.99"`
+	Currency  string          `json:"currency" example:"USD"`
+	Since     time.Time       `json:"since"`
+	Scheduled bool            `json:"scheduled"`
+}
+
+// Record appends the current price of product to its history as of
+// effectiveAt
+func Record(tx repository.Repositories, product *models.Product, effectiveAt time.Time, source string, actorID *uint) error {
+	return tx.PriceHistory.Create(&models.PriceChange{
+		ProductID:   product.ID,
+		Price:       product.Price,
+		Currency:    product.Currency,
+		EffectiveAt: effectiveAt,
+		Source:      source,
+		ActorID:     actorID,
+	})
+}
+
+// ApplyDue switches a product to each of its pending schedules that is due
+// at now, in the order they fell due, and records every one in the history
+// at the time it was scheduled for. It returns how many were applied. The
+// schedules of a product in the trash stay pending until it is restored.
+func ApplyDue(repos repository.Repositories, productID uint, now time.Time) (int, error) {
+	var applied int
+	err := repos.Transaction(func(tx repository.Repositories) error {
+		applied = 0
+		if err := tx.Products.Lock(productID); err != nil {
+			return err
+		}
+		product, err := tx.Products.FindByID(productID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		schedules, err := tx.Schedules.FindByProduct(productID)
+		if err != nil {
+			return err
+		}
+		for _, schedule := range schedules {
+			if schedule.Status != models.SchedulePending || schedule.EffectiveAt.After(now) {
+				continue
+			}
+			if err := tx.Schedules.SetStatus(schedule.ID, models.SchedulePending, models.ScheduleApplied); err != nil {
+				return err
+			}
+			product.Price = schedule.Price
+			if err := Record(tx, product, schedule.EffectiveAt, models.PriceSchedule, schedule.ActorID); err != nil {
+				return err
+			}
+			applied++
+		}
+
+		if applied == 0 {
+			return nil
+		}
+		return tx.Products.Update(product)
+	})
+	return applied, err
+}
+
+// At returns the price of a product at the given time. Times in the past
+// are answered from the history; pending schedules that are due by then
+// take precedence over anything recorded before them, so future times see
+// the prices already scheduled. It returns repository.ErrNotFound when the
+// product had no price yet.
+func At(repos repository.Repositories, product *models.Product, at time.Time) (*Effective, error) {
+	var effective *Effective
+
+	change, err := repos.PriceHistory.FindAt(product.ID, at)
+	switch {
+	case err == nil:
+		effective = &Effective{
+			Price:    change.Price,
+			Currency: change.Currency,
+			Since:    change.EffectiveAt,
+		}
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	}
+
+	schedules, err := repos.Schedules.FindByProduct(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range schedules {
+		if schedule.Status != models.SchedulePending || schedule.EffectiveAt.After(at) {
+			continue
+		}
+		if effective == nil || !schedule.EffectiveAt.Before(effective.Since) {
+			effective = &Effective{
+				Price:     schedule.Price,
+				Currency:  product.Currency,
+				Since:     schedule.EffectiveAt,
+				Scheduled: true,
+			}
+		}
+	}
+
+	if effective == nil {
+		return nil, repository.ErrNotFound
+	}
+	effective.ProductID = product.ID
+	effective.At = at
+	return effective, nil
+}
@@ -0,0 +1,183 @@
+package pricing
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
+)
+
+// maxRegionLength bounds the length of a tax region code
+const maxRegionLength = 16
+
+var hundred = decimal.NewFromInt(100)
+
+// NormalizeRegion returns a tax region code in the form it is stored in.
+// Regions are matched case-insensitively.
+func NormalizeRegion(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
+
+// ValidRegion reports whether region has the shape of a region code: up
+// to 16 letters, digits and hyphens, such as "DE" or "US-CA"
+func ValidRegion(region string) bool {
+	if region == "" || len(region) > maxRegionLength {
+		return false
+	}
+	for _, r := range region {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// TaxedAmount is an amount taxed at Rate percent
+type TaxedAmount struct {
+	Amount decimal.Decimal
+	Rate   decimal.Decimal
+}
+
+// Taxer works out the tax on prices in one region. Prices either already
+// include the tax or have it added on top.
+type Taxer struct {
+	inclusive bool
+	rates     map[uint]decimal.Decimal
+	classes   map[uint]*uint
+	parents   map[uint]*uint
+	converter currency.Converter
+}
+
+// NewTaxer reads the tax rates of region. Tax amounts are rounded to the
+// decimals of their currency with converter.
+func NewTaxer(repos repository.Repositories, converter currency.Converter, region string, inclusive bool) (*Taxer, error) {
+	rates, err := repos.Taxes.FindRatesByRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := repos.Categories.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Taxer{
+		inclusive: inclusive,
+		rates:     make(map[uint]decimal.Decimal, len(rates)),
+		classes:   make(map[uint]*uint, len(categories)),
+		parents:   make(map[uint]*uint, len(categories)),
+		converter: converter,
+	}
+	for _, rate := range rates {
+		t.rates[rate.TaxClassID] = rate.Rate
+	}
+	for _, category := range categories {
+		t.classes[category.ID] = category.TaxClassID
+		t.parents[category.ID] = category.ParentID
+	}
+	return t, nil
+}
+
+// Rate returns the percentage product is taxed at. That is the rate of
+// its own tax class or, failing that, of the nearest category up its tree
+// that has a class. Products without a class, or whose class has no rate
+// in the region, are not taxed.
+func (t *Taxer) Rate(product models.Product) decimal.Decimal {
+	classID := product.TaxClassID
+	categoryID := product.CategoryID
+	seen := make(map[uint]bool)
+	for classID == nil && categoryID != nil && !seen[*categoryID] {
+		seen[*categoryID] = true
+		classID = t.classes[*categoryID]
+		categoryID = t.parents[*categoryID]
+	}
+
+	if classID == nil {
+		return decimal.Zero
+	}
+	if rate, ok := t.rates[*classID]; ok {
+		return rate
+	}
+	return decimal.Zero
+}
+
+// Split breaks amount, in currency code, into net, tax and gross at rate
+// percent. The tax is rounded; net or gross, whichever amount is not
+// given, takes up the difference.
+func (t *Taxer) Split(amount, rate decimal.Decimal, code string) models.TaxBreakdown {
+	decimals := t.converter.Decimals(code)
+	amount = amount.Round(decimals)
+	if t.inclusive {
+		tax := amount.Mul(rate).Div(hundred.Add(rate), decimals)
+		return models.TaxBreakdown{Rate: rate, Net: amount.Sub(tax), Tax: tax, Gross: amount}
+	}
+	tax := amount.Mul(rate).Div(hundred, decimals)
+	return models.TaxBreakdown{Rate: rate, Net: amount, Tax: tax, Gross: amount.Add(tax)}
+}
+
+// Apply fills in the Tax of product from its EffectivePrice, or from its
+// Price when discounts were not applied
+func (t *Taxer) Apply(product *models.Product) {
+	price := product.Price
+	if product.EffectivePrice != nil {
+		price = *product.EffectivePrice
+	}
+	tax := t.Split(price, t.Rate(*product), product.Currency)
+	product.Tax = &tax
+}
+
+// Summarize works out the tax on amounts in currency code, less discount,
+// at each rate they are taxed at, lowest rate first. The discount is
+// shared out over the rates in proportion to their amounts; what rounding
+// leaves over goes to the highest rate.
+func (t *Taxer) Summarize(amounts []TaxedAmount, discount decimal.Decimal, code string) []models.TaxBreakdown {
+	var groups []TaxedAmount
+	subtotal := decimal.Zero
+	for _, amount := range amounts {
+		subtotal = subtotal.Add(amount.Amount)
+		i := slices.IndexFunc(groups, func(group TaxedAmount) bool {
+			return group.Rate.Equal(amount.Rate)
+		})
+		if i < 0 {
+			groups = append(groups, amount)
+			continue
+		}
+		groups[i].Amount = groups[i].Amount.Add(amount.Amount)
+	}
+	slices.SortFunc(groups, func(a, b TaxedAmount) int {
+		return a.Rate.Cmp(b.Rate)
+	})
+
+	decimals := t.converter.Decimals(code)
+	remaining := discount
+	breakdowns := make([]models.TaxBreakdown, 0, len(groups))
+	for i, group := range groups {
+		share := remaining
+		if i < len(groups)-1 {
+			share = decimal.Zero
+			if subtotal.Sign() > 0 {
+				share = discount.Mul(group.Amount).Div(subtotal, decimals)
+			}
+		}
+		if share.GreaterThan(group.Amount) {
+			share = group.Amount
+		}
+		remaining = remaining.Sub(share)
+		breakdowns = append(breakdowns, t.Split(group.Amount.Sub(share), group.Rate, code))
+	}
+	return breakdowns
+}
+
+// Total adds breakdowns in currency code up into one. Its Rate is zero.
+func (t *Taxer) Total(breakdowns []models.TaxBreakdown, code string) models.TaxBreakdown {
+	zero := t.converter.Round(decimal.Zero, code)
+	total := models.TaxBreakdown{Rate: decimal.Zero, Net: zero, Tax: zero, Gross: zero}
+	for _, breakdown := range breakdowns {
+		total.Net = total.Net.Add(breakdown.Net)
+		total.Tax = total.Tax.Add(breakdown.Tax)
+		total.Gross = total.Gross.Add(breakdown.Gross)
+	}
+	return total
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormCartRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCartRepository returns a CartRepository backed by GORM
+func NewGormCartRepository(db *gorm.DB) CartRepository {
+	return &gormCartRepository{db: db}
+}
+
+func (r *gormCartRepository) FindByUser(userID uint) ([]models.CartItem, error) {
+	items := []models.CartItem{}
+	err := r.db.
+		Where("user_id = ? AND product_id IN (SELECT id FROM products WHERE deleted_at IS NULL)", userID).
+		Where("variant_id IS NULL OR variant_id IN (SELECT id FROM variants WHERE deleted_at IS NULL)").
+		Order("id").Find(&items).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return items, nil
+}
+
+func (r *gormCartRepository) FindItem(userID, productID, variantID uint) (*models.CartItem, error) {
+	var item models.CartItem
+	if err := whereCartItem(r.db, userID, productID, variantID).First(&item).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &item, nil
+}
+
+func (r *gormCartRepository) Set(item *models.CartItem) error {
+	existing, err := r.FindItem(item.UserID, item.ProductID, variantKey(item.VariantID))
+	if errors.Is(err, ErrNotFound) {
+		item.ID = 0
+		return translateError(r.db.Create(item).Error)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.Model(existing).Update("quantity", item.Quantity).Error; err != nil {
+		return translateError(err)
+	}
+	item.ID = existing.ID
+	item.CreatedAt = existing.CreatedAt
+	item.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+func (r *gormCartRepository) Remove(userID, productID, variantID uint) error {
+	result := whereCartItem(r.db, userID, productID, variantID).Delete(&models.CartItem{})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormCartRepository) Clear(userID uint) error {
+	return translateError(r.db.Where("user_id = ?", userID).Delete(&models.CartItem{}).Error)
+}
+
+// whereCartItem narrows db to the user's item for a product, or for one of
+// its variants when variantID is not 0
+func whereCartItem(db *gorm.DB, userID, productID, variantID uint) *gorm.DB {
+	db = db.Where("user_id = ? AND product_id = ?", userID, productID)
+	if variantID == 0 {
+		return db.Where("variant_id IS NULL")
+	}
+	return db.Where("variant_id = ?", variantID)
+}
+
+// variantKey returns the variantID a cart item with variantID is found by
+func variantKey(variantID *uint) uint {
+	if variantID == nil {
+		return 0
+	}
+	return *variantID
+}
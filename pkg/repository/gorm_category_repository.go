@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormCategoryRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCategoryRepository returns a CategoryRepository backed by GORM
+func NewGormCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &gormCategoryRepository{db: db}
+}
+
+func (r *gormCategoryRepository) Create(category *models.Category) error {
+	category.Version = 1
+	return translateError(r.db.Create(category).Error)
+}
+
+func (r *gormCategoryRepository) FindAll() ([]models.Category, error) {
+	var categories []models.Category
+	if err := r.db.Find(&categories).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return categories, nil
+}
+
+func (r *gormCategoryRepository) FindByID(id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.First(&category, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &category, nil
+}
+
+func (r *gormCategoryRepository) FindByName(name string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("name = ?", name).First(&category).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &category, nil
+}
+
+func (r *gormCategoryRepository) Update(category *models.Category) error {
+	return versionedUpdate(r.db, category, &category.Model)
+}
+
+func (r *gormCategoryRepository) Delete(id uint, version uint) error {
+	return versionedDelete(r.db, &models.Category{}, id, version)
+}
+
+func (r *gormCategoryRepository) PurgeDeleted(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&models.Category{})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+func (r *gormCategoryRepository) Path(id uint) ([]models.Category, error) {
+	var path []models.Category
+	seen := make(map[uint]bool)
+	next := &id
+	for next != nil && !seen[*next] {
+		var category models.Category
+		err := r.db.First(&category, *next).Error
+		if err != nil {
+			if len(path) > 0 && errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, translateError(err)
+		}
+		seen[category.ID] = true
+		path = append(path, category)
+		next = category.ParentID
+	}
+
+	slices.Reverse(path)
+	return path, nil
+}
+
+func (r *gormCategoryRepository) SubtreeIDs(id uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Raw(`WITH RECURSIVE subtree (id) AS (
+    SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+    UNION
+    SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+    WHERE c.deleted_at IS NULL
+)
+SELECT id FROM subtree ORDER BY id`, id).Scan(&ids).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if len(ids) == 0 {
+		return nil, ErrNotFound
+	}
+	return ids, nil
+}
+
+func (r *gormCategoryRepository) CountChildren(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, translateError(err)
+}
+
+// Lock takes row locks in ID order so concurrent callers cannot deadlock.
+// SQLite ignores the locking clause; its writers are serialized anyway.
+func (r *gormCategoryRepository) Lock(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var categories []models.Category
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).Order("id").
+		Find(&categories).Error
+	return translateError(err)
+}
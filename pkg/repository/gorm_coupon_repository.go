@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormCouponRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCouponRepository returns a CouponRepository backed by GORM
+func NewGormCouponRepository(db *gorm.DB) CouponRepository {
+	return &gormCouponRepository{db: db}
+}
+
+func (r *gormCouponRepository) Create(coupon *models.Coupon) error {
+	coupon.Version = 1
+	return translateError(r.db.Create(coupon).Error)
+}
+
+func (r *gormCouponRepository) FindAll() ([]models.Coupon, error) {
+	coupons := []models.Coupon{}
+	if err := r.db.Order("id").Find(&coupons).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return coupons, nil
+}
+
+func (r *gormCouponRepository) FindByID(id uint) (*models.Coupon, error) {
+	var coupon models.Coupon
+	if err := r.db.First(&coupon, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &coupon, nil
+}
+
+func (r *gormCouponRepository) FindByCode(code string) (*models.Coupon, error) {
+	var coupon models.Coupon
+	if err := r.db.Where("code = ?", code).First(&coupon).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &coupon, nil
+}
+
+func (r *gormCouponRepository) Update(coupon *models.Coupon) error {
+	return versionedUpdate(r.db, coupon, &coupon.Model)
+}
+
+func (r *gormCouponRepository) Delete(id uint, version uint) error {
+	return versionedDelete(r.db, &models.Coupon{}, id, version)
+}
+
+func (r *gormCouponRepository) PurgeDeleted(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&models.Coupon{})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// Lock takes row locks in ID order so concurrent callers cannot deadlock.
+// SQLite ignores the locking clause; its writers are serialized anyway.
+func (r *gormCouponRepository) Lock(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var coupons []models.Coupon
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).Order("id").
+		Find(&coupons).Error
+	return translateError(err)
+}
+
+// Redeem relies on a conditional update, so two callers racing for the
+// last use of a coupon cannot both get it even without a lock
+func (r *gormCouponRepository) Redeem(redemption *models.CouponRedemption) error {
+	result := r.db.Model(&models.Coupon{}).
+		Where("id = ? AND (usage_limit IS NULL OR redeemed < usage_limit)", redemption.CouponID).
+		Updates(map[string]interface{}{
+			"redeemed": gorm.Expr("redeemed + 1"),
+			"version":  gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.FindByID(redemption.CouponID); err != nil {
+			return err
+		}
+		return ErrLimitReached
+	}
+	return translateError(r.db.Create(redemption).Error)
+}
+
+func (r *gormCouponRepository) LinkRedemption(id, orderID uint) error {
+	result := r.db.Model(&models.CouponRedemption{}).Where("id = ?", id).Update("order_id", orderID)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormCouponRepository) ReleaseRedemption(orderID uint) error {
+	var redemption models.CouponRedemption
+	err := translateError(r.db.Where("order_id = ? AND released_at IS NULL", orderID).First(&redemption).Error)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	result := r.db.Model(&redemption).Where("released_at IS NULL").Update("released_at", time.Now())
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+	err = r.db.Unscoped().Model(&models.Coupon{}).
+		Where("id = ? AND redeemed > 0", redemption.CouponID).
+		Updates(map[string]interface{}{
+			"redeemed": gorm.Expr("redeemed - 1"),
+			"version":  gorm.Expr("version + 1"),
+		}).Error
+	return translateError(err)
+}
+
+func (r *gormCouponRepository) CountRedemptions(couponID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ? AND released_at IS NULL", couponID, userID).
+		Count(&count).Error
+	return count, translateError(err)
+}
+
+func (r *gormCouponRepository) FindRedemptions(couponID uint) ([]models.CouponRedemption, error) {
+	redemptions := []models.CouponRedemption{}
+	if err := r.db.Where("coupon_id = ?", couponID).Order("id").Find(&redemptions).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return redemptions, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormCurrencyRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCurrencyRepository returns a CurrencyRepository backed by GORM
+func NewGormCurrencyRepository(db *gorm.DB) CurrencyRepository {
+	return &gormCurrencyRepository{db: db}
+}
+
+func (r *gormCurrencyRepository) FindRates() ([]models.ExchangeRate, error) {
+	rates := []models.ExchangeRate{}
+	if err := r.db.Order("currency").Find(&rates).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return rates, nil
+}
+
+func (r *gormCurrencyRepository) FindRate(currency string) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	if err := r.db.Where("currency = ?", currency).Take(&rate).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &rate, nil
+}
+
+func (r *gormCurrencyRepository) SaveRate(rate *models.ExchangeRate) error {
+	return translateError(r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(rate).Error)
+}
+
+func (r *gormCurrencyRepository) DeleteRate(currency string) error {
+	result := r.db.Where("currency = ?", currency).Delete(&models.ExchangeRate{})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormCurrencyRepository) FindPrices(productIDs []uint) ([]models.ProductPrice, error) {
+	prices := []models.ProductPrice{}
+	err := r.db.Where("product_id IN ?", productIDs).
+		Order("product_id, currency").
+		Find(&prices).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return prices, nil
+}
+
+func (r *gormCurrencyRepository) SavePrice(price *models.ProductPrice) error {
+	return translateError(r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(price).Error)
+}
+
+func (r *gormCurrencyRepository) DeletePrice(productID uint, currency string) error {
+	result := r.db.Where("product_id = ? AND currency = ?", productID, currency).Delete(&models.ProductPrice{})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormDiscountRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewGormDiscountRuleRepository returns a DiscountRuleRepository backed by GORM
+func NewGormDiscountRuleRepository(db *gorm.DB) DiscountRuleRepository {
+	return &gormDiscountRuleRepository{db: db}
+}
+
+func (r *gormDiscountRuleRepository) Create(rule *models.DiscountRule) error {
+	rule.Version = 1
+	return translateError(r.db.Create(rule).Error)
+}
+
+func (r *gormDiscountRuleRepository) FindAll() ([]models.DiscountRule, error) {
+	rules := []models.DiscountRule{}
+	if err := r.db.Order("id").Find(&rules).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return rules, nil
+}
+
+func (r *gormDiscountRuleRepository) FindByID(id uint) (*models.DiscountRule, error) {
+	var rule models.DiscountRule
+	if err := r.db.First(&rule, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &rule, nil
+}
+
+func (r *gormDiscountRuleRepository) Update(rule *models.DiscountRule) error {
+	return versionedUpdate(r.db, rule, &rule.Model)
+}
+
+func (r *gormDiscountRuleRepository) Delete(id uint, version uint) error {
+	return versionedDelete(r.db, &models.DiscountRule{}, id, version)
+}
+
+func (r *gormDiscountRuleRepository) PurgeDeleted(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&models.DiscountRule{})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+func (r *gormDiscountRuleRepository) FindInForce(at time.Time) ([]models.DiscountRule, error) {
+	rules := []models.DiscountRule{}
+	err := r.db.
+		Where("starts_at IS NULL OR starts_at <= ?", at).
+		Where("ends_at IS NULL OR ends_at > ?", at).
+		Order("priority DESC, id").
+		Find(&rules).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rules, nil
+}
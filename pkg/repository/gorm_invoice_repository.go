@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormInvoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewGormInvoiceRepository returns an InvoiceRepository backed by GORM
+func NewGormInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &gormInvoiceRepository{db: db}
+}
+
+// Issue draws the number from the invoices row of the sequences table.
+// The row stays locked until the invoice is saved, and a failed save
+// puts the number back.
+func (r *gormInvoiceRepository) Issue(invoice *models.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE sequences SET value = value + 1 WHERE name = ?", "invoices").Error; err != nil {
+			return translateError(err)
+		}
+		if err := tx.Raw("SELECT value FROM sequences WHERE name = ?", "invoices").Scan(&invoice.Number).Error; err != nil {
+			return translateError(err)
+		}
+		return translateError(tx.Create(invoice).Error)
+	})
+}
+
+func (r *gormInvoiceRepository) FindByOrder(orderID uint) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := r.db.Where("order_id = ?", orderID).First(&invoice).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &invoice, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewGormOrderRepository returns an OrderRepository backed by GORM
+func NewGormOrderRepository(db *gorm.DB) OrderRepository {
+	return &gormOrderRepository{db: db}
+}
+
+func (r *gormOrderRepository) Create(order *models.Order) error {
+	return translateError(r.db.Create(order).Error)
+}
+
+func (r *gormOrderRepository) FindByID(id uint) (*models.Order, error) {
+	var order models.Order
+	if err := r.withItems().First(&order, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &order, nil
+}
+
+func (r *gormOrderRepository) FindByUser(userID uint) ([]models.Order, error) {
+	orders := []models.Order{}
+	if err := r.withItems().Where("user_id = ?", userID).Order("id DESC").Find(&orders).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return orders, nil
+}
+
+// Lock takes row locks in ID order so concurrent callers cannot deadlock.
+// SQLite ignores the locking clause; its writers are serialized anyway.
+func (r *gormOrderRepository) Lock(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var orders []models.Order
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).Order("id").
+		Find(&orders).Error
+	return translateError(err)
+}
+
+func (r *gormOrderRepository) SetStatus(id uint, from, to string) error {
+	result := r.db.Model(&models.Order{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return missingOrStale(r.db, &models.Order{}, id)
+	}
+	return nil
+}
+
+func (r *gormOrderRepository) CreateTransition(transition *models.OrderTransition) error {
+	return translateError(r.db.Create(transition).Error)
+}
+
+func (r *gormOrderRepository) FindTransitions(orderID uint) ([]models.OrderTransition, error) {
+	transitions := []models.OrderTransition{}
+	if err := r.db.Where("order_id = ?", orderID).Order("id").Find(&transitions).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return transitions, nil
+}
+
+// withItems loads the items and taxes of every order found, in the order
+// they were added
+func (r *gormOrderRepository) withItems() *gorm.DB {
+	return r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Preload("Taxes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	})
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormPaymentRepository struct {
+	db *gorm.DB
+}
+
+// NewGormPaymentRepository returns a PaymentRepository backed by GORM
+func NewGormPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &gormPaymentRepository{db: db}
+}
+
+func (r *gormPaymentRepository) Create(payment *models.Payment) error {
+	return translateError(r.db.Create(payment).Error)
+}
+
+func (r *gormPaymentRepository) FindByOrder(orderID uint) ([]models.Payment, error) {
+	payments := []models.Payment{}
+	if err := r.db.Where("order_id = ?", orderID).Order("id").Find(&payments).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return payments, nil
+}
+
+func (r *gormPaymentRepository) FindByIntent(provider, intentID string) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.Where("provider = ? AND intent_id = ?", provider, intentID).First(&payment).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &payment, nil
+}
+
+func (r *gormPaymentRepository) SetStatus(id uint, from, to string) error {
+	result := r.db.Model(&models.Payment{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return missingOrStale(r.db, &models.Payment{}, id)
+	}
+	return nil
+}
+
+func (r *gormPaymentRepository) RecordEvent(event *models.PaymentEvent) error {
+	return translateError(r.db.Create(event).Error)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormPriceHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewGormPriceHistoryRepository returns a PriceHistoryRepository backed by GORM
+func NewGormPriceHistoryRepository(db *gorm.DB) PriceHistoryRepository {
+	return &gormPriceHistoryRepository{db: db}
+}
+
+func (r *gormPriceHistoryRepository) Create(change *models.PriceChange) error {
+	return translateError(r.db.Create(change).Error)
+}
+
+func (r *gormPriceHistoryRepository) FindByProduct(productID uint) ([]models.PriceChange, error) {
+	changes := []models.PriceChange{}
+	err := r.db.Where("product_id = ?", productID).
+		Order("effective_at, id").
+		Find(&changes).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return changes, nil
+}
+
+func (r *gormPriceHistoryRepository) FindAt(productID uint, at time.Time) (*models.PriceChange, error) {
+	var change models.PriceChange
+	err := r.db.Where("product_id = ? AND effective_at <= ?", productID, at).
+		Order("effective_at DESC, id DESC").
+		First(&change).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &change, nil
+}
+
+type gormPriceScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewGormPriceScheduleRepository returns a PriceScheduleRepository backed by GORM
+func NewGormPriceScheduleRepository(db *gorm.DB) PriceScheduleRepository {
+	return &gormPriceScheduleRepository{db: db}
+}
+
+func (r *gormPriceScheduleRepository) Create(schedule *models.ScheduledPrice) error {
+	return translateError(r.db.Create(schedule).Error)
+}
+
+func (r *gormPriceScheduleRepository) FindByID(id uint) (*models.ScheduledPrice, error) {
+	var schedule models.ScheduledPrice
+	if err := r.db.First(&schedule, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &schedule, nil
+}
+
+func (r *gormPriceScheduleRepository) FindByProduct(productID uint) ([]models.ScheduledPrice, error) {
+	schedules := []models.ScheduledPrice{}
+	err := r.db.Where("product_id = ?", productID).
+		Order("effective_at, id").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return schedules, nil
+}
+
+func (r *gormPriceScheduleRepository) SetStatus(id uint, from, to string) error {
+	result := r.db.Model(&models.ScheduledPrice{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return missingOrStale(r.db, &models.ScheduledPrice{}, id)
+	}
+	return nil
+}
+
+func (r *gormPriceScheduleRepository) FindDue(now time.Time) ([]models.ScheduledPrice, error) {
+	schedules := []models.ScheduledPrice{}
+	err := r.db.
+		Where("status = ? AND effective_at <= ?", models.SchedulePending, now).
+		Where("product_id IN (SELECT id FROM products WHERE deleted_at IS NULL)").
+		Order("effective_at, id").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return schedules, nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormProductRepository struct {
+	db *gorm.DB
+}
+
+// NewGormProductRepository returns a ProductRepository backed by GORM
+func NewGormProductRepository(db *gorm.DB) ProductRepository {
+	return &gormProductRepository{db: db}
+}
+
+func (r *gormProductRepository) Create(product *models.Product) error {
+	product.Version = 1
+	return translateError(r.db.Omit(clause.Associations).Create(product).Error)
+}
+
+func (r *gormProductRepository) FindAll(filter ProductFilter) ([]models.Product, error) {
+	query := r.db
+	if filter.CategoryIDs != nil {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+
+	var products []models.Product
+	if err := query.Find(&products).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return products, nil
+}
+
+func (r *gormProductRepository) FindByID(id uint) (*models.Product, error) {
+	var product models.Product
+	if err := r.db.First(&product, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &product, nil
+}
+
+func (r *gormProductRepository) FindByName(name string) (*models.Product, error) {
+	var product models.Product
+	if err := r.db.Where("name = ?", name).First(&product).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &product, nil
+}
+
+func (r *gormProductRepository) Update(product *models.Product) error {
+	return versionedUpdate(r.db, product, &product.Model)
+}
+
+func (r *gormProductRepository) Delete(id uint, version uint) error {
+	return versionedDelete(r.db, &models.Product{}, id, version)
+}
+
+func (r *gormProductRepository) FindDeleted() ([]models.Product, error) {
+	var products []models.Product
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").Find(&products).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return products, nil
+}
+
+func (r *gormProductRepository) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&models.Product{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormProductRepository) PurgeDeleted(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&models.Product{})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+func (r *gormProductRepository) CountByCategory(categoryID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Product{}).Where("category_id = ?", categoryID).Count(&count).Error
+	return count, translateError(err)
+}
+
+func (r *gormProductRepository) ReassignCategory(fromID, toID uint) (int64, error) {
+	result := r.db.Model(&models.Product{}).
+		Where("category_id = ?", fromID).
+		Updates(map[string]interface{}{
+			"category_id": toID,
+			"version":     gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+func (r *gormProductRepository) DeleteByCategory(categoryID uint) (int64, error) {
+	result := r.db.Where("category_id = ?", categoryID).Delete(&models.Product{})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+func (r *gormProductRepository) AdjustQty(id uint, delta int) (int, error) {
+	result := r.db.Model(&models.Product{}).
+		Where("id = ? AND qty + ? >= 0", id, delta).
+		Updates(map[string]interface{}{
+			"qty":     gorm.Expr("qty + ?", delta),
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return 0, translateError(result.Error)
+	}
+
+	var product models.Product
+	if err := r.db.Select("qty").First(&product, id).Error; err != nil {
+		return 0, translateError(err)
+	}
+	if result.RowsAffected == 0 {
+		return product.Qty, ErrInsufficientStock
+	}
+	return product.Qty, nil
+}
+
+func (r *gormProductRepository) Restock(id uint, delta int) (int, error) {
+	result := r.db.Unscoped().Model(&models.Product{}).
+		Where("id = ? AND qty + ? >= 0", id, delta).
+		Updates(map[string]interface{}{
+			"qty":     gorm.Expr("qty + ?", delta),
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return 0, translateError(result.Error)
+	}
+
+	var product models.Product
+	if err := r.db.Unscoped().Select("qty").First(&product, id).Error; err != nil {
+		return 0, translateError(err)
+	}
+	if result.RowsAffected == 0 {
+		return product.Qty, ErrInsufficientStock
+	}
+	return product.Qty, nil
+}
+
+// Lock takes row locks in ID order so concurrent callers cannot deadlock.
+// SQLite ignores the locking clause; its writers are serialized anyway.
+func (r *gormProductRepository) Lock(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var products []models.Product
+	// Trashed products are locked too, since their rating still changes
+	err := r.db.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).Order("id").
+		Find(&products).Error
+	return translateError(err)
+}
+
+func (r *gormProductRepository) CountByCurrency(currency string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Product{}).Where("currency = ?", currency).Count(&count).Error
+	return count, translateError(err)
+}
+
+func (r *gormProductRepository) SetRating(id uint, average decimal.Decimal, count int) error {
+	result := r.db.Unscoped().Model(&models.Product{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"rating_average": average,
+			"rating_count":   count,
+			"version":        gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
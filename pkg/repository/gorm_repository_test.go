@@ -0,0 +1,451 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/internal/db"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+// openGorm returns GORM repositories on a private in-memory SQLite
+// database with every migration applied
+func openGorm(t *testing.T) Repositories {
+	t.Helper()
+	conn, err := db.Open(config.Config{DBDriver: db.DriverSQLite, DBName: ":memory:", DBAutoMigrate: true})
+	if err != nil {
+		t.Fatalf("opening SQLite: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return NewGormRepositories(conn)
+}
+
+// createProduct stores a product named name and fails the test if it
+// cannot
+func createProduct(t *testing.T, repos Repositories, name string) *models.Product {
+	t.Helper()
+	product := &models.Product{Name: name, Qty: 5, Price: decimal.MustParse("19.99"), Currency: "USD"}
+	if err := repos.Products.Create(product); err != nil {
+		t.Fatalf("creating %s: %v", name, err)
+	}
+	return product
+}
+
+func TestGormProductVersions(t *testing.T) {
+	repos := openGorm(t)
+	product := createProduct(t, repos, "Lamp")
+	if product.Version != 1 {
+		t.Fatalf("got version %d for a new product, want 1", product.Version)
+	}
+
+	stale := *product
+	product.Qty = 6
+	if err := repos.Products.Update(product); err != nil {
+		t.Fatal(err)
+	}
+	if product.Version != 2 {
+		t.Fatalf("got version %d after an update, want 2", product.Version)
+	}
+
+	// The deletion time is not an updatable field
+	product.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	product.Qty = 6
+	if err := repos.Products.Update(product); err != nil {
+		t.Fatal(err)
+	}
+	if product.Version != 3 {
+		t.Fatalf("got version %d after a second update, want 3", product.Version)
+	}
+
+	stale.Qty = 7
+	if err := repos.Products.Update(&stale); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("got %v for a stale update, want ErrVersionConflict", err)
+	}
+	if stale.Version != 1 {
+		t.Fatalf("a failed update changed the version to %d", stale.Version)
+	}
+
+	stored, err := repos.Products.FindByID(product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Qty != 6 || stored.Version != 3 {
+		t.Fatalf("got qty %d at version %d, want qty 6 at version 3", stored.Qty, stored.Version)
+	}
+
+	qty, err := repos.Products.AdjustQty(product.ID, -2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qty != 4 {
+		t.Fatalf("got qty %d after taking 2 of 6, want 4", qty)
+	}
+	stored, err = repos.Products.FindByID(product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Version != 4 {
+		t.Fatalf("got version %d after adjusting the qty, want 4", stored.Version)
+	}
+}
+
+func TestGormProductDuplicates(t *testing.T) {
+	repos := openGorm(t)
+	createProduct(t, repos, "Lamp")
+	desk := createProduct(t, repos, "Desk")
+
+	duplicate := &models.Product{Name: "Lamp", Price: decimal.MustParse("1.00"), Currency: "USD"}
+	if err := repos.Products.Create(duplicate); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("got %v creating a second Lamp, want ErrDuplicate", err)
+	}
+
+	desk.Name = "Lamp"
+	if err := repos.Products.Update(desk); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("got %v renaming Desk to Lamp, want ErrDuplicate", err)
+	}
+
+	missing := uint(42)
+	orphan := &models.Product{Name: "Chair", Price: decimal.MustParse("1.00"), Currency: "USD", CategoryID: &missing}
+	if err := repos.Products.Create(orphan); !errors.Is(err, ErrInvalidReference) {
+		t.Fatalf("got %v creating a product in a missing category, want ErrInvalidReference", err)
+	}
+}
+
+func TestGormProductSoftDelete(t *testing.T) {
+	repos := openGorm(t)
+	product := createProduct(t, repos, "Lamp")
+
+	if err := repos.Products.Delete(product.ID, product.Version+1); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("got %v deleting a stale version, want ErrVersionConflict", err)
+	}
+	if err := repos.Products.Delete(product.ID, product.Version); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Products.FindByID(product.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v finding a trashed product, want ErrNotFound", err)
+	}
+	if err := repos.Products.Delete(product.ID, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v deleting a trashed product, want ErrNotFound", err)
+	}
+
+	trashed, err := repos.Products.FindDeleted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trashed) != 1 || trashed[0].ID != product.ID {
+		t.Fatalf("got trash %v, want only the Lamp", trashed)
+	}
+
+	// The unique index still covers the trashed product
+	duplicate := &models.Product{Name: "Lamp", Price: decimal.MustParse("1.00"), Currency: "USD"}
+	if err := repos.Products.Create(duplicate); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("got %v reusing the name of a trashed product, want ErrDuplicate", err)
+	}
+
+	if err := repos.Products.Restore(product.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Products.FindByID(product.ID); err != nil {
+		t.Fatalf("got %v finding a restored product", err)
+	}
+	if err := repos.Products.Restore(product.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v restoring a product outside the trash, want ErrNotFound", err)
+	}
+}
+
+// SQLite has no SELECT ... FOR UPDATE; the locks must still run there,
+// holding nothing beyond the transaction itself
+func TestGormLocks(t *testing.T) {
+	repos := openGorm(t)
+	lamp := createProduct(t, repos, "Lamp")
+	desk := createProduct(t, repos, "Desk")
+	if err := repos.Products.Delete(desk.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	category := &models.Category{Name: "Lighting"}
+	if err := repos.Categories.Create(category); err != nil {
+		t.Fatal(err)
+	}
+	warehouse := &models.Warehouse{Name: "Main"}
+	if err := repos.Warehouses.Create(warehouse); err != nil {
+		t.Fatal(err)
+	}
+
+	err := repos.Transaction(func(tx Repositories) error {
+		locks := []struct {
+			name string
+			lock func(ids ...uint) error
+			ids  []uint
+		}{
+			{"products", tx.Products.Lock, []uint{desk.ID, lamp.ID, 999}},
+			{"no products", tx.Products.Lock, nil},
+			{"categories", tx.Categories.Lock, []uint{category.ID}},
+			{"warehouses", tx.Warehouses.Lock, []uint{warehouse.ID}},
+			{"coupons", tx.Coupons.Lock, []uint{1}},
+			{"orders", tx.Orders.Lock, []uint{1}},
+		}
+		for _, lock := range locks {
+			if err := lock.lock(lock.ids...); err != nil {
+				t.Errorf("locking %s: %v", lock.name, err)
+			}
+		}
+
+		// A trashed product is locked too, since its rating still changes
+		return tx.Products.SetRating(desk.ID, decimal.MustParse("4.50"), 2)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trashed, err := repos.Products.FindDeleted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trashed) != 1 || trashed[0].RatingCount != 2 || !trashed[0].RatingAverage.Equal(decimal.MustParse("4.5")) {
+		t.Fatalf("got trash %v, want the Desk rated 4.5 twice", trashed)
+	}
+}
+
+func TestGormTransactionRollsBack(t *testing.T) {
+	repos := openGorm(t)
+	product := createProduct(t, repos, "Lamp")
+	failure := errors.New("changed my mind")
+
+	err := repos.Transaction(func(tx Repositories) error {
+		if err := tx.Products.Lock(product.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Products.AdjustQty(product.ID, 10); err != nil {
+			return err
+		}
+		if err := tx.Categories.Create(&models.Category{Name: "Lighting"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("got %v from the transaction, want the error it returned", err)
+	}
+
+	stored, err := repos.Products.FindByID(product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Qty != product.Qty || stored.Version != product.Version {
+		t.Fatalf("got qty %d at version %d after the rollback, want qty %d at version %d", stored.Qty, stored.Version, product.Qty, product.Version)
+	}
+	if _, err := repos.Categories.FindByName("Lighting"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v finding a category created in a rolled back transaction, want ErrNotFound", err)
+	}
+}
+
+func TestGormCouponRedemptions(t *testing.T) {
+	repos := openGorm(t)
+	user := &models.User{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Password: "x"}
+	if err := repos.Users.Create(user); err != nil {
+		t.Fatal(err)
+	}
+	limit := 1
+	coupon := &models.Coupon{Code: "ONCE", Type: models.DiscountPercentage, Value: decimal.MustParse("10"), Currency: "USD", UsageLimit: &limit}
+	if err := repos.Coupons.Create(coupon); err != nil {
+		t.Fatal(err)
+	}
+	order := &models.Order{UserID: &user.ID, Status: models.OrderPending, Currency: "USD", CouponID: &coupon.ID}
+	if err := repos.Orders.Create(order); err != nil {
+		t.Fatal(err)
+	}
+
+	redeem := func() (*models.CouponRedemption, error) {
+		redemption := &models.CouponRedemption{
+			CouponID:   coupon.ID,
+			UserID:     &user.ID,
+			OrderValue: decimal.MustParse("80.00"),
+			Discount:   decimal.MustParse("8.00"),
+			Currency:   "USD",
+		}
+		return redemption, repos.Coupons.Redeem(redemption)
+	}
+	redemption, err := redeem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := redeem(); !errors.Is(err, ErrLimitReached) {
+		t.Fatalf("got %v redeeming past the limit, want ErrLimitReached", err)
+	}
+
+	if err := repos.Coupons.LinkRedemption(redemption.ID+1, order.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v linking a missing redemption, want ErrNotFound", err)
+	}
+	if err := repos.Coupons.LinkRedemption(redemption.ID, order.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repos.Coupons.ReleaseRedemption(order.ID); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := repos.Coupons.FindByID(coupon.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Redeemed != 0 || stored.Version != 3 {
+		t.Fatalf("got %d redemptions at version %d after the release, want 0 at version 3", stored.Redeemed, stored.Version)
+	}
+	ledger, err := repos.Coupons.FindRedemptions(coupon.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger) != 1 || ledger[0].ReleasedAt == nil {
+		t.Fatalf("got ledger %v after the release, want the redemption kept and marked released", ledger)
+	}
+	if used, err := repos.Coupons.CountRedemptions(coupon.ID, user.ID); err != nil || used != 0 {
+		t.Fatalf("got %d, %v uses after the release, want 0", used, err)
+	}
+
+	// Releasing again, or an order without a coupon, changes nothing
+	if err := repos.Coupons.ReleaseRedemption(order.ID); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err = repos.Coupons.FindByID(coupon.ID); err != nil || stored.Version != 3 {
+		t.Fatalf("got %+v, %v after releasing twice, want the coupon left at version 3", stored, err)
+	}
+	if _, err := redeem(); err != nil {
+		t.Fatalf("got %v redeeming the use that was given back", err)
+	}
+}
+
+func TestGormCartVariants(t *testing.T) {
+	repos := openGorm(t)
+	user := &models.User{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Password: "x"}
+	if err := repos.Users.Create(user); err != nil {
+		t.Fatal(err)
+	}
+	shirt := createProduct(t, repos, "Shirt")
+	large := &models.Variant{ProductID: shirt.ID, SKU: "SHIRT-L", Options: models.VariantOptions{}, Qty: 3}
+	if err := repos.Variants.Create(large); err != nil {
+		t.Fatal(err)
+	}
+
+	// The product and its variant are separate lines, each set twice
+	for _, quantity := range []int{1, 2} {
+		items := []*models.CartItem{
+			{UserID: user.ID, ProductID: shirt.ID, Quantity: quantity},
+			{UserID: user.ID, ProductID: shirt.ID, VariantID: &large.ID, Quantity: quantity + 1},
+		}
+		for _, item := range items {
+			if err := repos.Carts.Set(item); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	items, err := repos.Carts.FindByUser(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].VariantID != nil || items[0].Quantity != 2 || items[1].VariantID == nil || items[1].Quantity != 3 {
+		t.Fatalf("got cart %+v, want 2 of the shirt and 3 of its variant", items)
+	}
+	item, err := repos.Carts.FindItem(user.ID, shirt.ID, large.ID)
+	if err != nil || item.Quantity != 3 {
+		t.Fatalf("got %+v, %v finding the variant's item, want 3 units", item, err)
+	}
+
+	// Items of a trashed variant are left out; removing one leaves the other
+	if err := repos.Variants.Delete(large.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if items, err = repos.Carts.FindByUser(user.ID); err != nil || len(items) != 1 || items[0].VariantID != nil {
+		t.Fatalf("got cart %+v, %v with the variant trashed, want the shirt alone", items, err)
+	}
+	if err := repos.Carts.Remove(user.ID, shirt.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Carts.FindItem(user.ID, shirt.ID, large.ID); err != nil {
+		t.Fatalf("got %v finding the variant's item after removing the shirt's, want it kept", err)
+	}
+	if err := repos.Carts.Remove(user.ID, shirt.ID, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v removing the shirt twice, want ErrNotFound", err)
+	}
+}
+
+func TestGormVariantAdjustQty(t *testing.T) {
+	repos := openGorm(t)
+	shirt := createProduct(t, repos, "Shirt")
+	large := &models.Variant{ProductID: shirt.ID, SKU: "SHIRT-L", Options: models.VariantOptions{}, Qty: 3}
+	if err := repos.Variants.Create(large); err != nil {
+		t.Fatal(err)
+	}
+
+	if qty, err := repos.Variants.AdjustQty(large.ID, -2); err != nil || qty != 1 {
+		t.Fatalf("got %d, %v taking 2 of 3, want 1", qty, err)
+	}
+	if qty, err := repos.Variants.AdjustQty(large.ID, -2); !errors.Is(err, ErrInsufficientStock) || qty != 1 {
+		t.Fatalf("got %d, %v taking 2 of 1, want 1 and ErrInsufficientStock", qty, err)
+	}
+	stored, err := repos.Variants.FindByID(large.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Qty != 1 || stored.Version != 2 {
+		t.Fatalf("got qty %d at version %d, want qty 1 at version 2", stored.Qty, stored.Version)
+	}
+}
+
+func TestGormRestockTrashed(t *testing.T) {
+	repos := openGorm(t)
+	shirt := createProduct(t, repos, "Shirt")
+	large := &models.Variant{ProductID: shirt.ID, SKU: "SHIRT-L", Options: models.VariantOptions{}, Qty: 3}
+	if err := repos.Variants.Create(large); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Variants.Delete(large.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Products.Delete(shirt.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repos.Products.AdjustQty(shirt.ID, 2); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v adjusting a trashed product, want ErrNotFound", err)
+	}
+	if qty, err := repos.Products.Restock(shirt.ID, 2); err != nil || qty != shirt.Qty+2 {
+		t.Fatalf("got %d, %v restocking a trashed product, want %d", qty, err, shirt.Qty+2)
+	}
+	if qty, err := repos.Variants.Restock(large.ID, 2); err != nil || qty != 5 {
+		t.Fatalf("got %d, %v restocking a trashed variant, want 5", qty, err)
+	}
+	if _, err := repos.Products.Restock(shirt.ID+1, 2); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v restocking a missing product, want ErrNotFound", err)
+	}
+}
+
+func TestGormReviewSetStatus(t *testing.T) {
+	repos := openGorm(t)
+	lamp := createProduct(t, repos, "Lamp")
+	review := &models.Review{ProductID: lamp.ID, Rating: 4, Title: "Bright", Status: models.ReviewPending}
+	if err := repos.Reviews.Create(review); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repos.Reviews.SetStatus(review.ID, models.ReviewApproved); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := repos.Reviews.FindByID(review.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != models.ReviewApproved || stored.Rating != 4 || stored.Title != "Bright" {
+		t.Fatalf("got %+v, want the review approved and otherwise unchanged", stored)
+	}
+	if err := repos.Reviews.SetStatus(review.ID+1, models.ReviewApproved); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v for a missing review, want ErrNotFound", err)
+	}
+}
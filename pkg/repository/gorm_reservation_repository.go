@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormReservationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormReservationRepository returns a ReservationRepository backed by GORM
+func NewGormReservationRepository(db *gorm.DB) ReservationRepository {
+	return &gormReservationRepository{db: db}
+}
+
+func (r *gormReservationRepository) Create(reservation *models.Reservation) error {
+	return translateError(r.db.Create(reservation).Error)
+}
+
+func (r *gormReservationRepository) FindByID(id uint) (*models.Reservation, error) {
+	var reservation models.Reservation
+	if err := r.db.First(&reservation, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &reservation, nil
+}
+
+func (r *gormReservationRepository) SetStatus(id uint, from, to string) error {
+	result := r.db.Model(&models.Reservation{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return missingOrStale(r.db, &models.Reservation{}, id)
+	}
+	return nil
+}
+
+func (r *gormReservationRepository) ReservedQty(productIDs []uint, now time.Time) (map[uint]int, error) {
+	var rows []struct {
+		ProductID uint
+		Quantity  int
+	}
+	err := r.db.Model(&models.Reservation{}).
+		Select("product_id, SUM(quantity) AS quantity").
+		Where("product_id IN ? AND status = ? AND expires_at > ?", productIDs, models.ReservationActive, now).
+		Group("product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	reserved := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		reserved[row.ProductID] = row.Quantity
+	}
+	return reserved, nil
+}
+
+func (r *gormReservationRepository) ExpireBefore(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Reservation{}).
+		Where("status = ? AND expires_at <= ?", models.ReservationActive, now).
+		Update("status", models.ReservationExpired)
+	return result.RowsAffected, translateError(result.Error)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewGormReviewRepository returns a ReviewRepository backed by GORM
+func NewGormReviewRepository(db *gorm.DB) ReviewRepository {
+	return &gormReviewRepository{db: db}
+}
+
+func (r *gormReviewRepository) Create(review *models.Review) error {
+	return translateError(r.db.Create(review).Error)
+}
+
+func (r *gormReviewRepository) Find(filter ReviewFilter, page, limit int) ([]models.Review, int64, error) {
+	var total int64
+	if err := r.filtered(filter).Count(&total).Error; err != nil {
+		return nil, 0, translateError(err)
+	}
+	reviews := []models.Review{}
+	err := r.filtered(filter).Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&reviews).Error
+	if err != nil {
+		return nil, 0, translateError(err)
+	}
+	return reviews, total, nil
+}
+
+func (r *gormReviewRepository) FindByID(id uint) (*models.Review, error) {
+	var review models.Review
+	if err := r.db.First(&review, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &review, nil
+}
+
+func (r *gormReviewRepository) Update(review *models.Review) error {
+	review.UpdatedAt = time.Now()
+	result := r.db.Model(&models.Review{}).
+		Where("id = ?", review.ID).
+		Updates(map[string]interface{}{
+			"rating":     review.Rating,
+			"title":      review.Title,
+			"body":       review.Body,
+			"status":     review.Status,
+			"updated_at": review.UpdatedAt,
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormReviewRepository) SetStatus(id uint, status string) error {
+	result := r.db.Model(&models.Review{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormReviewRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Review{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormReviewRepository) Stats(productID uint) (int, int, error) {
+	var stats struct {
+		Count int
+		Sum   int
+	}
+	err := r.db.Model(&models.Review{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(rating), 0) AS sum").
+		Where("product_id = ? AND status = ?", productID, models.ReviewApproved).
+		Scan(&stats).Error
+	if err != nil {
+		return 0, 0, translateError(err)
+	}
+	return stats.Count, stats.Sum, nil
+}
+
+// filtered starts a query for the reviews matching filter
+func (r *gormReviewRepository) filtered(filter ReviewFilter) *gorm.DB {
+	query := r.db.Model(&models.Review{})
+	if filter.ProductID != 0 {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return query
+}
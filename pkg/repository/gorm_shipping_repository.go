@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormShippingRepository struct {
+	db *gorm.DB
+}
+
+// NewGormShippingRepository returns a ShippingRepository backed by GORM
+func NewGormShippingRepository(db *gorm.DB) ShippingRepository {
+	return &gormShippingRepository{db: db}
+}
+
+func (r *gormShippingRepository) CreateZone(zone *models.ShippingZone) error {
+	zone.Methods = nil
+	if err := r.db.Create(zone).Error; err != nil {
+		return translateError(err)
+	}
+	zone.Methods = []models.ShippingMethod{}
+	return nil
+}
+
+func (r *gormShippingRepository) FindZones() ([]models.ShippingZone, error) {
+	zones := []models.ShippingZone{}
+	if err := r.withMethods().Order("id").Find(&zones).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return zones, nil
+}
+
+func (r *gormShippingRepository) FindZone(id uint) (*models.ShippingZone, error) {
+	var zone models.ShippingZone
+	if err := r.withMethods().First(&zone, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &zone, nil
+}
+
+func (r *gormShippingRepository) UpdateZone(zone *models.ShippingZone) error {
+	zone.UpdatedAt = time.Now()
+	result := r.db.Model(&models.ShippingZone{}).
+		Where("id = ?", zone.ID).
+		Updates(map[string]interface{}{"name": zone.Name, "regions": zone.Regions, "updated_at": zone.UpdatedAt})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormShippingRepository) DeleteZone(id uint) error {
+	result := r.db.Delete(&models.ShippingZone{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormShippingRepository) CreateMethod(method *models.ShippingMethod) error {
+	return translateError(r.db.Create(method).Error)
+}
+
+func (r *gormShippingRepository) FindMethod(id uint) (*models.ShippingMethod, error) {
+	var method models.ShippingMethod
+	if err := r.db.First(&method, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &method, nil
+}
+
+func (r *gormShippingRepository) UpdateMethod(method *models.ShippingMethod) error {
+	result := r.db.Model(method).
+		Select("*").Omit("id", "created_at", "zone_id").
+		Updates(method)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormShippingRepository) DeleteMethod(id uint) error {
+	result := r.db.Delete(&models.ShippingMethod{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// withMethods loads the methods of every zone found, ordered by ID
+func (r *gormShippingRepository) withMethods() *gorm.DB {
+	return r.db.Preload("Methods", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	})
+}
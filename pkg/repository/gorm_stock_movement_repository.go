@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormStockMovementRepository struct {
+	db *gorm.DB
+}
+
+// NewGormStockMovementRepository returns a StockMovementRepository backed by GORM
+func NewGormStockMovementRepository(db *gorm.DB) StockMovementRepository {
+	return &gormStockMovementRepository{db: db}
+}
+
+func (r *gormStockMovementRepository) Create(movement *models.StockMovement) error {
+	return translateError(r.db.Create(movement).Error)
+}
+
+func (r *gormStockMovementRepository) FindByProduct(productID uint) ([]models.StockMovement, error) {
+	movements := []models.StockMovement{}
+	if err := r.db.Where("product_id = ?", productID).Order("id").Find(&movements).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return movements, nil
+}
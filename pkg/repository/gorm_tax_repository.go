@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormTaxRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTaxRepository returns a TaxRepository backed by GORM
+func NewGormTaxRepository(db *gorm.DB) TaxRepository {
+	return &gormTaxRepository{db: db}
+}
+
+func (r *gormTaxRepository) CreateClass(class *models.TaxClass) error {
+	class.Rates = nil
+	if err := r.db.Create(class).Error; err != nil {
+		return translateError(err)
+	}
+	class.Rates = []models.TaxRate{}
+	return nil
+}
+
+func (r *gormTaxRepository) FindClasses() ([]models.TaxClass, error) {
+	classes := []models.TaxClass{}
+	if err := r.withRates().Order("id").Find(&classes).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return classes, nil
+}
+
+func (r *gormTaxRepository) FindClass(id uint) (*models.TaxClass, error) {
+	var class models.TaxClass
+	if err := r.withRates().First(&class, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &class, nil
+}
+
+func (r *gormTaxRepository) UpdateClass(class *models.TaxClass) error {
+	class.UpdatedAt = time.Now()
+	result := r.db.Model(&models.TaxClass{}).
+		Where("id = ?", class.ID).
+		Updates(map[string]interface{}{"name": class.Name, "updated_at": class.UpdatedAt})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormTaxRepository) DeleteClass(id uint) error {
+	result := r.db.Delete(&models.TaxClass{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormTaxRepository) SaveRate(rate *models.TaxRate) error {
+	return translateError(r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(rate).Error)
+}
+
+func (r *gormTaxRepository) DeleteRate(classID uint, region string) error {
+	result := r.db.Where("tax_class_id = ? AND region = ?", classID, region).Delete(&models.TaxRate{})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormTaxRepository) FindRatesByRegion(region string) ([]models.TaxRate, error) {
+	rates := []models.TaxRate{}
+	if err := r.db.Where("region = ?", region).Order("tax_class_id").Find(&rates).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return rates, nil
+}
+
+// withRates loads the rates of every class found, ordered by region
+func (r *gormTaxRepository) withRates() *gorm.DB {
+	return r.db.Preload("Rates", func(db *gorm.DB) *gorm.DB {
+		return db.Order("region")
+	})
+}
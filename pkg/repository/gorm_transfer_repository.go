@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormTransferRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTransferRepository returns a TransferRepository backed by GORM
+func NewGormTransferRepository(db *gorm.DB) TransferRepository {
+	return &gormTransferRepository{db: db}
+}
+
+func (r *gormTransferRepository) Create(transfer *models.Transfer) error {
+	return translateError(r.db.Create(transfer).Error)
+}
+
+func (r *gormTransferRepository) FindByID(id uint) (*models.Transfer, error) {
+	var transfer models.Transfer
+	if err := r.db.First(&transfer, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &transfer, nil
+}
+
+func (r *gormTransferRepository) FindByProduct(productID uint) ([]models.Transfer, error) {
+	transfers := []models.Transfer{}
+	if err := r.db.Where("product_id = ?", productID).Order("id").Find(&transfers).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return transfers, nil
+}
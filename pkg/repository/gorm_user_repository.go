@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserRepository returns a UserRepository backed by GORM
+func NewGormUserRepository(db *gorm.DB) UserRepository {
+	return &gormUserRepository{db: db}
+}
+
+func (r *gormUserRepository) Create(user *models.User) error {
+	user.Version = 1
+	return translateError(r.db.Create(user).Error)
+}
+
+func (r *gormUserRepository) FindAll() ([]models.User, error) {
+	var users []models.User
+	if err := r.db.Find(&users).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return users, nil
+}
+
+func (r *gormUserRepository) FindByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) FindByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) Update(user *models.User) error {
+	return versionedUpdate(r.db, user, &user.Model)
+}
+
+func (r *gormUserRepository) Delete(id uint, version uint) error {
+	return versionedDelete(r.db, &models.User{}, id, version)
+}
+
+func (r *gormUserRepository) PurgeDeleted(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&models.User{})
+	return result.RowsAffected, translateError(result.Error)
+}
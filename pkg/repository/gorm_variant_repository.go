@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormVariantRepository struct {
+	db *gorm.DB
+}
+
+// NewGormVariantRepository returns a VariantRepository backed by GORM
+func NewGormVariantRepository(db *gorm.DB) VariantRepository {
+	return &gormVariantRepository{db: db}
+}
+
+func (r *gormVariantRepository) Create(variant *models.Variant) error {
+	variant.Version = 1
+	return translateError(r.db.Create(variant).Error)
+}
+
+func (r *gormVariantRepository) FindByProduct(productID uint) ([]models.Variant, error) {
+	variants := []models.Variant{}
+	if err := r.db.Where("product_id = ?", productID).Order("id").Find(&variants).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return variants, nil
+}
+
+func (r *gormVariantRepository) FindByID(id uint) (*models.Variant, error) {
+	var variant models.Variant
+	if err := r.db.First(&variant, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &variant, nil
+}
+
+func (r *gormVariantRepository) FindBySKU(sku string) (*models.Variant, error) {
+	var variant models.Variant
+	if err := r.db.Where("sku = ?", sku).First(&variant).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &variant, nil
+}
+
+func (r *gormVariantRepository) Update(variant *models.Variant) error {
+	return versionedUpdate(r.db, variant, &variant.Model)
+}
+
+func (r *gormVariantRepository) AdjustQty(id uint, delta int) (int, error) {
+	result := r.db.Model(&models.Variant{}).
+		Where("id = ? AND qty + ? >= 0", id, delta).
+		Updates(map[string]interface{}{
+			"qty":     gorm.Expr("qty + ?", delta),
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return 0, translateError(result.Error)
+	}
+
+	var variant models.Variant
+	if err := r.db.Select("qty").First(&variant, id).Error; err != nil {
+		return 0, translateError(err)
+	}
+	if result.RowsAffected == 0 {
+		return variant.Qty, ErrInsufficientStock
+	}
+	return variant.Qty, nil
+}
+
+func (r *gormVariantRepository) Restock(id uint, delta int) (int, error) {
+	result := r.db.Unscoped().Model(&models.Variant{}).
+		Where("id = ? AND qty + ? >= 0", id, delta).
+		Updates(map[string]interface{}{
+			"qty":     gorm.Expr("qty + ?", delta),
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return 0, translateError(result.Error)
+	}
+
+	var variant models.Variant
+	if err := r.db.Unscoped().Select("qty").First(&variant, id).Error; err != nil {
+		return 0, translateError(err)
+	}
+	if result.RowsAffected == 0 {
+		return variant.Qty, ErrInsufficientStock
+	}
+	return variant.Qty, nil
+}
+
+func (r *gormVariantRepository) Delete(id uint, version uint) error {
+	return versionedDelete(r.db, &models.Variant{}, id, version)
+}
+
+func (r *gormVariantRepository) PurgeDeleted(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&models.Variant{})
+	return result.RowsAffected, translateError(result.Error)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormWarehouseRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWarehouseRepository returns a WarehouseRepository backed by GORM
+func NewGormWarehouseRepository(db *gorm.DB) WarehouseRepository {
+	return &gormWarehouseRepository{db: db}
+}
+
+func (r *gormWarehouseRepository) Create(warehouse *models.Warehouse) error {
+	warehouse.Version = 1
+	return translateError(r.db.Create(warehouse).Error)
+}
+
+func (r *gormWarehouseRepository) FindAll() ([]models.Warehouse, error) {
+	var warehouses []models.Warehouse
+	if err := r.db.Find(&warehouses).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return warehouses, nil
+}
+
+func (r *gormWarehouseRepository) FindByID(id uint) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	if err := r.db.First(&warehouse, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &warehouse, nil
+}
+
+func (r *gormWarehouseRepository) FindByName(name string) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	if err := r.db.Where("name = ?", name).First(&warehouse).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &warehouse, nil
+}
+
+func (r *gormWarehouseRepository) Update(warehouse *models.Warehouse) error {
+	return versionedUpdate(r.db, warehouse, &warehouse.Model)
+}
+
+func (r *gormWarehouseRepository) Delete(id uint, version uint) error {
+	return versionedDelete(r.db, &models.Warehouse{}, id, version)
+}
+
+func (r *gormWarehouseRepository) PurgeDeleted(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&models.Warehouse{})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// Lock takes row locks in ID order so concurrent callers cannot deadlock.
+// SQLite ignores the locking clause; its writers are serialized anyway.
+func (r *gormWarehouseRepository) Lock(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var warehouses []models.Warehouse
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).Order("id").
+		Find(&warehouses).Error
+	return translateError(err)
+}
+
+func (r *gormWarehouseRepository) FindLevels(productIDs []uint) ([]models.WarehouseStock, error) {
+	levels := []models.WarehouseStock{}
+	err := r.db.Where("product_id IN ?", productIDs).
+		Order("product_id, warehouse_id").
+		Find(&levels).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return levels, nil
+}
+
+func (r *gormWarehouseRepository) AdjustLevel(productID, warehouseID uint, delta int) (int, error) {
+	if delta > 0 {
+		err := r.db.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "product_id"}, {Name: "warehouse_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"qty":        gorm.Expr("warehouse_stocks.qty + ?", delta),
+				"updated_at": time.Now(),
+			}),
+		}).Create(&models.WarehouseStock{ProductID: productID, WarehouseID: warehouseID, Qty: delta}).Error
+		if err != nil {
+			return 0, translateError(err)
+		}
+	} else {
+		result := r.db.Model(&models.WarehouseStock{}).
+			Where("product_id = ? AND warehouse_id = ? AND qty + ? >= 0", productID, warehouseID, delta).
+			Update("qty", gorm.Expr("qty + ?", delta))
+		if result.Error != nil {
+			return 0, translateError(result.Error)
+		}
+		if result.RowsAffected == 0 && delta != 0 {
+			return 0, ErrInsufficientStock
+		}
+	}
+
+	var level models.WarehouseStock
+	err := r.db.Where("product_id = ? AND warehouse_id = ?", productID, warehouseID).Take(&level).Error
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return level.Qty, nil
+}
+
+func (r *gormWarehouseRepository) StockInWarehouse(warehouseID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.WarehouseStock{}).
+		Select("COALESCE(SUM(qty), 0)").
+		Where("warehouse_id = ?", warehouseID).
+		Scan(&total).Error
+	return total, translateError(err)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormWishlistRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWishlistRepository returns a WishlistRepository backed by GORM
+func NewGormWishlistRepository(db *gorm.DB) WishlistRepository {
+	return &gormWishlistRepository{db: db}
+}
+
+func (r *gormWishlistRepository) Create(wishlist *models.Wishlist) error {
+	wishlist.Items = nil
+	if err := r.db.Create(wishlist).Error; err != nil {
+		return translateError(err)
+	}
+	wishlist.Items = []models.WishlistItem{}
+	return nil
+}
+
+func (r *gormWishlistRepository) FindByUser(userID uint) ([]models.Wishlist, error) {
+	wishlists := []models.Wishlist{}
+	if err := r.withItems().Where("user_id = ?", userID).Order("id").Find(&wishlists).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return wishlists, nil
+}
+
+func (r *gormWishlistRepository) FindByID(id uint) (*models.Wishlist, error) {
+	var wishlist models.Wishlist
+	if err := r.withItems().First(&wishlist, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &wishlist, nil
+}
+
+func (r *gormWishlistRepository) FindByShareToken(token string) (*models.Wishlist, error) {
+	var wishlist models.Wishlist
+	if err := r.withItems().Where("share_token = ?", token).First(&wishlist).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &wishlist, nil
+}
+
+func (r *gormWishlistRepository) Update(wishlist *models.Wishlist) error {
+	wishlist.UpdatedAt = time.Now()
+	result := r.db.Model(&models.Wishlist{}).
+		Where("id = ?", wishlist.ID).
+		Updates(map[string]interface{}{
+			"name":        wishlist.Name,
+			"share_token": wishlist.ShareToken,
+			"updated_at":  wishlist.UpdatedAt,
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormWishlistRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Wishlist{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormWishlistRepository) AddItem(item *models.WishlistItem) error {
+	return translateError(r.db.Create(item).Error)
+}
+
+func (r *gormWishlistRepository) RemoveItem(wishlistID, productID uint) error {
+	result := r.db.Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).Delete(&models.WishlistItem{})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// withItems loads the items of every wishlist found in the order they
+// were added, leaving out products in the trash
+func (r *gormWishlistRepository) withItems() *gorm.DB {
+	return r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Where("product_id IN (SELECT id FROM products WHERE deleted_at IS NULL)").Order("id")
+	})
+}
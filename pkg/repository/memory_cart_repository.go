@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryCartRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryCartRepository returns a CartRepository that keeps data in memory
+func NewMemoryCartRepository() CartRepository {
+	return &memoryCartRepository{store: newMemoryStore()}
+}
+
+func (r *memoryCartRepository) FindByUser(userID uint) ([]models.CartItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	items := []models.CartItem{}
+	for _, item := range sortedValues(r.store.carts) {
+		if item.UserID != userID {
+			continue
+		}
+		if product, ok := r.store.products[item.ProductID]; !ok || product.DeletedAt.Valid {
+			continue
+		}
+		if item.VariantID != nil {
+			if variant, ok := r.store.variants[*item.VariantID]; !ok || variant.DeletedAt.Valid {
+				continue
+			}
+		}
+		items = append(items, cloneCartItem(item))
+	}
+	return items, nil
+}
+
+func (r *memoryCartRepository) FindItem(userID, productID, variantID uint) (*models.CartItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if id, ok := r.find(userID, productID, variantID); ok {
+		item := cloneCartItem(r.store.carts[id])
+		return &item, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryCartRepository) Set(item *models.CartItem) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[item.UserID]; !ok {
+		return ErrInvalidReference
+	}
+	if _, ok := r.store.products[item.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+	if item.VariantID != nil {
+		if _, ok := r.store.variants[*item.VariantID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+
+	now := time.Now()
+	if id, ok := r.find(item.UserID, item.ProductID, variantKey(item.VariantID)); ok {
+		item.ID = id
+		item.CreatedAt = r.store.carts[id].CreatedAt
+	} else {
+		item.ID = r.store.nextID("cart_items")
+		item.CreatedAt = now
+	}
+	item.UpdatedAt = now
+	r.store.carts[item.ID] = cloneCartItem(*item)
+	return nil
+}
+
+func (r *memoryCartRepository) Remove(userID, productID, variantID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	id, ok := r.find(userID, productID, variantID)
+	if !ok {
+		return ErrNotFound
+	}
+	delete(r.store.carts, id)
+	return nil
+}
+
+func (r *memoryCartRepository) Clear(userID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, item := range r.store.carts {
+		if item.UserID == userID {
+			delete(r.store.carts, id)
+		}
+	}
+	return nil
+}
+
+// find returns the ID of the user's item for the product, or for one of
+// its variants when variantID is not 0. The caller must hold the lock.
+func (r *memoryCartRepository) find(userID, productID, variantID uint) (uint, bool) {
+	for id, item := range r.store.carts {
+		if item.UserID == userID && item.ProductID == productID && variantKey(item.VariantID) == variantID {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// cloneCartItem returns a copy of item that shares no pointer with it
+func cloneCartItem(item models.CartItem) models.CartItem {
+	item.VariantID = cloneID(item.VariantID)
+	return item
+}
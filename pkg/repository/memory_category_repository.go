@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type memoryCategoryRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryCategoryRepository returns a CategoryRepository that keeps data in memory
+func NewMemoryCategoryRepository() CategoryRepository {
+	return &memoryCategoryRepository{store: newMemoryStore()}
+}
+
+func (r *memoryCategoryRepository) Create(category *models.Category) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(category.Name, 0) {
+		return ErrDuplicate
+	}
+	if !r.parentExists(category.ParentID) || !r.store.taxClassExists(category.TaxClassID) {
+		return ErrInvalidReference
+	}
+
+	now := time.Now()
+	category.ID = r.store.nextID("categories")
+	category.Version = 1
+	category.CreatedAt = now
+	category.UpdatedAt = now
+	r.store.categories[category.ID] = cloneCategory(*category)
+	return nil
+}
+
+func (r *memoryCategoryRepository) FindAll() ([]models.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	categories := []models.Category{}
+	for _, category := range sortedValues(r.store.categories) {
+		if !category.DeletedAt.Valid {
+			categories = append(categories, cloneCategory(category))
+		}
+	}
+	return categories, nil
+}
+
+func (r *memoryCategoryRepository) FindByID(id uint) (*models.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	category, ok := r.store.categories[id]
+	if !ok || category.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	category = cloneCategory(category)
+	return &category, nil
+}
+
+func (r *memoryCategoryRepository) FindByName(name string) (*models.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, category := range sortedValues(r.store.categories) {
+		if category.Name == name && !category.DeletedAt.Valid {
+			category = cloneCategory(category)
+			return &category, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryCategoryRepository) Update(category *models.Category) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.categories[category.ID]
+	if !ok || existing.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if existing.Version != category.Version {
+		return ErrVersionConflict
+	}
+	if r.nameTaken(category.Name, category.ID) {
+		return ErrDuplicate
+	}
+	if !r.parentExists(category.ParentID) || !r.store.taxClassExists(category.TaxClassID) {
+		return ErrInvalidReference
+	}
+
+	category.CreatedAt = existing.CreatedAt
+	category.DeletedAt = existing.DeletedAt
+	category.UpdatedAt = time.Now()
+	category.Version++
+	r.store.categories[category.ID] = cloneCategory(*category)
+	return nil
+}
+
+func (r *memoryCategoryRepository) Delete(id uint, version uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	category, ok := r.store.categories[id]
+	if !ok || category.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if version != 0 && category.Version != version {
+		return ErrVersionConflict
+	}
+	category.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.store.categories[id] = category
+	return nil
+}
+
+func (r *memoryCategoryRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, category := range r.store.categories {
+		if category.DeletedAt.Valid && category.DeletedAt.Time.Before(before) {
+			delete(r.store.categories, id)
+			r.detachProducts(id)
+			r.detachChildren(id)
+			r.deleteDiscountRules(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *memoryCategoryRepository) Path(id uint) ([]models.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var path []models.Category
+	seen := make(map[uint]bool)
+	next := &id
+	for next != nil && !seen[*next] {
+		category, ok := r.store.categories[*next]
+		if !ok || category.DeletedAt.Valid {
+			if len(path) > 0 {
+				break
+			}
+			return nil, ErrNotFound
+		}
+		seen[category.ID] = true
+		path = append(path, cloneCategory(category))
+		next = category.ParentID
+	}
+
+	slices.Reverse(path)
+	return path, nil
+}
+
+func (r *memoryCategoryRepository) SubtreeIDs(id uint) ([]uint, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if category, ok := r.store.categories[id]; !ok || category.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
+	ids := []uint{id}
+	seen := map[uint]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, category := range sortedValues(r.store.categories) {
+			if !category.DeletedAt.Valid && !seen[category.ID] && isChildOf(category, ids[i]) {
+				seen[category.ID] = true
+				ids = append(ids, category.ID)
+			}
+		}
+	}
+
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (r *memoryCategoryRepository) CountChildren(id uint) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, category := range r.store.categories {
+		if !category.DeletedAt.Valid && isChildOf(category, id) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Lock is a no-op: a memory transaction already holds the store's write lock
+func (r *memoryCategoryRepository) Lock(ids ...uint) error {
+	return nil
+}
+
+// nameTaken reports whether another category already uses the name.
+// The caller must hold the lock.
+func (r *memoryCategoryRepository) nameTaken(name string, exceptID uint) bool {
+	for id, category := range r.store.categories {
+		if id != exceptID && category.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// detachProducts mirrors ON DELETE SET NULL on products.category_id.
+// The caller must hold the lock.
+func (r *memoryCategoryRepository) detachProducts(categoryID uint) {
+	for id, product := range r.store.products {
+		if inCategory(product, categoryID) {
+			product.CategoryID = nil
+			r.store.products[id] = product
+		}
+	}
+}
+
+// parentExists mirrors the foreign key on categories.parent_id.
+// The caller must hold the lock.
+func (r *memoryCategoryRepository) parentExists(parentID *uint) bool {
+	if parentID == nil {
+		return true
+	}
+	_, ok := r.store.categories[*parentID]
+	return ok
+}
+
+// detachChildren mirrors ON DELETE SET NULL on categories.parent_id.
+// The caller must hold the lock.
+func (r *memoryCategoryRepository) detachChildren(parentID uint) {
+	for id, category := range r.store.categories {
+		if isChildOf(category, parentID) {
+			category.ParentID = nil
+			r.store.categories[id] = category
+		}
+	}
+}
+
+// deleteDiscountRules mirrors ON DELETE CASCADE on discount_rules.category_id.
+// The caller must hold the lock.
+func (r *memoryCategoryRepository) deleteDiscountRules(categoryID uint) {
+	for id, rule := range r.store.discounts {
+		if rule.CategoryID != nil && *rule.CategoryID == categoryID {
+			delete(r.store.discounts, id)
+		}
+	}
+}
+
+func isChildOf(category models.Category, parentID uint) bool {
+	return category.ParentID != nil && *category.ParentID == parentID
+}
+
+// cloneCategory returns a copy of category that shares no pointer with it
+func cloneCategory(category models.Category) models.Category {
+	if category.ParentID != nil {
+		parentID := *category.ParentID
+		category.ParentID = &parentID
+	}
+	category.TaxClassID = cloneID(category.TaxClassID)
+	return category
+}
@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type memoryCouponRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryCouponRepository returns a CouponRepository that keeps data in memory
+func NewMemoryCouponRepository() CouponRepository {
+	return &memoryCouponRepository{store: newMemoryStore()}
+}
+
+func (r *memoryCouponRepository) Create(coupon *models.Coupon) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.codeTaken(coupon.Code, 0) {
+		return ErrDuplicate
+	}
+
+	now := time.Now()
+	coupon.ID = r.store.nextID("coupons")
+	coupon.Version = 1
+	coupon.CreatedAt = now
+	coupon.UpdatedAt = now
+	r.store.coupons[coupon.ID] = cloneCoupon(*coupon)
+	return nil
+}
+
+func (r *memoryCouponRepository) FindAll() ([]models.Coupon, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	coupons := []models.Coupon{}
+	for _, coupon := range sortedValues(r.store.coupons) {
+		if !coupon.DeletedAt.Valid {
+			coupons = append(coupons, cloneCoupon(coupon))
+		}
+	}
+	return coupons, nil
+}
+
+func (r *memoryCouponRepository) FindByID(id uint) (*models.Coupon, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	coupon, ok := r.store.coupons[id]
+	if !ok || coupon.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	coupon = cloneCoupon(coupon)
+	return &coupon, nil
+}
+
+func (r *memoryCouponRepository) FindByCode(code string) (*models.Coupon, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, coupon := range sortedValues(r.store.coupons) {
+		if coupon.Code == code && !coupon.DeletedAt.Valid {
+			coupon = cloneCoupon(coupon)
+			return &coupon, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryCouponRepository) Update(coupon *models.Coupon) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.coupons[coupon.ID]
+	if !ok || existing.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if existing.Version != coupon.Version {
+		return ErrVersionConflict
+	}
+	if r.codeTaken(coupon.Code, coupon.ID) {
+		return ErrDuplicate
+	}
+
+	coupon.CreatedAt = existing.CreatedAt
+	coupon.DeletedAt = existing.DeletedAt
+	coupon.UpdatedAt = time.Now()
+	coupon.Version++
+	r.store.coupons[coupon.ID] = cloneCoupon(*coupon)
+	return nil
+}
+
+func (r *memoryCouponRepository) Delete(id uint, version uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	coupon, ok := r.store.coupons[id]
+	if !ok || coupon.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if version != 0 && coupon.Version != version {
+		return ErrVersionConflict
+	}
+	coupon.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.store.coupons[id] = coupon
+	return nil
+}
+
+func (r *memoryCouponRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, coupon := range r.store.coupons {
+		if coupon.DeletedAt.Valid && coupon.DeletedAt.Time.Before(before) {
+			delete(r.store.coupons, id)
+			r.deleteRedemptions(id)
+			r.detachOrders(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// Lock is a no-op: every write already holds the store's lock
+func (r *memoryCouponRepository) Lock(ids ...uint) error {
+	return nil
+}
+
+func (r *memoryCouponRepository) Redeem(redemption *models.CouponRedemption) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	coupon, ok := r.store.coupons[redemption.CouponID]
+	if !ok || coupon.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if redemption.UserID != nil {
+		if _, ok := r.store.users[*redemption.UserID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+	if coupon.UsageLimit != nil && coupon.Redeemed >= *coupon.UsageLimit {
+		return ErrLimitReached
+	}
+
+	coupon.Redeemed++
+	coupon.Version++
+	coupon.UpdatedAt = time.Now()
+	r.store.coupons[coupon.ID] = coupon
+
+	redemption.ID = r.store.nextID("coupon_redemptions")
+	redemption.CreatedAt = time.Now()
+	r.store.redeemed[redemption.ID] = cloneRedemption(*redemption)
+	return nil
+}
+
+func (r *memoryCouponRepository) LinkRedemption(id, orderID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	redemption, ok := r.store.redeemed[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if _, ok := r.store.orders[orderID]; !ok {
+		return ErrInvalidReference
+	}
+	for _, other := range r.store.redeemed {
+		if other.ID != id && other.OrderID != nil && *other.OrderID == orderID {
+			return ErrDuplicate
+		}
+	}
+	redemption.OrderID = &orderID
+	r.store.redeemed[id] = redemption
+	return nil
+}
+
+func (r *memoryCouponRepository) ReleaseRedemption(orderID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, redemption := range r.store.redeemed {
+		if redemption.OrderID == nil || *redemption.OrderID != orderID || redemption.ReleasedAt != nil {
+			continue
+		}
+		now := time.Now()
+		redemption.ReleasedAt = &now
+		r.store.redeemed[id] = redemption
+		if coupon, ok := r.store.coupons[redemption.CouponID]; ok && coupon.Redeemed > 0 {
+			coupon.Redeemed--
+			coupon.Version++
+			coupon.UpdatedAt = time.Now()
+			r.store.coupons[coupon.ID] = coupon
+		}
+		return nil
+	}
+	return nil
+}
+
+func (r *memoryCouponRepository) CountRedemptions(couponID, userID uint) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, redemption := range r.store.redeemed {
+		if redemption.CouponID == couponID && redemption.UserID != nil && *redemption.UserID == userID && redemption.ReleasedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryCouponRepository) FindRedemptions(couponID uint) ([]models.CouponRedemption, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	redemptions := []models.CouponRedemption{}
+	for _, redemption := range sortedValues(r.store.redeemed) {
+		if redemption.CouponID == couponID {
+			redemptions = append(redemptions, cloneRedemption(redemption))
+		}
+	}
+	return redemptions, nil
+}
+
+// deleteRedemptions mirrors ON DELETE CASCADE on coupon_redemptions.coupon_id.
+// The caller must hold the lock.
+func (r *memoryCouponRepository) deleteRedemptions(couponID uint) {
+	for id, redemption := range r.store.redeemed {
+		if redemption.CouponID == couponID {
+			delete(r.store.redeemed, id)
+		}
+	}
+}
+
+// detachOrders mirrors ON DELETE SET NULL on orders.coupon_id.
+// The caller must hold the lock.
+func (r *memoryCouponRepository) detachOrders(couponID uint) {
+	for id, order := range r.store.orders {
+		if order.CouponID != nil && *order.CouponID == couponID {
+			order.CouponID = nil
+			r.store.orders[id] = order
+		}
+	}
+}
+
+// codeTaken reports whether another coupon, trashed or not, already uses
+// the code. The caller must hold the lock.
+func (r *memoryCouponRepository) codeTaken(code string, exceptID uint) bool {
+	for id, coupon := range r.store.coupons {
+		if id != exceptID && coupon.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneCoupon returns a copy of coupon that shares no pointer with it
+func cloneCoupon(coupon models.Coupon) models.Coupon {
+	coupon.UsageLimit = cloneInt(coupon.UsageLimit)
+	coupon.PerUserLimit = cloneInt(coupon.PerUserLimit)
+	coupon.StartsAt = cloneTime(coupon.StartsAt)
+	coupon.EndsAt = cloneTime(coupon.EndsAt)
+	return coupon
+}
+
+// cloneRedemption returns a copy of redemption that shares no pointer with it
+func cloneRedemption(redemption models.CouponRedemption) models.CouponRedemption {
+	redemption.UserID = cloneID(redemption.UserID)
+	redemption.OrderID = cloneID(redemption.OrderID)
+	redemption.ReleasedAt = cloneTime(redemption.ReleasedAt)
+	return redemption
+}
+
+// cloneInt returns a copy of n that shares no memory with it
+func cloneInt(n *int) *int {
+	if n == nil {
+		return nil
+	}
+	copied := *n
+	return &copied
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+// priceKey is the primary key of a per-currency product price
+type priceKey struct {
+	productID uint
+	currency  string
+}
+
+type memoryCurrencyRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryCurrencyRepository returns a CurrencyRepository that keeps data in memory
+func NewMemoryCurrencyRepository() CurrencyRepository {
+	return &memoryCurrencyRepository{store: newMemoryStore()}
+}
+
+func (r *memoryCurrencyRepository) FindRates() ([]models.ExchangeRate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rates := []models.ExchangeRate{}
+	for _, rate := range r.store.rates {
+		rates = append(rates, rate)
+	}
+	slices.SortFunc(rates, func(a, b models.ExchangeRate) int {
+		return strings.Compare(a.Currency, b.Currency)
+	})
+	return rates, nil
+}
+
+func (r *memoryCurrencyRepository) FindRate(currency string) (*models.ExchangeRate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rate, ok := r.store.rates[currency]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &rate, nil
+}
+
+func (r *memoryCurrencyRepository) SaveRate(rate *models.ExchangeRate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rate.UpdatedAt = time.Now()
+	r.store.rates[rate.Currency] = *rate
+	return nil
+}
+
+func (r *memoryCurrencyRepository) DeleteRate(currency string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.rates[currency]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.rates, currency)
+	return nil
+}
+
+func (r *memoryCurrencyRepository) FindPrices(productIDs []uint) ([]models.ProductPrice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	prices := []models.ProductPrice{}
+	for key, price := range r.store.prices {
+		if slices.Contains(productIDs, key.productID) {
+			prices = append(prices, price)
+		}
+	}
+	slices.SortFunc(prices, func(a, b models.ProductPrice) int {
+		if a.ProductID != b.ProductID {
+			return int(a.ProductID) - int(b.ProductID)
+		}
+		return strings.Compare(a.Currency, b.Currency)
+	})
+	return prices, nil
+}
+
+func (r *memoryCurrencyRepository) SavePrice(price *models.ProductPrice) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.products[price.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+	price.UpdatedAt = time.Now()
+	r.store.prices[priceKey{productID: price.ProductID, currency: price.Currency}] = *price
+	return nil
+}
+
+func (r *memoryCurrencyRepository) DeletePrice(productID uint, currency string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := priceKey{productID: productID, currency: currency}
+	if _, ok := r.store.prices[key]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.prices, key)
+	return nil
+}
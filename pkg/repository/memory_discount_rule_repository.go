@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type memoryDiscountRuleRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryDiscountRuleRepository returns a DiscountRuleRepository that keeps data in memory
+func NewMemoryDiscountRuleRepository() DiscountRuleRepository {
+	return &memoryDiscountRuleRepository{store: newMemoryStore()}
+}
+
+func (r *memoryDiscountRuleRepository) Create(rule *models.DiscountRule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.referencesExist(*rule) {
+		return ErrInvalidReference
+	}
+
+	now := time.Now()
+	rule.ID = r.store.nextID("discount_rules")
+	rule.Version = 1
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	r.store.discounts[rule.ID] = cloneDiscountRule(*rule)
+	return nil
+}
+
+func (r *memoryDiscountRuleRepository) FindAll() ([]models.DiscountRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rules := []models.DiscountRule{}
+	for _, rule := range sortedValues(r.store.discounts) {
+		if !rule.DeletedAt.Valid {
+			rules = append(rules, cloneDiscountRule(rule))
+		}
+	}
+	return rules, nil
+}
+
+func (r *memoryDiscountRuleRepository) FindByID(id uint) (*models.DiscountRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rule, ok := r.store.discounts[id]
+	if !ok || rule.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	rule = cloneDiscountRule(rule)
+	return &rule, nil
+}
+
+func (r *memoryDiscountRuleRepository) Update(rule *models.DiscountRule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.discounts[rule.ID]
+	if !ok || existing.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if existing.Version != rule.Version {
+		return ErrVersionConflict
+	}
+	if !r.referencesExist(*rule) {
+		return ErrInvalidReference
+	}
+
+	rule.CreatedAt = existing.CreatedAt
+	rule.DeletedAt = existing.DeletedAt
+	rule.UpdatedAt = time.Now()
+	rule.Version++
+	r.store.discounts[rule.ID] = cloneDiscountRule(*rule)
+	return nil
+}
+
+func (r *memoryDiscountRuleRepository) Delete(id uint, version uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rule, ok := r.store.discounts[id]
+	if !ok || rule.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if version != 0 && rule.Version != version {
+		return ErrVersionConflict
+	}
+	rule.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.store.discounts[id] = rule
+	return nil
+}
+
+func (r *memoryDiscountRuleRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, rule := range r.store.discounts {
+		if rule.DeletedAt.Valid && rule.DeletedAt.Time.Before(before) {
+			delete(r.store.discounts, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *memoryDiscountRuleRepository) FindInForce(at time.Time) ([]models.DiscountRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rules := []models.DiscountRule{}
+	for _, rule := range sortedValues(r.store.discounts) {
+		if !rule.DeletedAt.Valid && rule.InForce(at) {
+			rules = append(rules, cloneDiscountRule(rule))
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
+	})
+	return rules, nil
+}
+
+// referencesExist reports whether the product and category the rule is
+// scoped to exist. Trashed records still satisfy the foreign keys.
+// The caller must hold the lock.
+func (r *memoryDiscountRuleRepository) referencesExist(rule models.DiscountRule) bool {
+	if rule.ProductID != nil {
+		if _, ok := r.store.products[*rule.ProductID]; !ok {
+			return false
+		}
+	}
+	if rule.CategoryID != nil {
+		if _, ok := r.store.categories[*rule.CategoryID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// cloneDiscountRule returns a copy of rule that shares no pointer with it
+func cloneDiscountRule(rule models.DiscountRule) models.DiscountRule {
+	rule.ProductID = cloneID(rule.ProductID)
+	rule.CategoryID = cloneID(rule.CategoryID)
+	rule.StartsAt = cloneTime(rule.StartsAt)
+	rule.EndsAt = cloneTime(rule.EndsAt)
+	return rule
+}
+
+// cloneTime returns a copy of t that shares no memory with it
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
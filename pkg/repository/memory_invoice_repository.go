@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryInvoiceRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryInvoiceRepository returns an InvoiceRepository that keeps data in memory
+func NewMemoryInvoiceRepository() InvoiceRepository {
+	return &memoryInvoiceRepository{store: newMemoryStore()}
+}
+
+func (r *memoryInvoiceRepository) Issue(invoice *models.Invoice) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.orders[invoice.OrderID]; !ok {
+		return ErrInvalidReference
+	}
+	for _, existing := range r.store.invoices {
+		if existing.OrderID == invoice.OrderID {
+			return ErrDuplicate
+		}
+	}
+
+	invoice.ID = r.store.nextID("invoices")
+	invoice.CreatedAt = time.Now()
+	// Invoice numbers have a counter of their own, like the sequences table
+	invoice.Number = r.store.nextID("sequences.invoices")
+	r.store.invoices[invoice.ID] = *invoice
+	return nil
+}
+
+func (r *memoryInvoiceRepository) FindByOrder(orderID uint) (*models.Invoice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, invoice := range r.store.invoices {
+		if invoice.OrderID == orderID {
+			return &invoice, nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryOrderRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryOrderRepository returns an OrderRepository that keeps data in memory
+func NewMemoryOrderRepository() OrderRepository {
+	return &memoryOrderRepository{store: newMemoryStore()}
+}
+
+func (r *memoryOrderRepository) Create(order *models.Order) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.referencesExist(*order) {
+		return ErrInvalidReference
+	}
+
+	now := time.Now()
+	order.ID = r.store.nextID("orders")
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	if order.Status == "" {
+		order.Status = models.OrderPending
+	}
+	for i := range order.Items {
+		order.Items[i].ID = r.store.nextID("order_items")
+		order.Items[i].OrderID = order.ID
+	}
+	for i := range order.Taxes {
+		order.Taxes[i].ID = r.store.nextID("order_taxes")
+		order.Taxes[i].OrderID = order.ID
+	}
+	r.store.orders[order.ID] = cloneOrder(*order)
+	return nil
+}
+
+func (r *memoryOrderRepository) FindByID(id uint) (*models.Order, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	order, ok := r.store.orders[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	order = cloneOrder(order)
+	return &order, nil
+}
+
+func (r *memoryOrderRepository) FindByUser(userID uint) ([]models.Order, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	orders := []models.Order{}
+	for _, order := range sortedValues(r.store.orders) {
+		if order.UserID != nil && *order.UserID == userID {
+			orders = append(orders, cloneOrder(order))
+		}
+	}
+	slices.Reverse(orders)
+	return orders, nil
+}
+
+// Lock is a no-op: every write already holds the store's lock
+func (r *memoryOrderRepository) Lock(ids ...uint) error {
+	return nil
+}
+
+func (r *memoryOrderRepository) SetStatus(id uint, from, to string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	order, ok := r.store.orders[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if order.Status != from {
+		return ErrVersionConflict
+	}
+	order.Status = to
+	order.UpdatedAt = time.Now()
+	r.store.orders[id] = order
+	return nil
+}
+
+func (r *memoryOrderRepository) CreateTransition(transition *models.OrderTransition) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.orders[transition.OrderID]; !ok {
+		return ErrInvalidReference
+	}
+	if transition.ActorID != nil {
+		if _, ok := r.store.users[*transition.ActorID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+
+	transition.ID = r.store.nextID("order_transitions")
+	transition.CreatedAt = time.Now()
+	r.store.timeline[transition.ID] = cloneTransition(*transition)
+	return nil
+}
+
+func (r *memoryOrderRepository) FindTransitions(orderID uint) ([]models.OrderTransition, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	transitions := []models.OrderTransition{}
+	for _, transition := range sortedValues(r.store.timeline) {
+		if transition.OrderID == orderID {
+			transitions = append(transitions, cloneTransition(transition))
+		}
+	}
+	return transitions, nil
+}
+
+// referencesExist reports whether the user, coupon, products and variants
+// an order refers to exist. Trashed records still satisfy the foreign keys.
+// The caller must hold the lock.
+func (r *memoryOrderRepository) referencesExist(order models.Order) bool {
+	if order.UserID != nil {
+		if _, ok := r.store.users[*order.UserID]; !ok {
+			return false
+		}
+	}
+	if order.CouponID != nil {
+		if _, ok := r.store.coupons[*order.CouponID]; !ok {
+			return false
+		}
+	}
+	for _, item := range order.Items {
+		if item.ProductID != nil {
+			if _, ok := r.store.products[*item.ProductID]; !ok {
+				return false
+			}
+		}
+		if item.VariantID != nil {
+			if _, ok := r.store.variants[*item.VariantID]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// cloneOrder returns a copy of order that shares no pointer with it
+func cloneOrder(order models.Order) models.Order {
+	order.UserID = cloneID(order.UserID)
+	order.CouponID = cloneID(order.CouponID)
+	order.Items = slices.Clone(order.Items)
+	if order.Items == nil {
+		order.Items = []models.OrderItem{}
+	}
+	for i := range order.Items {
+		order.Items[i].ProductID = cloneID(order.Items[i].ProductID)
+		order.Items[i].VariantID = cloneID(order.Items[i].VariantID)
+	}
+	order.Taxes = slices.Clone(order.Taxes)
+	if order.Taxes == nil {
+		order.Taxes = []models.OrderTax{}
+	}
+	return order
+}
+
+// cloneTransition returns a copy of transition that shares no pointer with it
+func cloneTransition(transition models.OrderTransition) models.OrderTransition {
+	transition.ActorID = cloneID(transition.ActorID)
+	return transition
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryPaymentRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryPaymentRepository returns a PaymentRepository that keeps data in memory
+func NewMemoryPaymentRepository() PaymentRepository {
+	return &memoryPaymentRepository{store: newMemoryStore()}
+}
+
+func (r *memoryPaymentRepository) Create(payment *models.Payment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.orders[payment.OrderID]; !ok {
+		return ErrInvalidReference
+	}
+	for _, existing := range r.store.payments {
+		if existing.Provider == payment.Provider && existing.IntentID == payment.IntentID {
+			return ErrDuplicate
+		}
+	}
+
+	now := time.Now()
+	payment.ID = r.store.nextID("payments")
+	payment.CreatedAt = now
+	payment.UpdatedAt = now
+	if payment.Status == "" {
+		payment.Status = models.PaymentPending
+	}
+	r.store.payments[payment.ID] = *payment
+	return nil
+}
+
+func (r *memoryPaymentRepository) FindByOrder(orderID uint) ([]models.Payment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	payments := []models.Payment{}
+	for _, payment := range sortedValues(r.store.payments) {
+		if payment.OrderID == orderID {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+func (r *memoryPaymentRepository) FindByIntent(provider, intentID string) (*models.Payment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, payment := range r.store.payments {
+		if payment.Provider == provider && payment.IntentID == intentID {
+			return &payment, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryPaymentRepository) SetStatus(id uint, from, to string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	payment, ok := r.store.payments[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if payment.Status != from {
+		return ErrVersionConflict
+	}
+	payment.Status = to
+	payment.UpdatedAt = time.Now()
+	r.store.payments[id] = payment
+	return nil
+}
+
+func (r *memoryPaymentRepository) RecordEvent(event *models.PaymentEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if event.PaymentID != nil {
+		if _, ok := r.store.payments[*event.PaymentID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+	for _, existing := range r.store.events {
+		if existing.Provider == event.Provider && existing.EventID == event.EventID {
+			return ErrDuplicate
+		}
+	}
+
+	event.ID = r.store.nextID("payment_events")
+	event.CreatedAt = time.Now()
+	r.store.events[event.ID] = clonePaymentEvent(*event)
+	return nil
+}
+
+// clonePaymentEvent returns a copy of event that shares no pointer with it
+func clonePaymentEvent(event models.PaymentEvent) models.PaymentEvent {
+	event.PaymentID = cloneID(event.PaymentID)
+	return event
+}
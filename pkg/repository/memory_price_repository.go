@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryPriceHistoryRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryPriceHistoryRepository returns a PriceHistoryRepository that keeps data in memory
+func NewMemoryPriceHistoryRepository() PriceHistoryRepository {
+	return &memoryPriceHistoryRepository{store: newMemoryStore()}
+}
+
+func (r *memoryPriceHistoryRepository) Create(change *models.PriceChange) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.products[change.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+	if change.ActorID != nil {
+		if _, ok := r.store.users[*change.ActorID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+
+	change.ID = r.store.nextID("price_history")
+	change.CreatedAt = time.Now()
+	r.store.history[change.ID] = clonePriceChange(*change)
+	return nil
+}
+
+func (r *memoryPriceHistoryRepository) FindByProduct(productID uint) ([]models.PriceChange, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.history(productID), nil
+}
+
+func (r *memoryPriceHistoryRepository) FindAt(productID uint, at time.Time) (*models.PriceChange, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	history := r.history(productID)
+	for i := len(history) - 1; i >= 0; i-- {
+		if !history[i].EffectiveAt.After(at) {
+			return &history[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// history returns the changes of a product in the order they took effect.
+// The caller must hold the read lock.
+func (r *memoryPriceHistoryRepository) history(productID uint) []models.PriceChange {
+	changes := []models.PriceChange{}
+	for _, change := range r.store.history {
+		if change.ProductID == productID {
+			changes = append(changes, clonePriceChange(change))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if !changes[i].EffectiveAt.Equal(changes[j].EffectiveAt) {
+			return changes[i].EffectiveAt.Before(changes[j].EffectiveAt)
+		}
+		return changes[i].ID < changes[j].ID
+	})
+	return changes
+}
+
+type memoryPriceScheduleRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryPriceScheduleRepository returns a PriceScheduleRepository that keeps data in memory
+func NewMemoryPriceScheduleRepository() PriceScheduleRepository {
+	return &memoryPriceScheduleRepository{store: newMemoryStore()}
+}
+
+func (r *memoryPriceScheduleRepository) Create(schedule *models.ScheduledPrice) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.products[schedule.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+	if schedule.ActorID != nil {
+		if _, ok := r.store.users[*schedule.ActorID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+
+	now := time.Now()
+	schedule.ID = r.store.nextID("scheduled_prices")
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+	if schedule.Status == "" {
+		schedule.Status = models.SchedulePending
+	}
+	r.store.schedules[schedule.ID] = cloneSchedule(*schedule)
+	return nil
+}
+
+func (r *memoryPriceScheduleRepository) FindByID(id uint) (*models.ScheduledPrice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	schedule, ok := r.store.schedules[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	schedule = cloneSchedule(schedule)
+	return &schedule, nil
+}
+
+func (r *memoryPriceScheduleRepository) FindByProduct(productID uint) ([]models.ScheduledPrice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.matching(func(schedule models.ScheduledPrice) bool {
+		return schedule.ProductID == productID
+	}), nil
+}
+
+func (r *memoryPriceScheduleRepository) SetStatus(id uint, from, to string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	schedule, ok := r.store.schedules[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if schedule.Status != from {
+		return ErrVersionConflict
+	}
+	schedule.Status = to
+	schedule.UpdatedAt = time.Now()
+	r.store.schedules[id] = schedule
+	return nil
+}
+
+func (r *memoryPriceScheduleRepository) FindDue(now time.Time) ([]models.ScheduledPrice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.matching(func(schedule models.ScheduledPrice) bool {
+		product, ok := r.store.products[schedule.ProductID]
+		return ok && !product.DeletedAt.Valid &&
+			schedule.Status == models.SchedulePending && !schedule.EffectiveAt.After(now)
+	}), nil
+}
+
+// matching returns the schedules for which keep is true, earliest first.
+// The caller must hold the read lock.
+func (r *memoryPriceScheduleRepository) matching(keep func(models.ScheduledPrice) bool) []models.ScheduledPrice {
+	schedules := []models.ScheduledPrice{}
+	for _, schedule := range r.store.schedules {
+		if keep(schedule) {
+			schedules = append(schedules, cloneSchedule(schedule))
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		if !schedules[i].EffectiveAt.Equal(schedules[j].EffectiveAt) {
+			return schedules[i].EffectiveAt.Before(schedules[j].EffectiveAt)
+		}
+		return schedules[i].ID < schedules[j].ID
+	})
+	return schedules
+}
+
+// clonePriceChange returns a copy of change that shares no pointer with it
+func clonePriceChange(change models.PriceChange) models.PriceChange {
+	change.ActorID = cloneID(change.ActorID)
+	return change
+}
+
+// cloneSchedule returns a copy of schedule that shares no pointer with it
+func cloneSchedule(schedule models.ScheduledPrice) models.ScheduledPrice {
+	schedule.ActorID = cloneID(schedule.ActorID)
+	return schedule
+}
@@ -0,0 +1,484 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type memoryProductRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryProductRepository returns a ProductRepository that keeps data in memory
+func NewMemoryProductRepository() ProductRepository {
+	return &memoryProductRepository{store: newMemoryStore()}
+}
+
+func (r *memoryProductRepository) Create(product *models.Product) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(product.Name, 0) {
+		return ErrDuplicate
+	}
+	if !r.categoryExists(product.CategoryID) || !r.store.taxClassExists(product.TaxClassID) {
+		return ErrInvalidReference
+	}
+
+	now := time.Now()
+	product.ID = r.store.nextID("products")
+	product.Version = 1
+	product.CreatedAt = now
+	product.UpdatedAt = now
+	r.store.products[product.ID] = cloneProduct(*product)
+	return nil
+}
+
+func (r *memoryProductRepository) FindAll(filter ProductFilter) ([]models.Product, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	products := []models.Product{}
+	for _, product := range sortedValues(r.store.products) {
+		if !product.DeletedAt.Valid && filter.matches(product) {
+			products = append(products, cloneProduct(product))
+		}
+	}
+	return products, nil
+}
+
+func (r *memoryProductRepository) FindByID(id uint) (*models.Product, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	product, ok := r.store.products[id]
+	if !ok || product.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	product = cloneProduct(product)
+	return &product, nil
+}
+
+func (r *memoryProductRepository) FindByName(name string) (*models.Product, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, product := range sortedValues(r.store.products) {
+		if product.Name == name && !product.DeletedAt.Valid {
+			product = cloneProduct(product)
+			return &product, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryProductRepository) Update(product *models.Product) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.products[product.ID]
+	if !ok || existing.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if existing.Version != product.Version {
+		return ErrVersionConflict
+	}
+	if r.nameTaken(product.Name, product.ID) {
+		return ErrDuplicate
+	}
+	if !r.categoryExists(product.CategoryID) || !r.store.taxClassExists(product.TaxClassID) {
+		return ErrInvalidReference
+	}
+
+	product.CreatedAt = existing.CreatedAt
+	product.DeletedAt = existing.DeletedAt
+	product.UpdatedAt = time.Now()
+	product.Version++
+	r.store.products[product.ID] = cloneProduct(*product)
+	return nil
+}
+
+func (r *memoryProductRepository) Delete(id uint, version uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	product, ok := r.store.products[id]
+	if !ok || product.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if version != 0 && product.Version != version {
+		return ErrVersionConflict
+	}
+	product.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.store.products[id] = product
+	return nil
+}
+
+func (r *memoryProductRepository) FindDeleted() ([]models.Product, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	products := []models.Product{}
+	for _, product := range sortedValues(r.store.products) {
+		if product.DeletedAt.Valid {
+			products = append(products, cloneProduct(product))
+		}
+	}
+	return products, nil
+}
+
+func (r *memoryProductRepository) Restore(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	product, ok := r.store.products[id]
+	if !ok || !product.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	product.DeletedAt = gorm.DeletedAt{}
+	product.Version++
+	r.store.products[id] = product
+	return nil
+}
+
+func (r *memoryProductRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, product := range r.store.products {
+		if product.DeletedAt.Valid && product.DeletedAt.Time.Before(before) {
+			delete(r.store.products, id)
+			r.deleteVariants(id)
+			r.deleteMovements(id)
+			r.deleteReservations(id)
+			r.deleteLevels(id)
+			r.deleteTransfers(id)
+			r.deletePrices(id)
+			r.deletePriceHistory(id)
+			r.deleteSchedules(id)
+			r.deleteDiscountRules(id)
+			r.deleteCartItems(id)
+			r.detachOrderItems(id)
+			r.deleteReviews(id)
+			r.deleteWishlistItems(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *memoryProductRepository) CountByCategory(categoryID uint) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, product := range r.store.products {
+		if !product.DeletedAt.Valid && inCategory(product, categoryID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryProductRepository) ReassignCategory(fromID, toID uint) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.categoryExists(&toID) {
+		return 0, ErrInvalidReference
+	}
+
+	var reassigned int64
+	now := time.Now()
+	for id, product := range r.store.products {
+		if !product.DeletedAt.Valid && inCategory(product, fromID) {
+			categoryID := toID
+			product.CategoryID = &categoryID
+			product.UpdatedAt = now
+			product.Version++
+			r.store.products[id] = product
+			reassigned++
+		}
+	}
+	return reassigned, nil
+}
+
+func (r *memoryProductRepository) DeleteByCategory(categoryID uint) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	now := time.Now()
+	for id, product := range r.store.products {
+		if !product.DeletedAt.Valid && inCategory(product, categoryID) {
+			product.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+			r.store.products[id] = product
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *memoryProductRepository) AdjustQty(id uint, delta int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	product, ok := r.store.products[id]
+	if !ok || product.DeletedAt.Valid {
+		return 0, ErrNotFound
+	}
+	if product.Qty+delta < 0 {
+		return product.Qty, ErrInsufficientStock
+	}
+	product.Qty += delta
+	product.UpdatedAt = time.Now()
+	product.Version++
+	r.store.products[id] = product
+	return product.Qty, nil
+}
+
+func (r *memoryProductRepository) Restock(id uint, delta int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	product, ok := r.store.products[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if product.Qty+delta < 0 {
+		return product.Qty, ErrInsufficientStock
+	}
+	product.Qty += delta
+	product.UpdatedAt = time.Now()
+	product.Version++
+	r.store.products[id] = product
+	return product.Qty, nil
+}
+
+// Lock is a no-op: a memory transaction already holds the store's write lock
+func (r *memoryProductRepository) Lock(ids ...uint) error {
+	return nil
+}
+
+func (r *memoryProductRepository) CountByCurrency(currency string) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, product := range r.store.products {
+		if !product.DeletedAt.Valid && product.Currency == currency {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryProductRepository) SetRating(id uint, average decimal.Decimal, count int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	product, ok := r.store.products[id]
+	if !ok {
+		return ErrNotFound
+	}
+	product.RatingAverage = average
+	product.RatingCount = count
+	product.UpdatedAt = time.Now()
+	product.Version++
+	r.store.products[id] = product
+	return nil
+}
+
+// categoryExists mirrors the foreign key on products.category_id, which
+// also accepts categories that are in the trash. The caller must hold the lock.
+func (r *memoryProductRepository) categoryExists(categoryID *uint) bool {
+	if categoryID == nil {
+		return true
+	}
+	_, ok := r.store.categories[*categoryID]
+	return ok
+}
+
+// nameTaken reports whether another product already uses the name.
+// The caller must hold the lock.
+func (r *memoryProductRepository) nameTaken(name string, exceptID uint) bool {
+	for id, product := range r.store.products {
+		if id != exceptID && product.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteVariants mirrors ON DELETE CASCADE on variants.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteVariants(productID uint) {
+	for id, variant := range r.store.variants {
+		if variant.ProductID == productID {
+			delete(r.store.variants, id)
+		}
+	}
+}
+
+// deleteMovements mirrors ON DELETE CASCADE on stock_movements.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteMovements(productID uint) {
+	for id, movement := range r.store.movements {
+		if movement.ProductID == productID {
+			delete(r.store.movements, id)
+		}
+	}
+}
+
+// deleteReservations mirrors ON DELETE CASCADE on reservations.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteReservations(productID uint) {
+	for id, reservation := range r.store.reserved {
+		if reservation.ProductID == productID {
+			delete(r.store.reserved, id)
+		}
+	}
+}
+
+// deleteLevels mirrors ON DELETE CASCADE on warehouse_stocks.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteLevels(productID uint) {
+	for key := range r.store.levels {
+		if key.productID == productID {
+			delete(r.store.levels, key)
+		}
+	}
+}
+
+// deleteTransfers mirrors ON DELETE CASCADE on transfers.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteTransfers(productID uint) {
+	for id, transfer := range r.store.transfers {
+		if transfer.ProductID == productID {
+			delete(r.store.transfers, id)
+		}
+	}
+}
+
+// deletePrices mirrors ON DELETE CASCADE on product_prices.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deletePrices(productID uint) {
+	for key := range r.store.prices {
+		if key.productID == productID {
+			delete(r.store.prices, key)
+		}
+	}
+}
+
+// deletePriceHistory mirrors ON DELETE CASCADE on price_history.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deletePriceHistory(productID uint) {
+	for id, change := range r.store.history {
+		if change.ProductID == productID {
+			delete(r.store.history, id)
+		}
+	}
+}
+
+// deleteSchedules mirrors ON DELETE CASCADE on scheduled_prices.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteSchedules(productID uint) {
+	for id, schedule := range r.store.schedules {
+		if schedule.ProductID == productID {
+			delete(r.store.schedules, id)
+		}
+	}
+}
+
+// deleteDiscountRules mirrors ON DELETE CASCADE on discount_rules.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteDiscountRules(productID uint) {
+	for id, rule := range r.store.discounts {
+		if rule.ProductID != nil && *rule.ProductID == productID {
+			delete(r.store.discounts, id)
+		}
+	}
+}
+
+func inCategory(product models.Product, categoryID uint) bool {
+	return product.CategoryID != nil && *product.CategoryID == categoryID
+}
+
+// matches applies the filter the way the database backend does
+func (f ProductFilter) matches(product models.Product) bool {
+	if f.CategoryIDs == nil {
+		return true
+	}
+	return product.CategoryID != nil && slices.Contains(f.CategoryIDs, *product.CategoryID)
+}
+
+// cloneProduct returns a copy of product that shares no pointer with it,
+// so stored records cannot be changed through values handed to callers.
+// Loaded associations are dropped; they are never stored.
+func cloneProduct(product models.Product) models.Product {
+	product.Category = nil
+	product.Variants = nil
+	product.Available = nil
+	if product.CategoryID != nil {
+		categoryID := *product.CategoryID
+		product.CategoryID = &categoryID
+	}
+	product.TaxClassID = cloneID(product.TaxClassID)
+	return product
+}
+
+// deleteCartItems mirrors ON DELETE CASCADE on cart_items.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteCartItems(productID uint) {
+	for id, item := range r.store.carts {
+		if item.ProductID == productID {
+			delete(r.store.carts, id)
+		}
+	}
+}
+
+// deleteReviews mirrors ON DELETE CASCADE on reviews.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteReviews(productID uint) {
+	for id, review := range r.store.reviews {
+		if review.ProductID == productID {
+			delete(r.store.reviews, id)
+		}
+	}
+}
+
+// deleteWishlistItems mirrors ON DELETE CASCADE on wishlist_items.product_id.
+// The caller must hold the lock.
+func (r *memoryProductRepository) deleteWishlistItems(productID uint) {
+	for id, item := range r.store.wished {
+		if item.ProductID == productID {
+			delete(r.store.wished, id)
+		}
+	}
+}
+
+// detachOrderItems mirrors ON DELETE SET NULL on order_items.product_id,
+// and on order_items.variant_id for the variants purged with the product.
+// The caller must hold the lock.
+func (r *memoryProductRepository) detachOrderItems(productID uint) {
+	for id, order := range r.store.orders {
+		detached := false
+		items := slices.Clone(order.Items)
+		for i, item := range items {
+			if item.ProductID != nil && *item.ProductID == productID {
+				items[i].ProductID = nil
+				items[i].VariantID = nil
+				detached = true
+			}
+		}
+		if detached {
+			order.Items = items
+			r.store.orders[id] = order
+		}
+	}
+}
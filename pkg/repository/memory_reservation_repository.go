@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryReservationRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryReservationRepository returns a ReservationRepository that keeps data in memory
+func NewMemoryReservationRepository() ReservationRepository {
+	return &memoryReservationRepository{store: newMemoryStore()}
+}
+
+func (r *memoryReservationRepository) Create(reservation *models.Reservation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.products[reservation.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+	if reservation.UserID != nil {
+		if _, ok := r.store.users[*reservation.UserID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+
+	now := time.Now()
+	reservation.ID = r.store.nextID("reservations")
+	reservation.CreatedAt = now
+	reservation.UpdatedAt = now
+	if reservation.Status == "" {
+		reservation.Status = models.ReservationActive
+	}
+	r.store.reserved[reservation.ID] = cloneReservation(*reservation)
+	return nil
+}
+
+func (r *memoryReservationRepository) FindByID(id uint) (*models.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reservation, ok := r.store.reserved[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	reservation = cloneReservation(reservation)
+	return &reservation, nil
+}
+
+func (r *memoryReservationRepository) SetStatus(id uint, from, to string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	reservation, ok := r.store.reserved[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if reservation.Status != from {
+		return ErrVersionConflict
+	}
+	reservation.Status = to
+	reservation.UpdatedAt = time.Now()
+	r.store.reserved[id] = reservation
+	return nil
+}
+
+func (r *memoryReservationRepository) ReservedQty(productIDs []uint, now time.Time) (map[uint]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[uint]bool, len(productIDs))
+	for _, id := range productIDs {
+		wanted[id] = true
+	}
+
+	reserved := make(map[uint]int)
+	for _, reservation := range r.store.reserved {
+		if wanted[reservation.ProductID] && isHolding(reservation, now) {
+			reserved[reservation.ProductID] += reservation.Quantity
+		}
+	}
+	return reserved, nil
+}
+
+func (r *memoryReservationRepository) ExpireBefore(now time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var expired int64
+	for id, reservation := range r.store.reserved {
+		if reservation.Status == models.ReservationActive && !reservation.ExpiresAt.After(now) {
+			reservation.Status = models.ReservationExpired
+			reservation.UpdatedAt = now
+			r.store.reserved[id] = reservation
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// isHolding reports whether the reservation still holds stock at now
+func isHolding(reservation models.Reservation, now time.Time) bool {
+	return reservation.Status == models.ReservationActive && reservation.ExpiresAt.After(now)
+}
+
+// cloneReservation returns a copy of reservation that shares no pointer with it
+func cloneReservation(reservation models.Reservation) models.Reservation {
+	if reservation.UserID != nil {
+		userID := *reservation.UserID
+		reservation.UserID = &userID
+	}
+	return reservation
+}
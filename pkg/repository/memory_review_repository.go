@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryReviewRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryReviewRepository returns a ReviewRepository that keeps data in memory
+func NewMemoryReviewRepository() ReviewRepository {
+	return &memoryReviewRepository{store: newMemoryStore()}
+}
+
+func (r *memoryReviewRepository) Create(review *models.Review) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.products[review.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+	if review.UserID != nil {
+		if _, ok := r.store.users[*review.UserID]; !ok {
+			return ErrInvalidReference
+		}
+		for _, existing := range r.store.reviews {
+			if existing.ProductID == review.ProductID && existing.UserID != nil && *existing.UserID == *review.UserID {
+				return ErrDuplicate
+			}
+		}
+	}
+
+	now := time.Now()
+	review.ID = r.store.nextID("reviews")
+	review.CreatedAt = now
+	review.UpdatedAt = now
+	r.store.reviews[review.ID] = cloneReview(*review)
+	return nil
+}
+
+func (r *memoryReviewRepository) Find(filter ReviewFilter, page, limit int) ([]models.Review, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var matching []models.Review
+	for _, review := range sortedValues(r.store.reviews) {
+		if filter.matches(review) {
+			matching = append(matching, review)
+		}
+	}
+	slices.Reverse(matching)
+
+	reviews := []models.Review{}
+	start := (page - 1) * limit
+	for i := start; i < len(matching) && i < start+limit; i++ {
+		reviews = append(reviews, cloneReview(matching[i]))
+	}
+	return reviews, int64(len(matching)), nil
+}
+
+func (r *memoryReviewRepository) FindByID(id uint) (*models.Review, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	review, ok := r.store.reviews[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	review = cloneReview(review)
+	return &review, nil
+}
+
+func (r *memoryReviewRepository) Update(review *models.Review) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.reviews[review.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	existing.Rating = review.Rating
+	existing.Title = review.Title
+	existing.Body = review.Body
+	existing.Status = review.Status
+	existing.UpdatedAt = time.Now()
+	r.store.reviews[review.ID] = existing
+	review.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+func (r *memoryReviewRepository) SetStatus(id uint, status string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.reviews[id]
+	if !ok {
+		return ErrNotFound
+	}
+	existing.Status = status
+	existing.UpdatedAt = time.Now()
+	r.store.reviews[id] = existing
+	return nil
+}
+
+func (r *memoryReviewRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.reviews[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.reviews, id)
+	return nil
+}
+
+func (r *memoryReviewRepository) Stats(productID uint) (int, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count, sum := 0, 0
+	for _, review := range r.store.reviews {
+		if review.ProductID == productID && review.Status == models.ReviewApproved {
+			count++
+			sum += review.Rating
+		}
+	}
+	return count, sum, nil
+}
+
+// matches applies the filter the way the database backend does
+func (f ReviewFilter) matches(review models.Review) bool {
+	if f.ProductID != 0 && review.ProductID != f.ProductID {
+		return false
+	}
+	return f.Status == "" || review.Status == f.Status
+}
+
+// cloneReview returns a copy of review that shares no pointer with it
+func cloneReview(review models.Review) models.Review {
+	review.UserID = cloneID(review.UserID)
+	return review
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryShippingRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryShippingRepository returns a ShippingRepository that keeps data in memory
+func NewMemoryShippingRepository() ShippingRepository {
+	return &memoryShippingRepository{store: newMemoryStore()}
+}
+
+func (r *memoryShippingRepository) CreateZone(zone *models.ShippingZone) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(zone.Name, 0) {
+		return ErrDuplicate
+	}
+
+	now := time.Now()
+	zone.ID = r.store.nextID("shipping_zones")
+	zone.CreatedAt = now
+	zone.UpdatedAt = now
+	zone.Methods = nil
+	r.store.zones[zone.ID] = cloneZone(*zone)
+	zone.Methods = []models.ShippingMethod{}
+	return nil
+}
+
+func (r *memoryShippingRepository) FindZones() ([]models.ShippingZone, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	zones := []models.ShippingZone{}
+	for _, zone := range sortedValues(r.store.zones) {
+		zone = cloneZone(zone)
+		zone.Methods = r.methodsOf(zone.ID)
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+func (r *memoryShippingRepository) FindZone(id uint) (*models.ShippingZone, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	zone, ok := r.store.zones[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	zone = cloneZone(zone)
+	zone.Methods = r.methodsOf(id)
+	return &zone, nil
+}
+
+func (r *memoryShippingRepository) UpdateZone(zone *models.ShippingZone) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.zones[zone.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if r.nameTaken(zone.Name, zone.ID) {
+		return ErrDuplicate
+	}
+
+	existing.Name = zone.Name
+	existing.Regions = slices.Clone(zone.Regions)
+	existing.UpdatedAt = time.Now()
+	r.store.zones[zone.ID] = existing
+	zone.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+func (r *memoryShippingRepository) DeleteZone(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.zones[id]; !ok {
+		return ErrNotFound
+	}
+	r.deleteMethods(id)
+	delete(r.store.zones, id)
+	return nil
+}
+
+func (r *memoryShippingRepository) CreateMethod(method *models.ShippingMethod) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.zones[method.ZoneID]; !ok {
+		return ErrInvalidReference
+	}
+
+	now := time.Now()
+	method.ID = r.store.nextID("shipping_methods")
+	method.CreatedAt = now
+	method.UpdatedAt = now
+	r.store.methods[method.ID] = cloneMethod(*method)
+	return nil
+}
+
+func (r *memoryShippingRepository) FindMethod(id uint) (*models.ShippingMethod, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	method, ok := r.store.methods[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	method = cloneMethod(method)
+	return &method, nil
+}
+
+func (r *memoryShippingRepository) UpdateMethod(method *models.ShippingMethod) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.methods[method.ID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	method.ZoneID = existing.ZoneID
+	method.CreatedAt = existing.CreatedAt
+	method.UpdatedAt = time.Now()
+	r.store.methods[method.ID] = cloneMethod(*method)
+	return nil
+}
+
+func (r *memoryShippingRepository) DeleteMethod(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.methods[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.methods, id)
+	return nil
+}
+
+// nameTaken reports whether another zone already uses the name.
+// The caller must hold the lock.
+func (r *memoryShippingRepository) nameTaken(name string, exceptID uint) bool {
+	for id, zone := range r.store.zones {
+		if id != exceptID && zone.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// methodsOf returns the methods of a zone ordered by ID.
+// The caller must hold the lock.
+func (r *memoryShippingRepository) methodsOf(zoneID uint) []models.ShippingMethod {
+	methods := []models.ShippingMethod{}
+	for _, method := range sortedValues(r.store.methods) {
+		if method.ZoneID == zoneID {
+			methods = append(methods, cloneMethod(method))
+		}
+	}
+	return methods
+}
+
+// deleteMethods mirrors ON DELETE CASCADE on shipping_methods.zone_id.
+// The caller must hold the lock.
+func (r *memoryShippingRepository) deleteMethods(zoneID uint) {
+	for id, method := range r.store.methods {
+		if method.ZoneID == zoneID {
+			delete(r.store.methods, id)
+		}
+	}
+}
+
+// cloneZone returns a copy of zone that shares no slice with it
+func cloneZone(zone models.ShippingZone) models.ShippingZone {
+	zone.Regions = slices.Clone(zone.Regions)
+	zone.Methods = nil
+	return zone
+}
+
+// cloneMethod returns a copy of method that shares no pointer or slice with it
+func cloneMethod(method models.ShippingMethod) models.ShippingMethod {
+	method.Tiers = slices.Clone(method.Tiers)
+	if method.Threshold != nil {
+		threshold := *method.Threshold
+		method.Threshold = &threshold
+	}
+	return method
+}
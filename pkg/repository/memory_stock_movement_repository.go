@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryStockMovementRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryStockMovementRepository returns a StockMovementRepository that keeps data in memory
+func NewMemoryStockMovementRepository() StockMovementRepository {
+	return &memoryStockMovementRepository{store: newMemoryStore()}
+}
+
+func (r *memoryStockMovementRepository) Create(movement *models.StockMovement) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.products[movement.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+	if movement.VariantID != nil {
+		if _, ok := r.store.variants[*movement.VariantID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+	if movement.WarehouseID != nil {
+		if _, ok := r.store.warehouses[*movement.WarehouseID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+	if movement.ActorID != nil {
+		if _, ok := r.store.users[*movement.ActorID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+
+	movement.ID = r.store.nextID("stock_movements")
+	movement.CreatedAt = time.Now()
+	r.store.movements[movement.ID] = cloneMovement(*movement)
+	return nil
+}
+
+func (r *memoryStockMovementRepository) FindByProduct(productID uint) ([]models.StockMovement, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	movements := []models.StockMovement{}
+	for _, movement := range sortedValues(r.store.movements) {
+		if movement.ProductID == productID {
+			movements = append(movements, cloneMovement(movement))
+		}
+	}
+	return movements, nil
+}
+
+// cloneMovement returns a copy of movement that shares no pointer with it
+func cloneMovement(movement models.StockMovement) models.StockMovement {
+	movement.VariantID = cloneID(movement.VariantID)
+	movement.WarehouseID = cloneID(movement.WarehouseID)
+	movement.ActorID = cloneID(movement.ActorID)
+	return movement
+}
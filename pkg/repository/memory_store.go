@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"maps"
+	"sort"
+	"sync"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+// rwLocker is the locking interface of sync.RWMutex
+type rwLocker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// noopLocker is used inside a transaction, which already holds the lock
+type noopLocker struct{}
+
+func (noopLocker) Lock()    {}
+func (noopLocker) Unlock()  {}
+func (noopLocker) RLock()   {}
+func (noopLocker) RUnlock() {}
+
+// memoryStore holds the tables shared by the in-memory repositories.
+// Records are stored by value so callers never alias stored data.
+type memoryStore struct {
+	mu         rwLocker
+	products   map[uint]models.Product
+	categories map[uint]models.Category
+	variants   map[uint]models.Variant
+	movements  map[uint]models.StockMovement
+	reserved   map[uint]models.Reservation
+	warehouses map[uint]models.Warehouse
+	levels     map[levelKey]models.WarehouseStock
+	transfers  map[uint]models.Transfer
+	rates      map[string]models.ExchangeRate
+	prices     map[priceKey]models.ProductPrice
+	history    map[uint]models.PriceChange
+	schedules  map[uint]models.ScheduledPrice
+	discounts  map[uint]models.DiscountRule
+	coupons    map[uint]models.Coupon
+	redeemed   map[uint]models.CouponRedemption
+	carts      map[uint]models.CartItem
+	orders     map[uint]models.Order
+	timeline   map[uint]models.OrderTransition
+	payments   map[uint]models.Payment
+	events     map[uint]models.PaymentEvent
+	invoices   map[uint]models.Invoice
+	classes    map[uint]models.TaxClass
+	taxrates   map[taxRateKey]models.TaxRate
+	zones      map[uint]models.ShippingZone
+	methods    map[uint]models.ShippingMethod
+	reviews    map[uint]models.Review
+	wishlists  map[uint]models.Wishlist
+	wished     map[uint]models.WishlistItem
+	users      map[uint]models.User
+	lastID     map[string]uint
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		mu:         &sync.RWMutex{},
+		products:   make(map[uint]models.Product),
+		categories: make(map[uint]models.Category),
+		variants:   make(map[uint]models.Variant),
+		movements:  make(map[uint]models.StockMovement),
+		reserved:   make(map[uint]models.Reservation),
+		warehouses: make(map[uint]models.Warehouse),
+		levels:     make(map[levelKey]models.WarehouseStock),
+		transfers:  make(map[uint]models.Transfer),
+		rates:      make(map[string]models.ExchangeRate),
+		prices:     make(map[priceKey]models.ProductPrice),
+		history:    make(map[uint]models.PriceChange),
+		schedules:  make(map[uint]models.ScheduledPrice),
+		discounts:  make(map[uint]models.DiscountRule),
+		coupons:    make(map[uint]models.Coupon),
+		redeemed:   make(map[uint]models.CouponRedemption),
+		carts:      make(map[uint]models.CartItem),
+		orders:     make(map[uint]models.Order),
+		timeline:   make(map[uint]models.OrderTransition),
+		payments:   make(map[uint]models.Payment),
+		events:     make(map[uint]models.PaymentEvent),
+		invoices:   make(map[uint]models.Invoice),
+		classes:    make(map[uint]models.TaxClass),
+		taxrates:   make(map[taxRateKey]models.TaxRate),
+		zones:      make(map[uint]models.ShippingZone),
+		methods:    make(map[uint]models.ShippingMethod),
+		reviews:    make(map[uint]models.Review),
+		wishlists:  make(map[uint]models.Wishlist),
+		wished:     make(map[uint]models.WishlistItem),
+		users:      make(map[uint]models.User),
+		lastID:     make(map[string]uint),
+	}
+}
+
+// transaction runs fn against a view of the store that holds the write
+// lock for its whole duration. If fn fails every table is restored to
+// the state it had before fn ran.
+func (s *memoryStore) transaction(fn func(tx *memoryStore) error) error {
+	if _, inTx := s.mu.(noopLocker); inTx {
+		return fn(s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := *s
+	tx.mu = noopLocker{}
+
+	restore := s.snapshot()
+	if err := fn(&tx); err != nil {
+		restore()
+		return err
+	}
+	return nil
+}
+
+// snapshot copies every table and returns a function that puts the
+// copies back in place. The caller must hold the write lock.
+func (s *memoryStore) snapshot() func() {
+	products := maps.Clone(s.products)
+	categories := maps.Clone(s.categories)
+	variants := maps.Clone(s.variants)
+	movements := maps.Clone(s.movements)
+	reserved := maps.Clone(s.reserved)
+	warehouses := maps.Clone(s.warehouses)
+	levels := maps.Clone(s.levels)
+	transfers := maps.Clone(s.transfers)
+	rates := maps.Clone(s.rates)
+	prices := maps.Clone(s.prices)
+	history := maps.Clone(s.history)
+	schedules := maps.Clone(s.schedules)
+	discounts := maps.Clone(s.discounts)
+	coupons := maps.Clone(s.coupons)
+	redeemed := maps.Clone(s.redeemed)
+	carts := maps.Clone(s.carts)
+	orders := maps.Clone(s.orders)
+	timeline := maps.Clone(s.timeline)
+	payments := maps.Clone(s.payments)
+	events := maps.Clone(s.events)
+	invoices := maps.Clone(s.invoices)
+	classes := maps.Clone(s.classes)
+	taxrates := maps.Clone(s.taxrates)
+	zones := maps.Clone(s.zones)
+	methods := maps.Clone(s.methods)
+	reviews := maps.Clone(s.reviews)
+	wishlists := maps.Clone(s.wishlists)
+	wished := maps.Clone(s.wished)
+	users := maps.Clone(s.users)
+	lastID := maps.Clone(s.lastID)
+
+	return func() {
+		replace(s.products, products)
+		replace(s.categories, categories)
+		replace(s.variants, variants)
+		replace(s.movements, movements)
+		replace(s.reserved, reserved)
+		replace(s.warehouses, warehouses)
+		replace(s.levels, levels)
+		replace(s.transfers, transfers)
+		replace(s.rates, rates)
+		replace(s.prices, prices)
+		replace(s.history, history)
+		replace(s.schedules, schedules)
+		replace(s.discounts, discounts)
+		replace(s.coupons, coupons)
+		replace(s.redeemed, redeemed)
+		replace(s.carts, carts)
+		replace(s.orders, orders)
+		replace(s.timeline, timeline)
+		replace(s.payments, payments)
+		replace(s.events, events)
+		replace(s.invoices, invoices)
+		replace(s.classes, classes)
+		replace(s.taxrates, taxrates)
+		replace(s.zones, zones)
+		replace(s.methods, methods)
+		replace(s.reviews, reviews)
+		replace(s.wishlists, wishlists)
+		replace(s.wished, wished)
+		replace(s.users, users)
+		replace(s.lastID, lastID)
+	}
+}
+
+// replace overwrites the contents of dst with src, keeping the map
+// itself so every view of the store sees the change.
+func replace[K comparable, V any](dst, src map[K]V) {
+	clear(dst)
+	maps.Copy(dst, src)
+}
+
+// nextID returns the next auto-increment ID for the given table.
+// The caller must hold the write lock.
+func (s *memoryStore) nextID(table string) uint {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// sortedValues returns the values of a table ordered by ID, matching
+// the default ordering of the database backend.
+func sortedValues[T any](table map[uint]T) []T {
+	ids := make([]uint, 0, len(table))
+	for id := range table {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	values := make([]T, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, table[id])
+	}
+	return values
+}
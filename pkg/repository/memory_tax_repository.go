@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+// taxRateKey is the primary key of a tax rate
+type taxRateKey struct {
+	classID uint
+	region  string
+}
+
+type memoryTaxRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryTaxRepository returns a TaxRepository that keeps data in memory
+func NewMemoryTaxRepository() TaxRepository {
+	return &memoryTaxRepository{store: newMemoryStore()}
+}
+
+func (r *memoryTaxRepository) CreateClass(class *models.TaxClass) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(class.Name, 0) {
+		return ErrDuplicate
+	}
+
+	now := time.Now()
+	class.ID = r.store.nextID("tax_classes")
+	class.CreatedAt = now
+	class.UpdatedAt = now
+	class.Rates = nil
+	r.store.classes[class.ID] = *class
+	class.Rates = []models.TaxRate{}
+	return nil
+}
+
+func (r *memoryTaxRepository) FindClasses() ([]models.TaxClass, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	classes := []models.TaxClass{}
+	for _, class := range sortedValues(r.store.classes) {
+		class.Rates = r.ratesOf(class.ID)
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+func (r *memoryTaxRepository) FindClass(id uint) (*models.TaxClass, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	class, ok := r.store.classes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	class.Rates = r.ratesOf(id)
+	return &class, nil
+}
+
+func (r *memoryTaxRepository) UpdateClass(class *models.TaxClass) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.classes[class.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if r.nameTaken(class.Name, class.ID) {
+		return ErrDuplicate
+	}
+
+	existing.Name = class.Name
+	existing.UpdatedAt = time.Now()
+	r.store.classes[class.ID] = existing
+	class.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+func (r *memoryTaxRepository) DeleteClass(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.classes[id]; !ok {
+		return ErrNotFound
+	}
+	r.deleteRates(id)
+	r.detachCategories(id)
+	r.detachProducts(id)
+	delete(r.store.classes, id)
+	return nil
+}
+
+func (r *memoryTaxRepository) SaveRate(rate *models.TaxRate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.classes[rate.TaxClassID]; !ok {
+		return ErrInvalidReference
+	}
+	rate.UpdatedAt = time.Now()
+	r.store.taxrates[taxRateKey{classID: rate.TaxClassID, region: rate.Region}] = *rate
+	return nil
+}
+
+func (r *memoryTaxRepository) DeleteRate(classID uint, region string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := taxRateKey{classID: classID, region: region}
+	if _, ok := r.store.taxrates[key]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.taxrates, key)
+	return nil
+}
+
+func (r *memoryTaxRepository) FindRatesByRegion(region string) ([]models.TaxRate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rates := []models.TaxRate{}
+	for key, rate := range r.store.taxrates {
+		if key.region == region {
+			rates = append(rates, rate)
+		}
+	}
+	slices.SortFunc(rates, func(a, b models.TaxRate) int {
+		return int(a.TaxClassID) - int(b.TaxClassID)
+	})
+	return rates, nil
+}
+
+// nameTaken reports whether another tax class already uses the name.
+// The caller must hold the lock.
+func (r *memoryTaxRepository) nameTaken(name string, exceptID uint) bool {
+	for id, class := range r.store.classes {
+		if id != exceptID && class.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ratesOf returns the rates of a class ordered by region.
+// The caller must hold the lock.
+func (r *memoryTaxRepository) ratesOf(classID uint) []models.TaxRate {
+	rates := []models.TaxRate{}
+	for key, rate := range r.store.taxrates {
+		if key.classID == classID {
+			rates = append(rates, rate)
+		}
+	}
+	slices.SortFunc(rates, func(a, b models.TaxRate) int {
+		return strings.Compare(a.Region, b.Region)
+	})
+	return rates
+}
+
+// deleteRates mirrors ON DELETE CASCADE on tax_rates.tax_class_id.
+// The caller must hold the lock.
+func (r *memoryTaxRepository) deleteRates(classID uint) {
+	for key := range r.store.taxrates {
+		if key.classID == classID {
+			delete(r.store.taxrates, key)
+		}
+	}
+}
+
+// detachCategories mirrors ON DELETE SET NULL on categories.tax_class_id.
+// The caller must hold the lock.
+func (r *memoryTaxRepository) detachCategories(classID uint) {
+	for id, category := range r.store.categories {
+		if category.TaxClassID != nil && *category.TaxClassID == classID {
+			category.TaxClassID = nil
+			r.store.categories[id] = category
+		}
+	}
+}
+
+// detachProducts mirrors ON DELETE SET NULL on products.tax_class_id.
+// The caller must hold the lock.
+func (r *memoryTaxRepository) detachProducts(classID uint) {
+	for id, product := range r.store.products {
+		if product.TaxClassID != nil && *product.TaxClassID == classID {
+			product.TaxClassID = nil
+			r.store.products[id] = product
+		}
+	}
+}
+
+// taxClassExists mirrors the foreign keys on categories.tax_class_id and
+// products.tax_class_id. The caller must hold the lock.
+func (s *memoryStore) taxClassExists(classID *uint) bool {
+	if classID == nil {
+		return true
+	}
+	_, ok := s.classes[*classID]
+	return ok
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryTransferRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryTransferRepository returns a TransferRepository that keeps data in memory
+func NewMemoryTransferRepository() TransferRepository {
+	return &memoryTransferRepository{store: newMemoryStore()}
+}
+
+func (r *memoryTransferRepository) Create(transfer *models.Transfer) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.products[transfer.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+	for _, warehouseID := range []*uint{transfer.FromWarehouseID, transfer.ToWarehouseID} {
+		if warehouseID == nil {
+			continue
+		}
+		if _, ok := r.store.warehouses[*warehouseID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+	if transfer.ActorID != nil {
+		if _, ok := r.store.users[*transfer.ActorID]; !ok {
+			return ErrInvalidReference
+		}
+	}
+
+	transfer.ID = r.store.nextID("transfers")
+	transfer.CreatedAt = time.Now()
+	r.store.transfers[transfer.ID] = cloneTransfer(*transfer)
+	return nil
+}
+
+func (r *memoryTransferRepository) FindByID(id uint) (*models.Transfer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	transfer, ok := r.store.transfers[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	transfer = cloneTransfer(transfer)
+	return &transfer, nil
+}
+
+func (r *memoryTransferRepository) FindByProduct(productID uint) ([]models.Transfer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	transfers := []models.Transfer{}
+	for _, transfer := range sortedValues(r.store.transfers) {
+		if transfer.ProductID == productID {
+			transfers = append(transfers, cloneTransfer(transfer))
+		}
+	}
+	return transfers, nil
+}
+
+// cloneTransfer returns a copy of transfer that shares no pointer with it
+func cloneTransfer(transfer models.Transfer) models.Transfer {
+	transfer.FromWarehouseID = cloneID(transfer.FromWarehouseID)
+	transfer.ToWarehouseID = cloneID(transfer.ToWarehouseID)
+	transfer.ActorID = cloneID(transfer.ActorID)
+	return transfer
+}
+
+func cloneID(id *uint) *uint {
+	if id == nil {
+		return nil
+	}
+	copied := *id
+	return &copied
+}
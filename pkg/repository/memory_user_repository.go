@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type memoryUserRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryUserRepository returns a UserRepository that keeps data in memory
+func NewMemoryUserRepository() UserRepository {
+	return &memoryUserRepository{store: newMemoryStore()}
+}
+
+func (r *memoryUserRepository) Create(user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.emailTaken(user.Email, 0) {
+		return ErrDuplicate
+	}
+
+	now := time.Now()
+	user.ID = r.store.nextID("users")
+	user.Version = 1
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUserRepository) FindAll() ([]models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range sortedValues(r.store.users) {
+		if !user.DeletedAt.Valid {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) FindByID(id uint) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *memoryUserRepository) FindByEmail(email string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range sortedValues(r.store.users) {
+		if user.Email == email && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepository) Update(user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.users[user.ID]
+	if !ok || existing.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if existing.Version != user.Version {
+		return ErrVersionConflict
+	}
+	if r.emailTaken(user.Email, user.ID) {
+		return ErrDuplicate
+	}
+
+	user.CreatedAt = existing.CreatedAt
+	user.DeletedAt = existing.DeletedAt
+	user.UpdatedAt = time.Now()
+	user.Version++
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(id uint, version uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if version != 0 && user.Version != version {
+		return ErrVersionConflict
+	}
+	user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.store.users[id] = user
+	return nil
+}
+
+func (r *memoryUserRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, user := range r.store.users {
+		if user.DeletedAt.Valid && user.DeletedAt.Time.Before(before) {
+			delete(r.store.users, id)
+			r.detachMovements(id)
+			r.detachReservations(id)
+			r.detachTransfers(id)
+			r.detachPriceHistory(id)
+			r.detachSchedules(id)
+			r.detachRedemptions(id)
+			r.deleteCartItems(id)
+			r.detachOrders(id)
+			r.detachOrderTransitions(id)
+			r.detachReviews(id)
+			r.deleteWishlists(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// detachMovements mirrors ON DELETE SET NULL on stock_movements.actor_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) detachMovements(userID uint) {
+	for id, movement := range r.store.movements {
+		if movement.ActorID != nil && *movement.ActorID == userID {
+			movement.ActorID = nil
+			r.store.movements[id] = movement
+		}
+	}
+}
+
+// detachReservations mirrors ON DELETE SET NULL on reservations.user_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) detachReservations(userID uint) {
+	for id, reservation := range r.store.reserved {
+		if reservation.UserID != nil && *reservation.UserID == userID {
+			reservation.UserID = nil
+			r.store.reserved[id] = reservation
+		}
+	}
+}
+
+// detachTransfers mirrors ON DELETE SET NULL on transfers.actor_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) detachTransfers(userID uint) {
+	for id, transfer := range r.store.transfers {
+		if transfer.ActorID != nil && *transfer.ActorID == userID {
+			transfer.ActorID = nil
+			r.store.transfers[id] = transfer
+		}
+	}
+}
+
+// detachPriceHistory mirrors ON DELETE SET NULL on price_history.actor_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) detachPriceHistory(userID uint) {
+	for id, change := range r.store.history {
+		if change.ActorID != nil && *change.ActorID == userID {
+			change.ActorID = nil
+			r.store.history[id] = change
+		}
+	}
+}
+
+// detachSchedules mirrors ON DELETE SET NULL on scheduled_prices.actor_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) detachSchedules(userID uint) {
+	for id, schedule := range r.store.schedules {
+		if schedule.ActorID != nil && *schedule.ActorID == userID {
+			schedule.ActorID = nil
+			r.store.schedules[id] = schedule
+		}
+	}
+}
+
+// detachRedemptions mirrors ON DELETE SET NULL on coupon_redemptions.user_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) detachRedemptions(userID uint) {
+	for id, redemption := range r.store.redeemed {
+		if redemption.UserID != nil && *redemption.UserID == userID {
+			redemption.UserID = nil
+			r.store.redeemed[id] = redemption
+		}
+	}
+}
+
+// deleteCartItems mirrors ON DELETE CASCADE on cart_items.user_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) deleteCartItems(userID uint) {
+	for id, item := range r.store.carts {
+		if item.UserID == userID {
+			delete(r.store.carts, id)
+		}
+	}
+}
+
+// detachOrders mirrors ON DELETE SET NULL on orders.user_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) detachOrders(userID uint) {
+	for id, order := range r.store.orders {
+		if order.UserID != nil && *order.UserID == userID {
+			order.UserID = nil
+			r.store.orders[id] = order
+		}
+	}
+}
+
+// detachOrderTransitions mirrors ON DELETE SET NULL on
+// order_transitions.actor_id. The caller must hold the lock.
+func (r *memoryUserRepository) detachOrderTransitions(userID uint) {
+	for id, transition := range r.store.timeline {
+		if transition.ActorID != nil && *transition.ActorID == userID {
+			transition.ActorID = nil
+			r.store.timeline[id] = transition
+		}
+	}
+}
+
+// detachReviews mirrors ON DELETE SET NULL on reviews.user_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) detachReviews(userID uint) {
+	for id, review := range r.store.reviews {
+		if review.UserID != nil && *review.UserID == userID {
+			review.UserID = nil
+			r.store.reviews[id] = review
+		}
+	}
+}
+
+// deleteWishlists mirrors ON DELETE CASCADE on wishlists.user_id and, for
+// the wishlists it deletes, on wishlist_items.wishlist_id.
+// The caller must hold the lock.
+func (r *memoryUserRepository) deleteWishlists(userID uint) {
+	for id, wishlist := range r.store.wishlists {
+		if wishlist.UserID != userID {
+			continue
+		}
+		delete(r.store.wishlists, id)
+		for itemID, item := range r.store.wished {
+			if item.WishlistID == id {
+				delete(r.store.wished, itemID)
+			}
+		}
+	}
+}
+
+// emailTaken reports whether another user already uses the email.
+// The caller must hold the lock.
+func (r *memoryUserRepository) emailTaken(email string, exceptID uint) bool {
+	for id, user := range r.store.users {
+		if id != exceptID && user.Email == email {
+			return true
+		}
+	}
+	return false
+}
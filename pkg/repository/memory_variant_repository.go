@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type memoryVariantRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryVariantRepository returns a VariantRepository that keeps data in memory
+func NewMemoryVariantRepository() VariantRepository {
+	return &memoryVariantRepository{store: newMemoryStore()}
+}
+
+func (r *memoryVariantRepository) Create(variant *models.Variant) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.skuTaken(variant.SKU, 0) {
+		return ErrDuplicate
+	}
+	if _, ok := r.store.products[variant.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+
+	now := time.Now()
+	variant.ID = r.store.nextID("variants")
+	variant.Version = 1
+	variant.CreatedAt = now
+	variant.UpdatedAt = now
+	r.store.variants[variant.ID] = cloneVariant(*variant)
+	return nil
+}
+
+func (r *memoryVariantRepository) FindByProduct(productID uint) ([]models.Variant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	variants := []models.Variant{}
+	for _, variant := range sortedValues(r.store.variants) {
+		if variant.ProductID == productID && !variant.DeletedAt.Valid {
+			variants = append(variants, cloneVariant(variant))
+		}
+	}
+	return variants, nil
+}
+
+func (r *memoryVariantRepository) FindByID(id uint) (*models.Variant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	variant, ok := r.store.variants[id]
+	if !ok || variant.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	variant = cloneVariant(variant)
+	return &variant, nil
+}
+
+func (r *memoryVariantRepository) FindBySKU(sku string) (*models.Variant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, variant := range sortedValues(r.store.variants) {
+		if variant.SKU == sku && !variant.DeletedAt.Valid {
+			variant = cloneVariant(variant)
+			return &variant, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryVariantRepository) Update(variant *models.Variant) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.variants[variant.ID]
+	if !ok || existing.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if existing.Version != variant.Version {
+		return ErrVersionConflict
+	}
+	if r.skuTaken(variant.SKU, variant.ID) {
+		return ErrDuplicate
+	}
+	if _, ok := r.store.products[variant.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+
+	variant.CreatedAt = existing.CreatedAt
+	variant.DeletedAt = existing.DeletedAt
+	variant.UpdatedAt = time.Now()
+	variant.Version++
+	r.store.variants[variant.ID] = cloneVariant(*variant)
+	return nil
+}
+
+func (r *memoryVariantRepository) AdjustQty(id uint, delta int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	variant, ok := r.store.variants[id]
+	if !ok || variant.DeletedAt.Valid {
+		return 0, ErrNotFound
+	}
+	if variant.Qty+delta < 0 {
+		return variant.Qty, ErrInsufficientStock
+	}
+	variant.Qty += delta
+	variant.UpdatedAt = time.Now()
+	variant.Version++
+	r.store.variants[id] = variant
+	return variant.Qty, nil
+}
+
+func (r *memoryVariantRepository) Restock(id uint, delta int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	variant, ok := r.store.variants[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if variant.Qty+delta < 0 {
+		return variant.Qty, ErrInsufficientStock
+	}
+	variant.Qty += delta
+	variant.UpdatedAt = time.Now()
+	variant.Version++
+	r.store.variants[id] = variant
+	return variant.Qty, nil
+}
+
+func (r *memoryVariantRepository) Delete(id uint, version uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	variant, ok := r.store.variants[id]
+	if !ok || variant.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if version != 0 && variant.Version != version {
+		return ErrVersionConflict
+	}
+	variant.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.store.variants[id] = variant
+	return nil
+}
+
+func (r *memoryVariantRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, variant := range r.store.variants {
+		if variant.DeletedAt.Valid && variant.DeletedAt.Time.Before(before) {
+			delete(r.store.variants, id)
+			r.deleteMovements(id)
+			r.deleteCartItems(id)
+			r.detachOrderItems(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// deleteMovements mirrors ON DELETE CASCADE on stock_movements.variant_id.
+// The caller must hold the lock.
+func (r *memoryVariantRepository) deleteMovements(variantID uint) {
+	for id, movement := range r.store.movements {
+		if movement.VariantID != nil && *movement.VariantID == variantID {
+			delete(r.store.movements, id)
+		}
+	}
+}
+
+// deleteCartItems mirrors ON DELETE CASCADE on cart_items.variant_id.
+// The caller must hold the lock.
+func (r *memoryVariantRepository) deleteCartItems(variantID uint) {
+	for id, item := range r.store.carts {
+		if item.VariantID != nil && *item.VariantID == variantID {
+			delete(r.store.carts, id)
+		}
+	}
+}
+
+// detachOrderItems mirrors ON DELETE SET NULL on order_items.variant_id.
+// The caller must hold the lock.
+func (r *memoryVariantRepository) detachOrderItems(variantID uint) {
+	for id, order := range r.store.orders {
+		detached := false
+		items := slices.Clone(order.Items)
+		for i, item := range items {
+			if item.VariantID != nil && *item.VariantID == variantID {
+				items[i].VariantID = nil
+				detached = true
+			}
+		}
+		if detached {
+			order.Items = items
+			r.store.orders[id] = order
+		}
+	}
+}
+
+// skuTaken reports whether another variant already uses the SKU.
+// The caller must hold the lock.
+func (r *memoryVariantRepository) skuTaken(sku string, exceptID uint) bool {
+	for id, variant := range r.store.variants {
+		if id != exceptID && variant.SKU == sku {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneVariant returns a copy of variant that shares no pointer or map with it
+func cloneVariant(variant models.Variant) models.Variant {
+	variant.Options = maps.Clone(variant.Options)
+	if variant.Price != nil {
+		price := *variant.Price
+		variant.Price = &price
+	}
+	return variant
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+// levelKey is the primary key of a warehouse stock level
+type levelKey struct {
+	productID   uint
+	warehouseID uint
+}
+
+type memoryWarehouseRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryWarehouseRepository returns a WarehouseRepository that keeps data in memory
+func NewMemoryWarehouseRepository() WarehouseRepository {
+	return &memoryWarehouseRepository{store: newMemoryStore()}
+}
+
+func (r *memoryWarehouseRepository) Create(warehouse *models.Warehouse) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(warehouse.Name, 0) {
+		return ErrDuplicate
+	}
+
+	now := time.Now()
+	warehouse.ID = r.store.nextID("warehouses")
+	warehouse.Version = 1
+	warehouse.CreatedAt = now
+	warehouse.UpdatedAt = now
+	r.store.warehouses[warehouse.ID] = *warehouse
+	return nil
+}
+
+func (r *memoryWarehouseRepository) FindAll() ([]models.Warehouse, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	warehouses := []models.Warehouse{}
+	for _, warehouse := range sortedValues(r.store.warehouses) {
+		if !warehouse.DeletedAt.Valid {
+			warehouses = append(warehouses, warehouse)
+		}
+	}
+	return warehouses, nil
+}
+
+func (r *memoryWarehouseRepository) FindByID(id uint) (*models.Warehouse, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	warehouse, ok := r.store.warehouses[id]
+	if !ok || warehouse.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	return &warehouse, nil
+}
+
+func (r *memoryWarehouseRepository) FindByName(name string) (*models.Warehouse, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, warehouse := range sortedValues(r.store.warehouses) {
+		if warehouse.Name == name && !warehouse.DeletedAt.Valid {
+			return &warehouse, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryWarehouseRepository) Update(warehouse *models.Warehouse) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.warehouses[warehouse.ID]
+	if !ok || existing.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if existing.Version != warehouse.Version {
+		return ErrVersionConflict
+	}
+	if r.nameTaken(warehouse.Name, warehouse.ID) {
+		return ErrDuplicate
+	}
+
+	warehouse.CreatedAt = existing.CreatedAt
+	warehouse.DeletedAt = existing.DeletedAt
+	warehouse.UpdatedAt = time.Now()
+	warehouse.Version++
+	r.store.warehouses[warehouse.ID] = *warehouse
+	return nil
+}
+
+func (r *memoryWarehouseRepository) Delete(id uint, version uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	warehouse, ok := r.store.warehouses[id]
+	if !ok || warehouse.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if version != 0 && warehouse.Version != version {
+		return ErrVersionConflict
+	}
+	warehouse.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.store.warehouses[id] = warehouse
+	return nil
+}
+
+func (r *memoryWarehouseRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, warehouse := range r.store.warehouses {
+		if warehouse.DeletedAt.Valid && warehouse.DeletedAt.Time.Before(before) {
+			delete(r.store.warehouses, id)
+			r.deleteLevels(id)
+			r.detachTransfers(id)
+			r.detachMovements(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// Lock is a no-op: a memory transaction already holds the store's write lock
+func (r *memoryWarehouseRepository) Lock(ids ...uint) error {
+	return nil
+}
+
+func (r *memoryWarehouseRepository) FindLevels(productIDs []uint) ([]models.WarehouseStock, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	levels := []models.WarehouseStock{}
+	for _, level := range r.store.levels {
+		if slices.Contains(productIDs, level.ProductID) {
+			levels = append(levels, level)
+		}
+	}
+	slices.SortFunc(levels, func(a, b models.WarehouseStock) int {
+		if a.ProductID != b.ProductID {
+			return int(a.ProductID) - int(b.ProductID)
+		}
+		return int(a.WarehouseID) - int(b.WarehouseID)
+	})
+	return levels, nil
+}
+
+func (r *memoryWarehouseRepository) AdjustLevel(productID, warehouseID uint, delta int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := levelKey{productID: productID, warehouseID: warehouseID}
+	level, ok := r.store.levels[key]
+	if !ok {
+		if _, ok := r.store.products[productID]; !ok {
+			return 0, ErrInvalidReference
+		}
+		if _, ok := r.store.warehouses[warehouseID]; !ok {
+			return 0, ErrInvalidReference
+		}
+		level = models.WarehouseStock{ProductID: productID, WarehouseID: warehouseID}
+	}
+	if level.Qty+delta < 0 {
+		return level.Qty, ErrInsufficientStock
+	}
+	level.Qty += delta
+	level.UpdatedAt = time.Now()
+	r.store.levels[key] = level
+	return level.Qty, nil
+}
+
+func (r *memoryWarehouseRepository) StockInWarehouse(warehouseID uint) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var total int64
+	for key, level := range r.store.levels {
+		if key.warehouseID == warehouseID {
+			total += int64(level.Qty)
+		}
+	}
+	return total, nil
+}
+
+// nameTaken reports whether another warehouse already uses the name.
+// The caller must hold the lock.
+func (r *memoryWarehouseRepository) nameTaken(name string, exceptID uint) bool {
+	for id, warehouse := range r.store.warehouses {
+		if id != exceptID && warehouse.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteLevels mirrors ON DELETE CASCADE on warehouse_stocks.warehouse_id.
+// The caller must hold the lock.
+func (r *memoryWarehouseRepository) deleteLevels(warehouseID uint) {
+	for key := range r.store.levels {
+		if key.warehouseID == warehouseID {
+			delete(r.store.levels, key)
+		}
+	}
+}
+
+// detachTransfers mirrors ON DELETE SET NULL on the warehouse columns of
+// transfers. The caller must hold the lock.
+func (r *memoryWarehouseRepository) detachTransfers(warehouseID uint) {
+	for id, transfer := range r.store.transfers {
+		if isWarehouse(transfer.FromWarehouseID, warehouseID) {
+			transfer.FromWarehouseID = nil
+		}
+		if isWarehouse(transfer.ToWarehouseID, warehouseID) {
+			transfer.ToWarehouseID = nil
+		}
+		r.store.transfers[id] = transfer
+	}
+}
+
+// detachMovements mirrors ON DELETE SET NULL on stock_movements.warehouse_id.
+// The caller must hold the lock.
+func (r *memoryWarehouseRepository) detachMovements(warehouseID uint) {
+	for id, movement := range r.store.movements {
+		if isWarehouse(movement.WarehouseID, warehouseID) {
+			movement.WarehouseID = nil
+			r.store.movements[id] = movement
+		}
+	}
+}
+
+func isWarehouse(id *uint, warehouseID uint) bool {
+	return id != nil && *id == warehouseID
+}
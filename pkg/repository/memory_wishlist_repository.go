@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+type memoryWishlistRepository struct {
+	store *memoryStore
+}
+
+// NewMemoryWishlistRepository returns a WishlistRepository that keeps data in memory
+func NewMemoryWishlistRepository() WishlistRepository {
+	return &memoryWishlistRepository{store: newMemoryStore()}
+}
+
+func (r *memoryWishlistRepository) Create(wishlist *models.Wishlist) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[wishlist.UserID]; !ok {
+		return ErrInvalidReference
+	}
+	if r.taken(*wishlist, 0) {
+		return ErrDuplicate
+	}
+
+	now := time.Now()
+	wishlist.ID = r.store.nextID("wishlists")
+	wishlist.CreatedAt = now
+	wishlist.UpdatedAt = now
+	wishlist.Items = nil
+	r.store.wishlists[wishlist.ID] = cloneWishlist(*wishlist)
+	wishlist.Items = []models.WishlistItem{}
+	return nil
+}
+
+func (r *memoryWishlistRepository) FindByUser(userID uint) ([]models.Wishlist, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wishlists := []models.Wishlist{}
+	for _, wishlist := range sortedValues(r.store.wishlists) {
+		if wishlist.UserID == userID {
+			wishlists = append(wishlists, r.withItems(wishlist))
+		}
+	}
+	return wishlists, nil
+}
+
+func (r *memoryWishlistRepository) FindByID(id uint) (*models.Wishlist, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wishlist, ok := r.store.wishlists[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	wishlist = r.withItems(wishlist)
+	return &wishlist, nil
+}
+
+func (r *memoryWishlistRepository) FindByShareToken(token string) (*models.Wishlist, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, wishlist := range r.store.wishlists {
+		if wishlist.ShareToken != nil && *wishlist.ShareToken == token {
+			wishlist = r.withItems(wishlist)
+			return &wishlist, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryWishlistRepository) Update(wishlist *models.Wishlist) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.wishlists[wishlist.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	candidate := existing
+	candidate.Name = wishlist.Name
+	candidate.ShareToken = wishlist.ShareToken
+	if r.taken(candidate, wishlist.ID) {
+		return ErrDuplicate
+	}
+
+	candidate.UpdatedAt = time.Now()
+	r.store.wishlists[wishlist.ID] = cloneWishlist(candidate)
+	wishlist.UpdatedAt = candidate.UpdatedAt
+	return nil
+}
+
+func (r *memoryWishlistRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.wishlists[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.wishlists, id)
+	r.deleteItems(id)
+	return nil
+}
+
+func (r *memoryWishlistRepository) AddItem(item *models.WishlistItem) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.wishlists[item.WishlistID]; !ok {
+		return ErrInvalidReference
+	}
+	if _, ok := r.store.products[item.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+	if _, ok := r.find(item.WishlistID, item.ProductID); ok {
+		return ErrDuplicate
+	}
+
+	item.ID = r.store.nextID("wishlist_items")
+	item.CreatedAt = time.Now()
+	item.Product = nil
+	r.store.wished[item.ID] = *item
+	return nil
+}
+
+func (r *memoryWishlistRepository) RemoveItem(wishlistID, productID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	id, ok := r.find(wishlistID, productID)
+	if !ok {
+		return ErrNotFound
+	}
+	delete(r.store.wished, id)
+	return nil
+}
+
+// withItems returns a copy of wishlist with its items of products outside
+// the trash, ordered by ID.
+// The caller must hold the lock.
+func (r *memoryWishlistRepository) withItems(wishlist models.Wishlist) models.Wishlist {
+	wishlist = cloneWishlist(wishlist)
+	wishlist.Items = []models.WishlistItem{}
+	for _, item := range sortedValues(r.store.wished) {
+		if item.WishlistID != wishlist.ID {
+			continue
+		}
+		if product, ok := r.store.products[item.ProductID]; ok && !product.DeletedAt.Valid {
+			wishlist.Items = append(wishlist.Items, item)
+		}
+	}
+	return wishlist
+}
+
+// taken reports whether another wishlist than the one with the given ID
+// has the name of wishlist for the same user, or its share token. It
+// mirrors the unique indexes of the wishlists table.
+// The caller must hold the lock.
+func (r *memoryWishlistRepository) taken(wishlist models.Wishlist, id uint) bool {
+	for _, other := range r.store.wishlists {
+		if other.ID == id {
+			continue
+		}
+		if other.UserID == wishlist.UserID && other.Name == wishlist.Name {
+			return true
+		}
+		if other.ShareToken != nil && wishlist.ShareToken != nil && *other.ShareToken == *wishlist.ShareToken {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the ID of the item for the product on the wishlist.
+// The caller must hold the lock.
+func (r *memoryWishlistRepository) find(wishlistID, productID uint) (uint, bool) {
+	for id, item := range r.store.wished {
+		if item.WishlistID == wishlistID && item.ProductID == productID {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// deleteItems mirrors ON DELETE CASCADE on wishlist_items.wishlist_id.
+// The caller must hold the lock.
+func (r *memoryWishlistRepository) deleteItems(wishlistID uint) {
+	for id, item := range r.store.wished {
+		if item.WishlistID == wishlistID {
+			delete(r.store.wished, id)
+		}
+	}
+}
+
+// cloneWishlist returns a copy of wishlist that shares no pointer or
+// slice with it
+func cloneWishlist(wishlist models.Wishlist) models.Wishlist {
+	if wishlist.ShareToken != nil {
+		token := *wishlist.ShareToken
+		wishlist.ShareToken = &token
+	}
+	wishlist.Items = nil
+	return wishlist
+}
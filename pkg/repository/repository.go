@@ -0,0 +1,539 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNotFound is returned when the requested record does not exist.
+	ErrNotFound = errors.New("record not found")
+
+	// ErrDuplicate is returned when a unique field is already taken.
+	ErrDuplicate = errors.New("duplicate record")
+
+	// ErrInvalidReference is returned when a record points at another
+	// record that does not exist.
+	ErrInvalidReference = errors.New("referenced record does not exist")
+
+	// ErrVersionConflict is returned when a record was changed after the
+	// version the caller based its write on.
+	ErrVersionConflict = errors.New("record version conflict")
+
+	// ErrInsufficientStock is returned when a stock change would take the
+	// quantity on hand below zero.
+	ErrInsufficientStock = errors.New("insufficient stock")
+
+	// ErrLimitReached is returned when a coupon has been redeemed as
+	// often as its usage limit allows.
+	ErrLimitReached = errors.New("usage limit reached")
+)
+
+// ProductFilter narrows the products returned by FindAll.
+// A nil CategoryIDs matches products in any category or none.
+type ProductFilter struct {
+	CategoryIDs []uint
+}
+
+// ProductRepository defines the storage operations for products.
+// Update only succeeds if the stored version still equals the product's
+// version, and bumps it. Delete moves a product to the trash; a non-zero
+// version must match the stored one. PurgeDeleted removes for good
+// everything that was trashed before the given time. AdjustQty atomically
+// adds delta to the quantity on hand, bumps the version and returns the
+// new quantity. Restock does the same for a product in the trash or not,
+// so stock coming back is never lost. Lock holds the given products, in
+// the trash or not, until the surrounding transaction ends. SetRating
+// stores the rating of a product, in the trash or not, and bumps its
+// version.
+type ProductRepository interface {
+	Create(product *models.Product) error
+	FindAll(filter ProductFilter) ([]models.Product, error)
+	FindByID(id uint) (*models.Product, error)
+	FindByName(name string) (*models.Product, error)
+	Update(product *models.Product) error
+	Delete(id uint, version uint) error
+	FindDeleted() ([]models.Product, error)
+	Restore(id uint) error
+	PurgeDeleted(before time.Time) (int64, error)
+	CountByCategory(categoryID uint) (int64, error)
+	ReassignCategory(fromID, toID uint) (int64, error)
+	DeleteByCategory(categoryID uint) (int64, error)
+	AdjustQty(id uint, delta int) (int, error)
+	Restock(id uint, delta int) (int, error)
+	Lock(ids ...uint) error
+	CountByCurrency(currency string) (int64, error)
+	SetRating(id uint, average decimal.Decimal, count int) error
+}
+
+// CategoryRepository defines the storage operations for categories.
+// Path returns the ancestors of a category, root first and ending with the
+// category itself. SubtreeIDs returns the category and all its descendants.
+// Lock holds the given categories until the surrounding transaction ends.
+type CategoryRepository interface {
+	Create(category *models.Category) error
+	FindAll() ([]models.Category, error)
+	FindByID(id uint) (*models.Category, error)
+	FindByName(name string) (*models.Category, error)
+	Update(category *models.Category) error
+	Delete(id uint, version uint) error
+	PurgeDeleted(before time.Time) (int64, error)
+	Path(id uint) ([]models.Category, error)
+	SubtreeIDs(id uint) ([]uint, error)
+	CountChildren(id uint) (int64, error)
+	Lock(ids ...uint) error
+}
+
+// VariantRepository defines the storage operations for product variants.
+// SKUs are unique across all variants, including those in the trash.
+// AdjustQty atomically adds delta to the stock of a variant, bumps its
+// version and returns the new stock; it returns ErrInsufficientStock
+// instead of going below zero. Restock does the same for a variant in the
+// trash or not.
+type VariantRepository interface {
+	Create(variant *models.Variant) error
+	FindByProduct(productID uint) ([]models.Variant, error)
+	FindByID(id uint) (*models.Variant, error)
+	FindBySKU(sku string) (*models.Variant, error)
+	Update(variant *models.Variant) error
+	AdjustQty(id uint, delta int) (int, error)
+	Restock(id uint, delta int) (int, error)
+	Delete(id uint, version uint) error
+	PurgeDeleted(before time.Time) (int64, error)
+}
+
+// StockMovementRepository defines the storage operations for the stock
+// ledger. Movements are only ever appended, never changed or removed.
+type StockMovementRepository interface {
+	Create(movement *models.StockMovement) error
+	FindByProduct(productID uint) ([]models.StockMovement, error)
+}
+
+// ReservationRepository defines the storage operations for stock
+// reservations. SetStatus only changes a reservation that is still in the
+// from state and returns ErrVersionConflict otherwise. ReservedQty sums
+// the active, unexpired reservations of each product. ExpireBefore marks
+// every active reservation that ran out before the given time as expired.
+type ReservationRepository interface {
+	Create(reservation *models.Reservation) error
+	FindByID(id uint) (*models.Reservation, error)
+	SetStatus(id uint, from, to string) error
+	ReservedQty(productIDs []uint, now time.Time) (map[uint]int, error)
+	ExpireBefore(now time.Time) (int64, error)
+}
+
+// WarehouseRepository defines the storage operations for warehouses and
+// the stock levels they hold. AdjustLevel atomically adds delta to the
+// stock of a product in a warehouse and returns the new level; it returns
+// ErrInsufficientStock instead of going below zero. StockInWarehouse sums
+// the stock of every product held in a warehouse.
+type WarehouseRepository interface {
+	Create(warehouse *models.Warehouse) error
+	FindAll() ([]models.Warehouse, error)
+	FindByID(id uint) (*models.Warehouse, error)
+	FindByName(name string) (*models.Warehouse, error)
+	Update(warehouse *models.Warehouse) error
+	Delete(id uint, version uint) error
+	PurgeDeleted(before time.Time) (int64, error)
+	Lock(ids ...uint) error
+	FindLevels(productIDs []uint) ([]models.WarehouseStock, error)
+	AdjustLevel(productID, warehouseID uint, delta int) (int, error)
+	StockInWarehouse(warehouseID uint) (int64, error)
+}
+
+// TransferRepository defines the storage operations for stock transfers.
+// Like stock movements, transfers are only ever appended.
+type TransferRepository interface {
+	Create(transfer *models.Transfer) error
+	FindByID(id uint) (*models.Transfer, error)
+	FindByProduct(productID uint) ([]models.Transfer, error)
+}
+
+// CurrencyRepository defines the storage operations for exchange rates
+// and explicit per-currency product prices. SaveRate and SavePrice insert
+// the record or replace the one with the same key.
+type CurrencyRepository interface {
+	FindRates() ([]models.ExchangeRate, error)
+	FindRate(currency string) (*models.ExchangeRate, error)
+	SaveRate(rate *models.ExchangeRate) error
+	DeleteRate(currency string) error
+	FindPrices(productIDs []uint) ([]models.ProductPrice, error)
+	SavePrice(price *models.ProductPrice) error
+	DeletePrice(productID uint, currency string) error
+}
+
+// PriceHistoryRepository defines the storage operations for the price
+// history. Changes are only ever appended. FindAt returns the change in
+// force at the given time: the latest one effective at or before it.
+type PriceHistoryRepository interface {
+	Create(change *models.PriceChange) error
+	FindByProduct(productID uint) ([]models.PriceChange, error)
+	FindAt(productID uint, at time.Time) (*models.PriceChange, error)
+}
+
+// PriceScheduleRepository defines the storage operations for scheduled
+// prices. SetStatus only changes a schedule that is still in the from
+// state and returns ErrVersionConflict otherwise. FindDue returns the
+// pending schedules of products outside the trash that are due at the
+// given time, earliest first.
+type PriceScheduleRepository interface {
+	Create(schedule *models.ScheduledPrice) error
+	FindByID(id uint) (*models.ScheduledPrice, error)
+	FindByProduct(productID uint) ([]models.ScheduledPrice, error)
+	SetStatus(id uint, from, to string) error
+	FindDue(now time.Time) ([]models.ScheduledPrice, error)
+}
+
+// DiscountRuleRepository defines the storage operations for discount
+// rules. FindInForce returns the rules outside the trash whose date range
+// covers the given time.
+type DiscountRuleRepository interface {
+	Create(rule *models.DiscountRule) error
+	FindAll() ([]models.DiscountRule, error)
+	FindByID(id uint) (*models.DiscountRule, error)
+	Update(rule *models.DiscountRule) error
+	Delete(id uint, version uint) error
+	PurgeDeleted(before time.Time) (int64, error)
+	FindInForce(at time.Time) ([]models.DiscountRule, error)
+}
+
+// CouponRepository defines the storage operations for coupons and their
+// redemption ledger. Codes are unique across all coupons, including those
+// in the trash. Redeem atomically counts a redemption against the
+// coupon's usage limit, bumps its version and appends it to the ledger;
+// it returns ErrLimitReached instead of going over the limit.
+// LinkRedemption ties a redemption to the order it was made for.
+// ReleaseRedemption marks the redemption of an order released, if it has
+// one that is not already, and gives the use back to its coupon, in the
+// trash or not, bumping the coupon's version. CountRedemptions leaves
+// released redemptions out. Lock holds the given coupons until the surrounding
+// transaction ends.
+type CouponRepository interface {
+	Create(coupon *models.Coupon) error
+	FindAll() ([]models.Coupon, error)
+	FindByID(id uint) (*models.Coupon, error)
+	FindByCode(code string) (*models.Coupon, error)
+	Update(coupon *models.Coupon) error
+	Delete(id uint, version uint) error
+	PurgeDeleted(before time.Time) (int64, error)
+	Lock(ids ...uint) error
+	Redeem(redemption *models.CouponRedemption) error
+	LinkRedemption(id, orderID uint) error
+	ReleaseRedemption(orderID uint) error
+	CountRedemptions(couponID, userID uint) (int64, error)
+	FindRedemptions(couponID uint) ([]models.CouponRedemption, error)
+}
+
+// CartRepository defines the storage operations for the items in users'
+// carts. Items are found by product and variant, where a variantID of 0
+// names the product itself. FindByUser leaves out the items of products or
+// variants in the trash. Set creates the user's item for a product or
+// variant or replaces its quantity. Remove returns ErrNotFound when the
+// user has no such item.
+type CartRepository interface {
+	FindByUser(userID uint) ([]models.CartItem, error)
+	FindItem(userID, productID, variantID uint) (*models.CartItem, error)
+	Set(item *models.CartItem) error
+	Remove(userID, productID, variantID uint) error
+	Clear(userID uint) error
+}
+
+// OrderRepository defines the storage operations for orders and the
+// history of their states. Create stores an order together with its
+// items. Orders are always returned with their items; FindByUser lists a
+// user's orders newest first. SetStatus only changes an order that is
+// still in the from state and returns ErrVersionConflict otherwise. Lock
+// holds the given orders until the surrounding transaction ends.
+type OrderRepository interface {
+	Create(order *models.Order) error
+	FindByID(id uint) (*models.Order, error)
+	FindByUser(userID uint) ([]models.Order, error)
+	Lock(ids ...uint) error
+	SetStatus(id uint, from, to string) error
+	CreateTransition(transition *models.OrderTransition) error
+	FindTransitions(orderID uint) ([]models.OrderTransition, error)
+}
+
+// PaymentRepository defines the storage operations for payments and the
+// webhook events applied to them. FindByOrder lists the payments of an
+// order oldest first. SetStatus only changes a payment that is still in
+// the from state and returns ErrVersionConflict otherwise. RecordEvent
+// returns ErrDuplicate for an event that has already been recorded.
+type PaymentRepository interface {
+	Create(payment *models.Payment) error
+	FindByOrder(orderID uint) ([]models.Payment, error)
+	FindByIntent(provider, intentID string) (*models.Payment, error)
+	SetStatus(id uint, from, to string) error
+	RecordEvent(event *models.PaymentEvent) error
+}
+
+// InvoiceRepository defines the storage operations for invoices. Issue
+// gives the invoice the next number in sequence and stores it; it returns
+// ErrDuplicate when the order already has an invoice.
+type InvoiceRepository interface {
+	Issue(invoice *models.Invoice) error
+	FindByOrder(orderID uint) (*models.Invoice, error)
+}
+
+// TaxRepository defines the storage operations for tax classes and their
+// rates. Classes are returned with their rates, ordered by region.
+// Deleting a class removes its rates and takes it off the categories and
+// products it was assigned to. SaveRate inserts the rate or replaces the
+// one of the same class in the same region. FindRatesByRegion returns the
+// rate of every class that has one in the region.
+type TaxRepository interface {
+	CreateClass(class *models.TaxClass) error
+	FindClasses() ([]models.TaxClass, error)
+	FindClass(id uint) (*models.TaxClass, error)
+	UpdateClass(class *models.TaxClass) error
+	DeleteClass(id uint) error
+	SaveRate(rate *models.TaxRate) error
+	DeleteRate(classID uint, region string) error
+	FindRatesByRegion(region string) ([]models.TaxRate, error)
+}
+
+// ShippingRepository defines the storage operations for shipping zones
+// and their methods. Zones are returned with their methods, ordered by
+// ID. Deleting a zone deletes its methods. UpdateZone writes the name and
+// regions of a zone; UpdateMethod every field of a method but its zone.
+type ShippingRepository interface {
+	CreateZone(zone *models.ShippingZone) error
+	FindZones() ([]models.ShippingZone, error)
+	FindZone(id uint) (*models.ShippingZone, error)
+	UpdateZone(zone *models.ShippingZone) error
+	DeleteZone(id uint) error
+	CreateMethod(method *models.ShippingMethod) error
+	FindMethod(id uint) (*models.ShippingMethod, error)
+	UpdateMethod(method *models.ShippingMethod) error
+	DeleteMethod(id uint) error
+}
+
+// ReviewFilter narrows down the reviews ReviewRepository.Find returns.
+// Zero fields match every review.
+type ReviewFilter struct {
+	ProductID uint
+	Status    string
+}
+
+// ReviewRepository defines the storage operations for product reviews.
+// Create returns ErrDuplicate when the user already reviewed the product.
+// Find returns page (counted from 1) of the reviews matching the filter,
+// limit reviews to a page and newest first, and how many match in all.
+// Update writes the rating, title, body and status of a review; SetStatus
+// writes its status alone. Stats returns the number and the sum of the
+// ratings of a product's approved reviews.
+type ReviewRepository interface {
+	Create(review *models.Review) error
+	Find(filter ReviewFilter, page, limit int) ([]models.Review, int64, error)
+	FindByID(id uint) (*models.Review, error)
+	Update(review *models.Review) error
+	SetStatus(id uint, status string) error
+	Delete(id uint) error
+	Stats(productID uint) (int, int, error)
+}
+
+// WishlistRepository defines the storage operations for users' wishlists
+// and the products on them. Wishlists are returned with their items in
+// the order they were added, leaving out the items of products in the
+// trash; FindByUser orders a user's wishlists by ID. FindByShareToken only
+// finds a wishlist while it is shared. Update writes the name and share
+// token of a wishlist. Deleting a wishlist deletes its items. AddItem
+// returns ErrDuplicate when the product is already on the wishlist and
+// RemoveItem ErrNotFound when it is not.
+type WishlistRepository interface {
+	Create(wishlist *models.Wishlist) error
+	FindByUser(userID uint) ([]models.Wishlist, error)
+	FindByID(id uint) (*models.Wishlist, error)
+	FindByShareToken(token string) (*models.Wishlist, error)
+	Update(wishlist *models.Wishlist) error
+	Delete(id uint) error
+	AddItem(item *models.WishlistItem) error
+	RemoveItem(wishlistID, productID uint) error
+}
+
+// UserRepository defines the storage operations for users
+type UserRepository interface {
+	Create(user *models.User) error
+	FindAll() ([]models.User, error)
+	FindByID(id uint) (*models.User, error)
+	FindByEmail(email string) (*models.User, error)
+	Update(user *models.User) error
+	Delete(id uint, version uint) error
+	PurgeDeleted(before time.Time) (int64, error)
+}
+
+// Repositories groups every repository the handlers depend on
+type Repositories struct {
+	Products     ProductRepository
+	Categories   CategoryRepository
+	Variants     VariantRepository
+	Stock        StockMovementRepository
+	Reservations ReservationRepository
+	Warehouses   WarehouseRepository
+	Transfers    TransferRepository
+	Currencies   CurrencyRepository
+	PriceHistory PriceHistoryRepository
+	Schedules    PriceScheduleRepository
+	Discounts    DiscountRuleRepository
+	Coupons      CouponRepository
+	Carts        CartRepository
+	Orders       OrderRepository
+	Payments     PaymentRepository
+	Invoices     InvoiceRepository
+	Taxes        TaxRepository
+	Shipping     ShippingRepository
+	Reviews      ReviewRepository
+	Wishlists    WishlistRepository
+	Users        UserRepository
+
+	transact func(fn func(tx Repositories) error) error
+}
+
+// Transaction runs fn with repositories whose changes are committed
+// together if fn returns nil and rolled back otherwise.
+func (r Repositories) Transaction(fn func(tx Repositories) error) error {
+	return r.transact(fn)
+}
+
+// NewGormRepositories returns repositories backed by the given GORM connection
+func NewGormRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Products:     NewGormProductRepository(db),
+		Categories:   NewGormCategoryRepository(db),
+		Variants:     NewGormVariantRepository(db),
+		Stock:        NewGormStockMovementRepository(db),
+		Reservations: NewGormReservationRepository(db),
+		Warehouses:   NewGormWarehouseRepository(db),
+		Transfers:    NewGormTransferRepository(db),
+		Currencies:   NewGormCurrencyRepository(db),
+		PriceHistory: NewGormPriceHistoryRepository(db),
+		Schedules:    NewGormPriceScheduleRepository(db),
+		Discounts:    NewGormDiscountRuleRepository(db),
+		Coupons:      NewGormCouponRepository(db),
+		Carts:        NewGormCartRepository(db),
+		Orders:       NewGormOrderRepository(db),
+		Payments:     NewGormPaymentRepository(db),
+		Invoices:     NewGormInvoiceRepository(db),
+		Taxes:        NewGormTaxRepository(db),
+		Shipping:     NewGormShippingRepository(db),
+		Reviews:      NewGormReviewRepository(db),
+		Wishlists:    NewGormWishlistRepository(db),
+		Users:        NewGormUserRepository(db),
+
+		transact: func(fn func(tx Repositories) error) error {
+			return db.Transaction(func(tx *gorm.DB) error {
+				return fn(NewGormRepositories(tx))
+			})
+		},
+	}
+}
+
+// NewMemoryRepositories returns repositories that keep all data in memory
+func NewMemoryRepositories() Repositories {
+	return newMemoryRepositories(newMemoryStore())
+}
+
+func newMemoryRepositories(store *memoryStore) Repositories {
+	return Repositories{
+		Products:     &memoryProductRepository{store: store},
+		Categories:   &memoryCategoryRepository{store: store},
+		Variants:     &memoryVariantRepository{store: store},
+		Stock:        &memoryStockMovementRepository{store: store},
+		Reservations: &memoryReservationRepository{store: store},
+		Warehouses:   &memoryWarehouseRepository{store: store},
+		Transfers:    &memoryTransferRepository{store: store},
+		Currencies:   &memoryCurrencyRepository{store: store},
+		PriceHistory: &memoryPriceHistoryRepository{store: store},
+		Schedules:    &memoryPriceScheduleRepository{store: store},
+		Discounts:    &memoryDiscountRuleRepository{store: store},
+		Coupons:      &memoryCouponRepository{store: store},
+		Carts:        &memoryCartRepository{store: store},
+		Orders:       &memoryOrderRepository{store: store},
+		Payments:     &memoryPaymentRepository{store: store},
+		Invoices:     &memoryInvoiceRepository{store: store},
+		Taxes:        &memoryTaxRepository{store: store},
+		Shipping:     &memoryShippingRepository{store: store},
+		Reviews:      &memoryReviewRepository{store: store},
+		Wishlists:    &memoryWishlistRepository{store: store},
+		Users:        &memoryUserRepository{store: store},
+
+		transact: func(fn func(tx Repositories) error) error {
+			return store.transaction(func(tx *memoryStore) error {
+				return fn(newMemoryRepositories(tx))
+			})
+		},
+	}
+}
+
+// translateError maps GORM errors onto the repository errors
+func translateError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicate
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrInvalidReference
+	default:
+		return err
+	}
+}
+
+// versionedUpdate writes every field of value but its creation and
+// deletion times if the stored version still matches meta.Version, and
+// bumps meta.Version on success.
+func versionedUpdate(db *gorm.DB, value interface{}, meta *models.Model) error {
+	expected := meta.Version
+	meta.Version++
+
+	result := db.Model(value).
+		Where("version = ?", expected).
+		Select("*").Omit("created_at", "deleted_at", clause.Associations).
+		Updates(value)
+	if result.Error != nil {
+		meta.Version = expected
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		meta.Version = expected
+		return missingOrStale(db, value, meta.ID)
+	}
+	return nil
+}
+
+// versionedDelete deletes the record with the given ID. A non-zero
+// version must match the stored version.
+func versionedDelete(db *gorm.DB, value interface{}, id uint, version uint) error {
+	tx := db
+	if version != 0 {
+		tx = tx.Where("version = ?", version)
+	}
+
+	result := tx.Delete(value, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return missingOrStale(db, value, id)
+	}
+	return nil
+}
+
+// missingOrStale explains why a conditional write matched no rows
+func missingOrStale(db *gorm.DB, value interface{}, id uint) error {
+	var count int64
+	if err := db.Model(value).Where("id = ?", id).Count(&count).Error; err != nil {
+		return translateError(err)
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return ErrVersionConflict
+}
@@ -1,35 +1,189 @@
 package routes
 
 import (
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/config"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/handlers"
 	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/middlewares"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/payment"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/repository"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func AppRoutes(app *fiber.App) {
+func AppRoutes(app *fiber.App, repos repository.Repositories) {
+	userHandler := handlers.NewUserHandler(repos)
+	productHandler := handlers.NewProductHandler(repos, config.CurrencyCfg(), config.TaxCfg())
+	categoryHandler := handlers.NewCategoryHandler(repos)
+	variantHandler := handlers.NewVariantHandler(repos)
+	stockHandler := handlers.NewStockHandler(repos)
+	reservationHandler := handlers.NewReservationHandler(repos, config.ReservationCfg())
+	warehouseHandler := handlers.NewWarehouseHandler(repos)
+	currencyHandler := handlers.NewCurrencyHandler(repos, config.CurrencyCfg())
+	priceHandler := handlers.NewPriceHandler(repos)
+	discountHandler := handlers.NewDiscountHandler(repos, config.CurrencyCfg())
+	couponHandler := handlers.NewCouponHandler(repos, config.CurrencyCfg())
+	cartHandler := handlers.NewCartHandler(repos, config.CurrencyCfg(), config.TaxCfg())
+	taxHandler := handlers.NewTaxHandler(repos)
+	shippingHandler := handlers.NewShippingHandler(repos, config.CurrencyCfg(), config.TaxCfg())
+	reviewHandler := handlers.NewReviewHandler(repos)
+	wishlistHandler := handlers.NewWishlistHandler(repos)
+
+	paymentCfg := config.PaymentCfg()
+	paymentProvider := payment.NewMockProvider(paymentCfg.WebhookSecret, paymentCfg.WebhookURL)
+	orderHandler := handlers.NewOrderHandler(repos, config.CurrencyCfg(), config.TaxCfg(), paymentProvider)
+	paymentHandler := handlers.NewPaymentHandler(repos, paymentProvider)
+	invoiceHandler := handlers.NewInvoiceHandler(repos, config.CompanyCfg())
+
 	// User routes
-	app.Post("/api/users", handlers.CreateUser)
-	app.Get("/api/users", handlers.GetAllUsers)
-	app.Get("/api/users/:id", handlers.GetUser)
-	app.Patch("/api/users/:id", handlers.UpdateUser)
-	app.Delete("/api/users/:id", handlers.DeleteUser)
+	app.Post("/api/users", userHandler.CreateUser)
+	app.Get("/api/users", userHandler.GetAllUsers)
+	app.Get("/api/users/:id", userHandler.GetUser)
+	app.Patch("/api/users/:id", middlewares.Protected(), userHandler.UpdateUser)
+	app.Delete("/api/users/:id", middlewares.Protected(), userHandler.DeleteUser)
 
 	// Auth routes
-	app.Post("/api/login", handlers.Login)
+	app.Post("/api/login", userHandler.Login)
 
 	// Product routes
-	app.Post("/api/product", middlewares.Protected(), handlers.CreateProduct)
-	app.Get("/api/products", handlers.GetAllProducts)
-	app.Get("/api/product/:id", handlers.GetProduct)
-	app.Patch("/api/product/:id", middlewares.Protected(), handlers.UpdateProduct)
-	app.Delete("/api/product/:id", middlewares.Protected(), handlers.DeleteProduct)
+	app.Post("/api/product", middlewares.Protected(), productHandler.CreateProduct)
+	app.Get("/api/products", productHandler.GetAllProducts)
+	app.Get("/api/products/trash", middlewares.Protected(), productHandler.GetTrashedProducts)
+	app.Get("/api/product/:id", productHandler.GetProduct)
+	app.Patch("/api/product/:id", middlewares.Protected(), productHandler.UpdateProduct)
+	app.Delete("/api/product/:id", middlewares.Protected(), productHandler.DeleteProduct)
+	app.Post("/api/product/:id/restore", middlewares.Protected(), productHandler.RestoreProduct)
+
+	// Review routes
+	app.Post("/api/product/:id/reviews", middlewares.Protected(), reviewHandler.CreateReview)
+	app.Get("/api/product/:id/reviews", reviewHandler.GetProductReviews)
+	app.Get("/api/reviews", middlewares.Protected(), middlewares.Admin(), reviewHandler.GetReviews)
+	app.Patch("/api/review/:id", middlewares.Protected(), reviewHandler.UpdateReview)
+	app.Delete("/api/review/:id", middlewares.Protected(), reviewHandler.DeleteReview)
+	app.Put("/api/review/:id/status", middlewares.Protected(), middlewares.Admin(), reviewHandler.ModerateReview)
+
+	// Variant routes
+	app.Post("/api/product/:id/variants", middlewares.Protected(), variantHandler.CreateVariant)
+	app.Get("/api/product/:id/variants", variantHandler.GetVariants)
+	app.Get("/api/product/:id/variants/:variantId", variantHandler.GetVariant)
+	app.Patch("/api/product/:id/variants/:variantId", middlewares.Protected(), variantHandler.UpdateVariant)
+	app.Delete("/api/product/:id/variants/:variantId", middlewares.Protected(), variantHandler.DeleteVariant)
+
+	// Stock routes
+	app.Post("/api/product/:id/stock-movements", middlewares.Protected(), stockHandler.CreateStockMovement)
+	app.Get("/api/product/:id/stock-movements", middlewares.Protected(), stockHandler.GetStockMovements)
+
+	// Reservation routes
+	app.Post("/api/product/:id/reservations", middlewares.Protected(), reservationHandler.CreateReservation)
+	app.Get("/api/reservations/:id", middlewares.Protected(), reservationHandler.GetReservation)
+	app.Post("/api/reservations/:id/confirm", middlewares.Protected(), reservationHandler.ConfirmReservation)
+	app.Post("/api/reservations/:id/release", middlewares.Protected(), reservationHandler.ReleaseReservation)
+
+	// Warehouse routes
+	app.Post("/api/warehouse", middlewares.Protected(), warehouseHandler.CreateWarehouse)
+	app.Get("/api/warehouses", warehouseHandler.GetAllWarehouses)
+	app.Get("/api/warehouse/:id", warehouseHandler.GetWarehouse)
+	app.Patch("/api/warehouse/:id", middlewares.Protected(), warehouseHandler.UpdateWarehouse)
+	app.Delete("/api/warehouse/:id", middlewares.Protected(), warehouseHandler.DeleteWarehouse)
+
+	// Transfer routes
+	app.Post("/api/transfers", middlewares.Protected(), warehouseHandler.CreateTransfer)
+	app.Get("/api/transfers/:id", middlewares.Protected(), warehouseHandler.GetTransfer)
+	app.Get("/api/product/:id/transfers", middlewares.Protected(), warehouseHandler.GetProductTransfers)
+
+	// Currency routes
+	app.Get("/api/exchange-rates", currencyHandler.GetExchangeRates)
+	app.Put("/api/exchange-rates/:currency", middlewares.Protected(), middlewares.Admin(), currencyHandler.PutExchangeRate)
+	app.Delete("/api/exchange-rates/:currency", middlewares.Protected(), middlewares.Admin(), currencyHandler.DeleteExchangeRate)
+	app.Get("/api/product/:id/price-overrides", currencyHandler.GetPriceOverrides)
+	app.Put("/api/product/:id/price-overrides/:currency", middlewares.Protected(), currencyHandler.PutPriceOverride)
+	app.Delete("/api/product/:id/price-overrides/:currency", middlewares.Protected(), currencyHandler.DeletePriceOverride)
+
+	// Price routes
+	app.Get("/api/product/:id/prices", priceHandler.GetPriceHistory)
+	app.Get("/api/product/:id/price", priceHandler.GetEffectivePrice)
+	app.Post("/api/product/:id/scheduled-prices", middlewares.Protected(), priceHandler.CreateScheduledPrice)
+	app.Get("/api/product/:id/scheduled-prices", middlewares.Protected(), priceHandler.GetScheduledPrices)
+	app.Delete("/api/product/:id/scheduled-prices/:scheduleId", middlewares.Protected(), priceHandler.CancelScheduledPrice)
+
+	// Discount routes
+	app.Post("/api/discount-rule", middlewares.Protected(), middlewares.Admin(), discountHandler.CreateDiscountRule)
+	app.Get("/api/discount-rules", discountHandler.GetDiscountRules)
+	app.Get("/api/discount-rule/:id", discountHandler.GetDiscountRule)
+	app.Patch("/api/discount-rule/:id", middlewares.Protected(), middlewares.Admin(), discountHandler.UpdateDiscountRule)
+	app.Delete("/api/discount-rule/:id", middlewares.Protected(), middlewares.Admin(), discountHandler.DeleteDiscountRule)
+
+	// Coupon routes
+	app.Post("/api/coupon", middlewares.Protected(), middlewares.Admin(), couponHandler.CreateCoupon)
+	app.Get("/api/coupons", middlewares.Protected(), middlewares.Admin(), couponHandler.GetCoupons)
+	app.Post("/api/coupons/validate", middlewares.Protected(), couponHandler.ValidateCoupon)
+	app.Get("/api/coupon/:id", middlewares.Protected(), middlewares.Admin(), couponHandler.GetCoupon)
+	app.Patch("/api/coupon/:id", middlewares.Protected(), middlewares.Admin(), couponHandler.UpdateCoupon)
+	app.Delete("/api/coupon/:id", middlewares.Protected(), middlewares.Admin(), couponHandler.DeleteCoupon)
+	app.Get("/api/coupon/:id/redemptions", middlewares.Protected(), middlewares.Admin(), couponHandler.GetCouponRedemptions)
+
+	// Tax routes
+	app.Post("/api/tax-class", middlewares.Protected(), middlewares.Admin(), taxHandler.CreateTaxClass)
+	app.Get("/api/tax-classes", taxHandler.GetTaxClasses)
+	app.Get("/api/tax-class/:id", taxHandler.GetTaxClass)
+	app.Patch("/api/tax-class/:id", middlewares.Protected(), middlewares.Admin(), taxHandler.UpdateTaxClass)
+	app.Delete("/api/tax-class/:id", middlewares.Protected(), middlewares.Admin(), taxHandler.DeleteTaxClass)
+	app.Put("/api/tax-class/:id/rates/:region", middlewares.Protected(), middlewares.Admin(), taxHandler.PutTaxRate)
+	app.Delete("/api/tax-class/:id/rates/:region", middlewares.Protected(), middlewares.Admin(), taxHandler.DeleteTaxRate)
+
+	// Shipping routes
+	app.Post("/api/shipping-zone", middlewares.Protected(), middlewares.Admin(), shippingHandler.CreateShippingZone)
+	app.Get("/api/shipping-zones", shippingHandler.GetShippingZones)
+	app.Get("/api/shipping-zone/:id", shippingHandler.GetShippingZone)
+	app.Patch("/api/shipping-zone/:id", middlewares.Protected(), middlewares.Admin(), shippingHandler.UpdateShippingZone)
+	app.Delete("/api/shipping-zone/:id", middlewares.Protected(), middlewares.Admin(), shippingHandler.DeleteShippingZone)
+	app.Post("/api/shipping-zone/:id/methods", middlewares.Protected(), middlewares.Admin(), shippingHandler.CreateShippingMethod)
+	app.Patch("/api/shipping-zone/:id/methods/:methodId", middlewares.Protected(), middlewares.Admin(), shippingHandler.UpdateShippingMethod)
+	app.Delete("/api/shipping-zone/:id/methods/:methodId", middlewares.Protected(), middlewares.Admin(), shippingHandler.DeleteShippingMethod)
+	app.Post("/api/shipping/quote", middlewares.Protected(), shippingHandler.QuoteShipping)
+
+	// Cart routes
+	app.Get("/api/cart", middlewares.Protected(), cartHandler.GetCart)
+	app.Delete("/api/cart", middlewares.Protected(), cartHandler.ClearCart)
+	app.Post("/api/cart/items", middlewares.Protected(), cartHandler.AddCartItem)
+	app.Patch("/api/cart/items/:productId", middlewares.Protected(), cartHandler.UpdateCartItem)
+	app.Delete("/api/cart/items/:productId", middlewares.Protected(), cartHandler.DeleteCartItem)
+
+	// Wishlist routes
+	app.Post("/api/wishlist", middlewares.Protected(), wishlistHandler.CreateWishlist)
+	app.Get("/api/wishlists", middlewares.Protected(), wishlistHandler.GetWishlists)
+	app.Get("/api/wishlists/shared/:token", wishlistHandler.GetSharedWishlist)
+	app.Get("/api/wishlist/:id", middlewares.Protected(), wishlistHandler.GetWishlist)
+	app.Patch("/api/wishlist/:id", middlewares.Protected(), wishlistHandler.UpdateWishlist)
+	app.Delete("/api/wishlist/:id", middlewares.Protected(), wishlistHandler.DeleteWishlist)
+	app.Post("/api/wishlist/:id/items", middlewares.Protected(), wishlistHandler.AddWishlistItem)
+	app.Delete("/api/wishlist/:id/items/:productId", middlewares.Protected(), wishlistHandler.RemoveWishlistItem)
+	app.Post("/api/wishlist/:id/share", middlewares.Protected(), wishlistHandler.ShareWishlist)
+	app.Delete("/api/wishlist/:id/share", middlewares.Protected(), wishlistHandler.UnshareWishlist)
+
+	// Order routes
+	app.Post("/api/orders", middlewares.Protected(), orderHandler.CreateOrder)
+	app.Get("/api/orders", middlewares.Protected(), orderHandler.GetOrders)
+	app.Get("/api/orders/:id", middlewares.Protected(), orderHandler.GetOrder)
+	app.Get("/api/orders/:id/transitions", middlewares.Protected(), orderHandler.GetOrderTransitions)
+	app.Post("/api/orders/:id/transitions", middlewares.Protected(), middlewares.Admin(), orderHandler.TransitionOrder)
+
+	// Invoice routes
+	app.Get("/api/orders/:id/invoice.pdf", middlewares.Protected(), invoiceHandler.GetInvoicePDF)
+	app.Get("/api/orders/:id/invoice.html", middlewares.Protected(), invoiceHandler.GetInvoiceHTML)
+	app.Get("/api/orders/:id/packing-slip.pdf", middlewares.Protected(), invoiceHandler.GetPackingSlipPDF)
+	app.Get("/api/orders/:id/packing-slip.html", middlewares.Protected(), invoiceHandler.GetPackingSlipHTML)
+
+	// Payment routes
+	app.Post("/api/orders/:id/payments", middlewares.Protected(), paymentHandler.PayOrder)
+	app.Get("/api/orders/:id/payments", middlewares.Protected(), paymentHandler.GetOrderPayments)
+	app.Post("/api/payments/webhook", paymentHandler.HandleWebhook)
 
 	// Category routes
-	app.Post("/api/category", middlewares.Protected(), handlers.CreateCategory)
-	app.Get("/api/categories", handlers.GetAllCategories)
-	app.Get("/api/category/:id", handlers.GetCategory)
-	app.Patch("/api/category/:id", middlewares.Protected(), handlers.UpdateCategory)
-	app.Delete("/api/category/:id", middlewares.Protected(), handlers.DeleteCategory)
+	app.Post("/api/category", middlewares.Protected(), categoryHandler.CreateCategory)
+	app.Get("/api/categories", categoryHandler.GetAllCategories)
+	app.Get("/api/categories/tree", categoryHandler.GetCategoryTree)
+	app.Get("/api/category/:id", categoryHandler.GetCategory)
+	app.Patch("/api/category/:id", middlewares.Protected(), categoryHandler.UpdateCategory)
+	app.Delete("/api/category/:id", middlewares.Protected(), categoryHandler.DeleteCategory)
 
 }
@@ -0,0 +1,243 @@
+// Package shipping quotes what it costs to ship goods to a destination.
+// Every shipping method is priced by a rate calculator; the built-in ones
+// charge a flat rate, a rate by weight tier, or nothing over an order
+// value. Other kinds can be added with Register.
+package shipping
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/currency"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/decimal"
+	"github.com/santoadji21/santoadji21-go-fiber-product-api/pkg/models"
+)
+
+// VolumetricDivisor turns a volume in cubic centimetres into the weight
+// in kilograms that carriers charge bulky, light parcels as
+const VolumetricDivisor = 5000
+
+// weightDecimals rounds weights to the gram
+const weightDecimals = 3
+
+// ErrUnknownType is returned for a method whose type no calculator is
+// registered under
+var ErrUnknownType = errors.New("unknown shipping rate calculator")
+
+// Item is a number of units of one product being shipped. Weight is per
+// unit in kilograms and Length, Width and Height in centimetres.
+type Item struct {
+	Quantity int
+	Weight   decimal.Decimal
+	Length   decimal.Decimal
+	Width    decimal.Decimal
+	Height   decimal.Decimal
+}
+
+// ChargeableWeight returns what the units weigh for shipping: for each
+// unit the larger of its actual and its volumetric weight
+func (i Item) ChargeableWeight() decimal.Decimal {
+	volume := i.Length.Mul(i.Width).Mul(i.Height)
+	weight := volume.Div(decimal.NewFromInt(VolumetricDivisor), weightDecimals)
+	if i.Weight.GreaterThan(weight) {
+		weight = i.Weight
+	}
+	return weight.Mul(decimal.NewFromInt(int64(i.Quantity)))
+}
+
+// Parcel is what is being shipped: the chargeable weight of its items in
+// kilograms and what its goods are worth in Currency
+type Parcel struct {
+	Weight   decimal.Decimal
+	Value    decimal.Decimal
+	Currency string
+}
+
+// NewParcel packs items worth value in currency code into a parcel
+func NewParcel(items []Item, value decimal.Decimal, code string) Parcel {
+	weight := decimal.Zero
+	for _, item := range items {
+		weight = weight.Add(item.ChargeableWeight())
+	}
+	return Parcel{Weight: weight.Round(weightDecimals), Value: value, Currency: code}
+}
+
+// RateCalculator prices shipping a parcel, in the currency of the
+// parcel's value. It reports false when it cannot ship the parcel at all,
+// such as one heavier than its heaviest weight tier.
+type RateCalculator interface {
+	Rate(parcel Parcel) (decimal.Decimal, bool)
+}
+
+// FlatRate charges Price for any parcel
+type FlatRate struct {
+	Price decimal.Decimal
+}
+
+// Rate implements RateCalculator
+func (f FlatRate) Rate(Parcel) (decimal.Decimal, bool) {
+	return f.Price, true
+}
+
+// WeightTiers charges the price of the first tier the parcel's weight is
+// up to. Tiers are ordered lightest first.
+type WeightTiers struct {
+	Tiers []models.ShippingTier
+}
+
+// Rate implements RateCalculator
+func (w WeightTiers) Rate(parcel Parcel) (decimal.Decimal, bool) {
+	for _, tier := range w.Tiers {
+		if !parcel.Weight.GreaterThan(tier.UpTo) {
+			return tier.Price, true
+		}
+	}
+	return decimal.Zero, false
+}
+
+// FreeOver charges Price for parcels worth less than Threshold and
+// nothing for the others
+type FreeOver struct {
+	Threshold decimal.Decimal
+	Price     decimal.Decimal
+}
+
+// Rate implements RateCalculator
+func (f FreeOver) Rate(parcel Parcel) (decimal.Decimal, bool) {
+	if parcel.Value.LessThan(f.Threshold) {
+		return f.Price, true
+	}
+	return decimal.Zero, true
+}
+
+// Factory builds the calculator of a shipping method, or returns why the
+// method cannot be priced as it is
+type Factory func(method models.ShippingMethod) (RateCalculator, error)
+
+var factories = map[string]Factory{
+	models.ShippingFlat:       newFlatRate,
+	models.ShippingWeightTier: newWeightTiers,
+	models.ShippingFreeOver:   newFreeOver,
+}
+
+// Register makes a kind of calculator available to shipping methods of
+// the given type, replacing any registered before. It is meant to be
+// called from init functions, before any method is priced.
+func Register(kind string, factory Factory) {
+	factories[kind] = factory
+}
+
+// Types returns the kinds of calculator registered, in order
+func Types() []string {
+	kinds := make([]string, 0, len(factories))
+	for kind := range factories {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// New returns the calculator of method. It fails with ErrUnknownType for
+// a type nothing is registered under.
+func New(method models.ShippingMethod) (RateCalculator, error) {
+	factory, ok := factories[method.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: type must be one of %s", ErrUnknownType, strings.Join(Types(), ", "))
+	}
+	return factory(method)
+}
+
+func newFlatRate(method models.ShippingMethod) (RateCalculator, error) {
+	if method.Price.IsNegative() {
+		return nil, errors.New("price cannot be negative")
+	}
+	return FlatRate{Price: method.Price}, nil
+}
+
+func newWeightTiers(method models.ShippingMethod) (RateCalculator, error) {
+	if len(method.Tiers) == 0 {
+		return nil, errors.New("a weight_tier method needs at least one tier")
+	}
+	for i, tier := range method.Tiers {
+		if tier.UpTo.Sign() <= 0 {
+			return nil, errors.New("up_to must be positive")
+		}
+		if i > 0 && !tier.UpTo.GreaterThan(method.Tiers[i-1].UpTo) {
+			return nil, errors.New("tiers must be ordered by up_to, lightest first")
+		}
+		if tier.Price.IsNegative() {
+			return nil, errors.New("price cannot be negative")
+		}
+	}
+	return WeightTiers{Tiers: method.Tiers}, nil
+}
+
+func newFreeOver(method models.ShippingMethod) (RateCalculator, error) {
+	if method.Threshold == nil || method.Threshold.Sign() <= 0 {
+		return nil, errors.New("a free_over method needs a positive threshold")
+	}
+	if method.Price.IsNegative() {
+		return nil, errors.New("price cannot be negative")
+	}
+	return FreeOver{Threshold: *method.Threshold, Price: method.Price}, nil
+}
+
+// MatchZone returns the zone that covers region: the one listing region
+// itself or, failing that, the one listing its country, the part before
+// the first hyphen. It returns nil when no zone covers region.
+func MatchZone(zones []models.ShippingZone, region string) *models.ShippingZone {
+	country, _, _ := strings.Cut(region, "-")
+	var match *models.ShippingZone
+	for i := range zones {
+		if slices.Contains(zones[i].Regions, region) {
+			return &zones[i]
+		}
+		if match == nil && slices.Contains(zones[i].Regions, country) {
+			match = &zones[i]
+		}
+	}
+	return match
+}
+
+// Quote prices parcel with every method, in currency target. Methods
+// priced in another currency see the parcel's value in theirs. Methods
+// that cannot ship the parcel, or whose currency has no exchange rate
+// any more, are left out; the others are ordered cheapest first.
+func Quote(converter currency.Converter, methods []models.ShippingMethod, parcel Parcel, target string) ([]models.ShippingOption, error) {
+	options := []models.ShippingOption{}
+	for _, method := range methods {
+		if !converter.Supports(method.Currency) {
+			continue
+		}
+		calculator, err := New(method)
+		if err != nil {
+			return nil, fmt.Errorf("shipping method %d: %w", method.ID, err)
+		}
+
+		value, err := converter.Convert(parcel.Value, parcel.Currency, method.Currency)
+		if err != nil {
+			return nil, err
+		}
+		price, ok := calculator.Rate(Parcel{Weight: parcel.Weight, Value: value, Currency: method.Currency})
+		if !ok {
+			continue
+		}
+		if price, err = converter.Convert(price, method.Currency, target); err != nil {
+			return nil, err
+		}
+
+		options = append(options, models.ShippingOption{
+			MethodID: method.ID,
+			Name:     method.Name,
+			Type:     method.Type,
+			Price:    price,
+		})
+	}
+
+	slices.SortStableFunc(options, func(a, b models.ShippingOption) int {
+		return a.Price.Cmp(b.Price)
+	})
+	return options, nil
+}